@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"expvar"
 	"fmt"
 	"net/http"
 	"os"
@@ -15,13 +16,17 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"victa/internal/bot/bot_common"
+	"victa/internal/bot/notification_bot"
 	"victa/internal/bot/victa_bot"
 	"victa/internal/config"
 	"victa/internal/db"
 	"victa/internal/logger"
 	"victa/internal/repository/postgres"
+	"victa/internal/secret"
 	"victa/internal/service"
 	"victa/internal/webhook"
+	"victa/internal/webhook/webhook_common"
+	"victa/internal/worker"
 )
 
 func main() {
@@ -50,12 +55,75 @@ func run(logg logger.Logger) error {
 		_ = dbConn.Close()
 	}()
 
-	repos, err := initRepos(dbConn)
+	keys, err := secret.NewKeyring(cfg.IntegrationEncryptionKeys)
+	if err != nil {
+		return fmt.Errorf("integration encryption keys: %w", err)
+	}
+
+	repos, err := initRepos(dbConn, keys)
 	if err != nil {
 		return err
 	}
+
+	// секреты, сохранённые открытым текстом или старым ключом
+	if n, err := repos.Integration.Reencrypt(ctx); err != nil {
+		return fmt.Errorf("reencrypt integrations: %w", err)
+	} else if n > 0 {
+		logg.Info("перешифрованы секреты интеграций: %d", n)
+	}
 	services := initServices(cfg, repos)
 
+	botFactory := bot_common.NewBotFactory()
+	buildProgress := worker.NewBuildProgress(
+		botFactory,
+		logg,
+		services.Company,
+		services.App,
+		services.Codemagic,
+		services.BuildMessage,
+		services.Outbox,
+		services.Identity,
+	)
+	outbox := worker.NewOutbox(
+		botFactory,
+		logg,
+		services.Company,
+		services.Outbox,
+		services.BuildMessage,
+		services.Codemagic,
+		services.ArtifactFile,
+		services.Gitlab,
+	)
+	storeMonitor := worker.NewStoreMonitor(
+		botFactory,
+		logg,
+		services.Company,
+		services.App,
+		services.Store,
+		services.Outbox,
+		services.Identity,
+	)
+	janitor := worker.NewJanitor(logg, services.Delivery, services.WebhookArchive, services.PendingReply)
+	actions := notification_bot.NewActions(
+		logg,
+		services.Company,
+		services.User,
+		services.Identity,
+		services.Bugsnag,
+		services.Gitlab,
+		services.Codemagic,
+		services.PendingReply,
+	)
+	notificationUpdates := worker.NewNotificationUpdates(
+		botFactory,
+		logg,
+		services.Company,
+		actions,
+		cfg.TelegramToken,
+	)
+
+	handlers := initWebhookHandlers(logg, services, botFactory, buildProgress)
+
 	botBase, err := bot_common.NewBotFactory().GetBaseBot(cfg.TelegramToken, logg)
 	if err != nil {
 		return fmt.Errorf("init telegram bot: %w", err)
@@ -68,9 +136,20 @@ func run(logg logger.Logger) error {
 		services.Invite,
 		services.App,
 		services.JWT,
+		services.WebhookArchive,
+		services.Routing,
+		services.Identity,
+		services.Codemagic,
+		services.Store,
+		services.ChatState,
+		webhook.NewReplayer(services.WebhookArchive, handlers),
+		actions,
 	)
+	if cfg.TelegramUpdatesMode == config.TelegramUpdatesWebhook {
+		tgBot.WithWebhook(cfg.TelegramWebhookURL, cfg.TelegramWebhookSecret)
+	}
 
-	router := buildRouter(cfg, logg, services)
+	router := buildRouter(cfg, logg, services, handlers, tgBot)
 
 	srv := &http.Server{
 		Addr:         ":" + cfg.APIPort,
@@ -80,6 +159,18 @@ func run(logg logger.Logger) error {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// счётчики очереди обновлений бота и рантайма — только на внутреннем адресе
+	var debugSrv *http.Server
+	if cfg.DebugAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/debug/vars", expvar.Handler())
+		debugSrv = &http.Server{
+			Addr:        cfg.DebugAddr,
+			Handler:     mux,
+			ReadTimeout: 15 * time.Second,
+		}
+	}
+
 	g, gCtx := errgroup.WithContext(ctx)
 
 	g.Go(func() error {
@@ -90,6 +181,16 @@ func run(logg logger.Logger) error {
 		return nil
 	})
 
+	if debugSrv != nil {
+		g.Go(func() error {
+			logg.Info("debug‑метрики слушают %s", debugSrv.Addr)
+			if err := debugSrv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		})
+	}
+
 	botCtx, cancelBot := context.WithCancel(ctx)
 
 	g.Go(func() error {
@@ -97,11 +198,36 @@ func run(logg logger.Logger) error {
 		return tgBot.Run(botCtx)
 	})
 
+	// опросы и getUpdates должны идти на одной реплике, иначе уведомления
+	// задвоятся, а Telegram ответит 409 на второй getUpdates
+	g.Go(func() error {
+		return worker.NewSingleton(logg, services.Leader, "build_progress", buildProgress).Run(gCtx)
+	})
+
+	g.Go(func() error {
+		return outbox.Run(gCtx)
+	})
+
+	g.Go(func() error {
+		return worker.NewSingleton(logg, services.Leader, "store_monitor", storeMonitor).Run(gCtx)
+	})
+
+	g.Go(func() error {
+		return janitor.Run(gCtx)
+	})
+
+	g.Go(func() error {
+		return worker.NewSingleton(logg, services.Leader, "notification_updates", notificationUpdates).Run(gCtx)
+	})
+
 	g.Go(func() error {
 		<-gCtx.Done()
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		_ = srv.Shutdown(shutdownCtx)
+		if debugSrv != nil {
+			_ = debugSrv.Shutdown(shutdownCtx)
+		}
 		cancelBot()
 		return nil
 	})
@@ -121,14 +247,26 @@ func initDB(ctx context.Context, dsn string) (*sql.DB, error) {
 }
 
 type Repos struct {
-	User        *postgres.UserRepo
-	Company     *postgres.CompanyRepo
-	UserCompany *postgres.UserCompanyRepo
-	Integration *postgres.CompanyIntegrationRepo
-	App         *postgres.AppRepo
+	User           *postgres.UserRepo
+	Company        *postgres.CompanyRepo
+	UserCompany    *postgres.UserCompanyRepo
+	Integration    *postgres.CompanyIntegrationRepo
+	App            *postgres.AppRepo
+	AppIntegration *postgres.AppIntegrationRepo
+	BuildMessage   *postgres.BuildMessageRepo
+	Outbox         *postgres.NotificationOutboxRepo
+	DeliveryKey    *postgres.WebhookDeliveryKeyRepo
+	Delivery       *postgres.WebhookDeliveryRepo
+	RoutingRule    *postgres.RoutingRuleRepo
+	UserIdentity   *postgres.UserIdentityRepo
+	ArtifactFile   *postgres.ArtifactFileRepo
+	AppStoreState  *postgres.AppStoreStateRepo
+	ChatState      *postgres.ChatStateRepo
+	PendingReply   *postgres.PendingReplyRepo
+	LeaderLock     *postgres.LeaderLockRepo
 }
 
-func initRepos(conn *sql.DB) (Repos, error) {
+func initRepos(conn *sql.DB, keys *secret.Keyring) (Repos, error) {
 	must := func(v any, err error) (any, error) {
 		if err != nil {
 			return nil, err
@@ -148,7 +286,7 @@ func initRepos(conn *sql.DB) (Repos, error) {
 	if err != nil {
 		return Repos{}, err
 	}
-	integration, err := must(postgres.NewCompanyIntegrationRepo(conn))
+	integration, err := must(postgres.NewCompanyIntegrationRepo(conn, keys))
 	if err != nil {
 		return Repos{}, err
 	}
@@ -156,37 +294,163 @@ func initRepos(conn *sql.DB) (Repos, error) {
 	if err != nil {
 		return Repos{}, err
 	}
+	appIntegration, err := must(postgres.NewAppIntegrationRepo(conn))
+	if err != nil {
+		return Repos{}, err
+	}
+	buildMessage, err := must(postgres.NewBuildMessageRepo(conn))
+	if err != nil {
+		return Repos{}, err
+	}
+	outbox, err := must(postgres.NewNotificationOutboxRepo(conn))
+	if err != nil {
+		return Repos{}, err
+	}
+	deliveryKey, err := must(postgres.NewWebhookDeliveryKeyRepo(conn))
+	if err != nil {
+		return Repos{}, err
+	}
+	delivery, err := must(postgres.NewWebhookDeliveryRepo(conn))
+	if err != nil {
+		return Repos{}, err
+	}
+
+	routingRule, err := must(postgres.NewRoutingRuleRepo(conn))
+	if err != nil {
+		return Repos{}, err
+	}
+
+	userIdentity, err := must(postgres.NewUserIdentityRepo(conn))
+	if err != nil {
+		return Repos{}, err
+	}
+
+	artifactFile, err := must(postgres.NewArtifactFileRepo(conn))
+	if err != nil {
+		return Repos{}, err
+	}
+
+	appStoreState, err := must(postgres.NewAppStoreStateRepo(conn))
+	if err != nil {
+		return Repos{}, err
+	}
+
+	chatState, err := must(postgres.NewChatStateRepo(conn))
+	if err != nil {
+		return Repos{}, err
+	}
+
+	pendingReply, err := must(postgres.NewPendingReplyRepo(conn))
+	if err != nil {
+		return Repos{}, err
+	}
+
+	leaderLock, err := must(postgres.NewLeaderLockRepo(conn))
+	if err != nil {
+		return Repos{}, err
+	}
 
 	return Repos{
-		User:        user.(*postgres.UserRepo),
-		Company:     company.(*postgres.CompanyRepo),
-		UserCompany: userCompany.(*postgres.UserCompanyRepo),
-		Integration: integration.(*postgres.CompanyIntegrationRepo),
-		App:         app.(*postgres.AppRepo),
+		User:           user.(*postgres.UserRepo),
+		Company:        company.(*postgres.CompanyRepo),
+		UserCompany:    userCompany.(*postgres.UserCompanyRepo),
+		Integration:    integration.(*postgres.CompanyIntegrationRepo),
+		App:            app.(*postgres.AppRepo),
+		AppIntegration: appIntegration.(*postgres.AppIntegrationRepo),
+		BuildMessage:   buildMessage.(*postgres.BuildMessageRepo),
+		Outbox:         outbox.(*postgres.NotificationOutboxRepo),
+		DeliveryKey:    deliveryKey.(*postgres.WebhookDeliveryKeyRepo),
+		Delivery:       delivery.(*postgres.WebhookDeliveryRepo),
+		RoutingRule:    routingRule.(*postgres.RoutingRuleRepo),
+		UserIdentity:   userIdentity.(*postgres.UserIdentityRepo),
+		ArtifactFile:   artifactFile.(*postgres.ArtifactFileRepo),
+		AppStoreState:  appStoreState.(*postgres.AppStoreStateRepo),
+		ChatState:      chatState.(*postgres.ChatStateRepo),
+		PendingReply:   pendingReply.(*postgres.PendingReplyRepo),
+		LeaderLock:     leaderLock.(*postgres.LeaderLockRepo),
 	}, nil
 }
 
 type Services struct {
-	User      *service.UserService
-	Company   *service.CompanyService
-	Invite    *service.InviteService
-	App       *service.AppService
-	JWT       *service.JWTService
-	Codemagic *service.CodemagicService
+	User           *service.UserService
+	Company        *service.CompanyService
+	Invite         *service.InviteService
+	App            *service.AppService
+	JWT            *service.JWTService
+	Codemagic      *service.CodemagicService
+	Gitlab         *service.GitlabService
+	Bugsnag        *service.BugsnagService
+	BuildMessage   *service.BuildMessageService
+	Outbox         *service.OutboxService
+	Delivery       *service.DeliveryService
+	WebhookArchive *service.WebhookArchiveService
+	Routing        *service.RoutingService
+	Identity       *service.IdentityService
+	ArtifactFile   *service.ArtifactFileService
+	Store          *service.StoreService
+	ChatState      *service.ChatStateService
+	PendingReply   *service.PendingReplyService
+	Leader         *service.LeaderService
 }
 
 func initServices(cfg *config.Config, r Repos) Services {
 	return Services{
-		User:      service.NewUserService(r.User, r.UserCompany),
-		Company:   service.NewCompanyService(r.Company, r.Integration),
-		Invite:    service.NewInviteService([]byte(cfg.InviteSecret), 48*time.Hour),
-		App:       service.NewAppService(r.App),
-		JWT:       service.NewJWTService(cfg.JwtSecret),
-		Codemagic: service.NewCodemagicService(cfg.CodemagicAPIHost),
+		User:           service.NewUserService(r.User, r.UserCompany),
+		Company:        service.NewCompanyService(r.Company, r.Integration),
+		Invite:         service.NewInviteService([]byte(cfg.InviteSecret), 48*time.Hour),
+		App:            service.NewAppService(r.App, r.AppIntegration),
+		JWT:            service.NewJWTService(cfg.JwtSecret),
+		Codemagic:      service.NewCodemagicService(cfg.CodemagicAPIHost),
+		Gitlab:         service.NewGitlabService(),
+		Bugsnag:        service.NewBugsnagService(),
+		BuildMessage:   service.NewBuildMessageService(r.BuildMessage),
+		Outbox:         service.NewOutboxService(r.Outbox),
+		Delivery:       service.NewDeliveryService(r.DeliveryKey),
+		WebhookArchive: service.NewWebhookArchiveService(r.Delivery),
+		Routing:        service.NewRoutingService(r.RoutingRule),
+		Identity:       service.NewIdentityService(r.UserIdentity),
+		ArtifactFile:   service.NewArtifactFileService(r.ArtifactFile),
+		Store:          service.NewStoreService(r.AppStoreState),
+		ChatState:      service.NewChatStateService(r.ChatState),
+		PendingReply:   service.NewPendingReplyService(r.PendingReply),
+		Leader:         service.NewLeaderService(r.LeaderLock),
+	}
+}
+
+// initWebhookHandlers собирает обработчики вебхуков по имени провайдера.
+// Те же обработчики использует Replayer для повтора из архива.
+func initWebhookHandlers(
+	logg logger.Logger,
+	s Services,
+	botFactory *bot_common.BotFactory,
+	buildProgress *worker.BuildProgress,
+) map[string]gin.HandlerFunc {
+	return map[string]gin.HandlerFunc{
+		"codemagic": webhook.NewCodemagicWebhookHandler(
+			botFactory, logg, s.JWT, s.Outbox, s.Delivery, s.Routing, s.Identity, s.Company, s.App, s.Codemagic, buildProgress,
+		).Handle,
+		"gitlab": webhook.NewGitlabWebhookHandler(
+			botFactory, logg, s.JWT, s.Outbox, s.Delivery, s.Routing, s.Identity, s.Company, s.App,
+		).Handle,
+		"github": webhook.NewGithubWebhookHandler(
+			botFactory, logg, s.JWT, s.Outbox, s.Delivery, s.Routing, s.Identity, s.Company, s.App,
+		).Handle,
+		"bugsnag": webhook.NewBugsnagWebhookHandler(
+			botFactory, logg, s.JWT, s.Outbox, s.Delivery, s.Routing, s.Identity, s.Company, s.App,
+		).Handle,
+		"sentry": webhook.NewSentryWebhookHandler(
+			botFactory, logg, s.JWT, s.Outbox, s.Delivery, s.Routing, s.Identity, s.Company, s.App,
+		).Handle,
 	}
 }
 
-func buildRouter(cfg *config.Config, logg logger.Logger, s Services) *gin.Engine {
+func buildRouter(
+	cfg *config.Config,
+	logg logger.Logger,
+	s Services,
+	handlers map[string]gin.HandlerFunc,
+	tgBot *victa_bot.Bot,
+) *gin.Engine {
 	if cfg.ENV == "prod" || cfg.ENV == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	} else {
@@ -196,17 +460,21 @@ func buildRouter(cfg *config.Config, logg logger.Logger, s Services) *gin.Engine
 	r := gin.New()
 	r.Use(gin.Recovery())
 
-	botFactory := bot_common.NewBotFactory()
+	if cfg.TelegramUpdatesMode == config.TelegramUpdatesWebhook {
+		r.POST("/telegram/webhook", tgBot.WebhookHandler)
+	}
 
-	r.POST("/webhook/codemagic",
-		webhook.NewCodemagicWebhookHandler(botFactory, logg, s.JWT, s.Company, s.Codemagic).Handle,
-	)
-	r.POST("/webhook/gitlab",
-		webhook.NewGitlabWebhookHandler(botFactory, logg, s.JWT, s.Company).Handle,
-	)
-	r.POST("/webhook/bugsnag",
-		webhook.NewBugsnagWebhookHandler(botFactory, logg, s.JWT, s.Company).Handle,
-	)
+	archiver := webhook_common.NewArchiver(logg, s.WebhookArchive)
+
+	r.POST("/webhook/codemagic", archiver.Middleware("codemagic"), handlers["codemagic"])
+
+	// Вебхуки с подписью провайдера: компания берётся из пути,
+	// подлинность проверяется секретом из интеграции, а не токеном в URL.
+	for _, provider := range []string{"gitlab", "github", "bugsnag", "sentry"} {
+		path := "/webhook/" + provider
+		r.POST(path, archiver.Middleware(provider), handlers[provider])
+		r.POST(path+"/:company_id", archiver.Middleware(provider), handlers[provider])
+	}
 
 	return r
 }
@@ -1,6 +1,8 @@
 package bot_common
 
 import (
+	"strings"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"victa/internal/logger"
 )
@@ -49,19 +51,25 @@ func (b *BaseBot) SendMessage(config tgbotapi.MessageConfig) *tgbotapi.Message {
 }
 
 func (b *BaseBot) EditMessage(messageID int, config tgbotapi.MessageConfig) *tgbotapi.Message {
-	text := config.Text
-	replyMarkup, ok := config.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
-
-	if !ok {
-		return nil
-	}
-
-	editMsg := tgbotapi.NewEditMessageTextAndMarkup(config.ChatID, messageID, text, replyMarkup)
+	editMsg := tgbotapi.NewEditMessageText(config.ChatID, messageID, config.Text)
 	editMsg.ParseMode = config.ParseMode
+	editMsg.DisableWebPagePreview = config.DisableWebPagePreview
+
+	if config.ReplyMarkup != nil {
+		replyMarkup, ok := config.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+		if !ok {
+			return nil
+		}
+		editMsg.ReplyMarkup = &replyMarkup
+	}
 
 	msg, err := b.BotAPI.Send(editMsg)
 
 	if err != nil {
+		// Telegram отвечает ошибкой, если текст не изменился — сообщение при этом на месте.
+		if strings.Contains(err.Error(), "message is not modified") {
+			return &tgbotapi.Message{MessageID: messageID, Chat: &tgbotapi.Chat{ID: config.ChatID}}
+		}
 		b.Logger.Error(err.Error())
 		return nil
 	}
@@ -0,0 +1,212 @@
+package notification_bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf16"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"victa/internal/bot/bot_common"
+	"victa/internal/domain"
+	appErr "victa/internal/errors"
+	"victa/internal/logger"
+	"victa/internal/service"
+)
+
+// maxCallbackData — ограничение Telegram на callback_data в байтах.
+const maxCallbackData = 64
+
+// Actions обрабатывает нажатия кнопок под уведомлениями. Нажатия приходят
+// боту уведомлений компании (см. worker.NotificationUpdates), а если компания
+// шлёт уведомления основным ботом — victa_bot передаёт их сюда же.
+type Actions struct {
+	logger       logger.Logger
+	companySvc   *service.CompanyService
+	userSvc      *service.UserService
+	identitySvc  *service.IdentityService
+	bugsnagSvc   *service.BugsnagService
+	gitlabSvc    *service.GitlabService
+	codemagicSvc *service.CodemagicService
+	replySvc     *service.PendingReplyService
+}
+
+func NewActions(
+	logger logger.Logger,
+	companySvc *service.CompanyService,
+	userSvc *service.UserService,
+	identitySvc *service.IdentityService,
+	bugsnagSvc *service.BugsnagService,
+	gitlabSvc *service.GitlabService,
+	codemagicSvc *service.CodemagicService,
+	replySvc *service.PendingReplyService,
+) *Actions {
+	return &Actions{
+		logger:       logger,
+		companySvc:   companySvc,
+		userSvc:      userSvc,
+		identitySvc:  identitySvc,
+		bugsnagSvc:   bugsnagSvc,
+		gitlabSvc:    gitlabSvc,
+		codemagicSvc: codemagicSvc,
+		replySvc:     replySvc,
+	}
+}
+
+// Handles сообщает, относится ли callback к кнопкам уведомлений.
+func (a *Actions) Handles(data string) bool {
+	return strings.HasPrefix(data, bugsnagActionPrefix) ||
+		strings.HasPrefix(data, gitlabActionPrefix) ||
+		strings.HasPrefix(data, codemagicActionPrefix)
+}
+
+// HandleCallback выполняет действие и отвечает на нажатие.
+func (a *Actions) HandleCallback(ctx context.Context, base *bot_common.BaseBot, callback *tgbotapi.CallbackQuery) {
+	if callback.Message == nil {
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(callback.Data, bugsnagActionPrefix):
+		a.handleBugsnag(ctx, base, callback)
+	case strings.HasPrefix(callback.Data, gitlabActionPrefix):
+		a.handleGitlab(ctx, base, callback)
+	case strings.HasPrefix(callback.Data, codemagicActionPrefix):
+		a.handleCodemagic(ctx, base, callback)
+	}
+}
+
+// HandlesMessage сообщает, что сообщение — ответ на запрос комментария.
+func (a *Actions) HandlesMessage(ctx context.Context, message *tgbotapi.Message) bool {
+	if message.ReplyToMessage == nil || message.Text == "" || message.From == nil {
+		return false
+	}
+
+	r, err := a.replySvc.Get(ctx, message.Chat.ID, message.ReplyToMessage.MessageID)
+	if err != nil {
+		a.logger.Error("pending reply: %v", err)
+		return false
+	}
+	return r != nil && message.From.ID == r.TgUserID
+}
+
+// HandleMessage отправляет ответ пользователя комментарием в GitLab.
+func (a *Actions) HandleMessage(ctx context.Context, base *bot_common.BaseBot, message *tgbotapi.Message) {
+	if message.ReplyToMessage == nil {
+		return
+	}
+	reply, err := a.replySvc.Take(ctx, message.Chat.ID, message.ReplyToMessage.MessageID)
+	if err != nil {
+		a.logger.Error("pending reply: %v", err)
+		return
+	}
+	if reply == nil {
+		return
+	}
+	action, err := parseGitlabAction(reply.Action)
+	if err != nil {
+		a.logger.Error("pending reply: %v", err)
+		return
+	}
+	a.commentGitlabIssue(ctx, base, message, action, reply.BaseURL)
+}
+
+// authorize проверяет, что нажатие пришло боту уведомлений компании и
+// сделано её участником. При отказе возвращает текст для пользователя.
+func (a *Actions) authorize(
+	ctx context.Context,
+	base *bot_common.BaseBot,
+	tgUserID, companyID int64,
+) (*domain.CompanyIntegration, *domain.User, string) {
+	integration, err := a.companySvc.GetCompanyIntegrationByID(ctx, companyID)
+	if err != nil && !errors.Is(err, appErr.ErrIntegrationNotFound) {
+		a.logger.Error("notification action: %v", err)
+		return nil, nil, "Не удалось выполнить действие, попробуйте позже."
+	}
+	if integration == nil || integration.NotificationBotToken == nil ||
+		*integration.NotificationBotToken != base.BotAPI.Token {
+		return nil, nil, "Неверная команда."
+	}
+
+	user, err := a.userSvc.GetByTgID(ctx, tgUserID)
+	if errors.Is(err, appErr.ErrUserNotFound) {
+		return nil, nil, "Сначала зарегистрируйтесь в Victa."
+	}
+	if err != nil {
+		a.logger.Error("notification action: %v", err)
+		return nil, nil, "Не удалось выполнить действие, попробуйте позже."
+	}
+	if err := a.companySvc.CheckMember(ctx, user.ID, companyID); err != nil {
+		return nil, nil, "Вы не состоите в этой компании."
+	}
+
+	return integration, user, ""
+}
+
+// editLine меняет значение строки label в исходной карточке и, если
+// передана, клавиатуру. Текст приходит без HTML, поэтому правим его вместе
+// с entities, сдвигая их смещения.
+func (a *Actions) editLine(
+	base *bot_common.BaseBot,
+	message *tgbotapi.Message,
+	label, value string,
+	keyboard *tgbotapi.InlineKeyboardMarkup,
+) {
+	text, entities, ok := replaceLineValue(message.Text, message.Entities, label, value)
+	if !ok {
+		return
+	}
+	if keyboard == nil {
+		keyboard = message.ReplyMarkup
+	}
+
+	edit := tgbotapi.NewEditMessageText(message.Chat.ID, message.MessageID, text)
+	edit.Entities = entities
+	edit.DisableWebPagePreview = true
+	edit.ReplyMarkup = keyboard
+
+	if _, err := base.BotAPI.Send(edit); err != nil {
+		a.logger.Error("edit notification %d: %v", message.MessageID, err)
+	}
+}
+
+// replaceLineValue заменяет значение после label до конца строки. Смещения
+// entities в Telegram считаются в UTF‑16, поэтому и правим в UTF‑16.
+func replaceLineValue(
+	text string,
+	entities []tgbotapi.MessageEntity,
+	label, value string,
+) (string, []tgbotapi.MessageEntity, bool) {
+	idx := strings.Index(text, label)
+	if idx < 0 {
+		return text, entities, false
+	}
+	end := strings.IndexByte(text[idx:], '\n')
+	if end < 0 {
+		end = len(text)
+	} else {
+		end += idx
+	}
+	start := idx + len(label)
+	value = " " + value
+
+	from := len(utf16.Encode([]rune(text[:start])))
+	to := from + len(utf16.Encode([]rune(text[start:end])))
+	delta := len(utf16.Encode([]rune(value))) - (to - from)
+
+	out := make([]tgbotapi.MessageEntity, 0, len(entities))
+	for _, e := range entities {
+		switch {
+		case e.Offset >= to:
+			e.Offset += delta
+		case e.Offset >= from && e.Offset+e.Length <= to:
+			continue // entity целиком внутри заменённого значения
+		case e.Offset+e.Length > from:
+			e.Length += delta
+		}
+		out = append(out, e)
+	}
+
+	return text[:start] + value + text[end:], out, true
+}
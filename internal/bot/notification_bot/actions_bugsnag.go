@@ -0,0 +1,132 @@
+package notification_bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"victa/internal/bot/bot_common"
+	"victa/internal/domain"
+	"victa/internal/service"
+)
+
+// Кнопки под ошибками Bugsnag: «bsn:<op>:<company_id>:<project_id>:<error_id>».
+// Формат короткий, чтобы два 24‑символьных ID Bugsnag влезли в 64 байта.
+const (
+	bugsnagActionPrefix = "bsn:"
+
+	bugsnagOpSnooze byte = 's'
+	bugsnagOpIgnore byte = 'i'
+	bugsnagOpFix    byte = 'f'
+	bugsnagOpAssign byte = 'a'
+)
+
+type bugsnagAction struct {
+	Op        byte
+	CompanyID int64
+	ProjectID string
+	ErrorID   string
+}
+
+func (a bugsnagAction) String() string {
+	return fmt.Sprintf("%s%c:%d:%s:%s", bugsnagActionPrefix, a.Op, a.CompanyID, a.ProjectID, a.ErrorID)
+}
+
+func parseBugsnagAction(data string) (bugsnagAction, error) {
+	parts := strings.Split(strings.TrimPrefix(data, bugsnagActionPrefix), ":")
+	if len(parts) != 4 || len(parts[0]) != 1 || parts[2] == "" || parts[3] == "" {
+		return bugsnagAction{}, fmt.Errorf("malformed bugsnag action %q", data)
+	}
+	companyID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return bugsnagAction{}, fmt.Errorf("malformed bugsnag action %q: %w", data, err)
+	}
+	return bugsnagAction{
+		Op:        parts[0][0],
+		CompanyID: companyID,
+		ProjectID: parts[2],
+		ErrorID:   parts[3],
+	}, nil
+}
+
+var errBugsnagNotLinked = errors.New("bugsnag account is not linked")
+
+func (a *Actions) handleBugsnag(ctx context.Context, base *bot_common.BaseBot, callback *tgbotapi.CallbackQuery) {
+	action, err := parseBugsnagAction(callback.Data)
+	if err != nil {
+		a.logger.Warn("notification action: %v", err)
+		base.AnswerCallback(callback, "Неверная команда.")
+		return
+	}
+
+	integration, user, denied := a.authorize(ctx, base, callback.From.ID, action.CompanyID)
+	if denied != "" {
+		base.AnswerCallback(callback, denied)
+		return
+	}
+	if integration.BugsnagAPIToken == nil {
+		base.AnswerCallback(callback, "Токен Bugsnag API не настроен в интеграциях компании.")
+		return
+	}
+
+	status, err := a.applyBugsnag(ctx, *integration.BugsnagAPIToken, action, user)
+	if err != nil {
+		a.logger.Error("bugsnag action %c on %s: %v", action.Op, action.ErrorID, err)
+		base.AnswerCallback(callback, bugsnagActionError(err))
+		return
+	}
+
+	a.editLine(base, callback.Message, "• Статус:", status, nil)
+	base.AnswerCallback(callback, "Готово")
+}
+
+// applyBugsnag вызывает Bugsnag API и возвращает новый текст строки «Статус».
+func (a *Actions) applyBugsnag(ctx context.Context, token string, action bugsnagAction, user *domain.User) (string, error) {
+	switch action.Op {
+	case bugsnagOpSnooze:
+		if err := a.bugsnagSvc.SnoozeError(ctx, token, action.ProjectID, action.ErrorID); err != nil {
+			return "", err
+		}
+		hours := int(a.bugsnagSvc.SnoozePeriod().Hours())
+		return fmt.Sprintf("Отложена на %d ч (%s)", hours, user.Name), nil
+	case bugsnagOpIgnore:
+		if err := a.bugsnagSvc.IgnoreError(ctx, token, action.ProjectID, action.ErrorID); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Игнорирована (%s)", user.Name), nil
+	case bugsnagOpFix:
+		if err := a.bugsnagSvc.FixError(ctx, token, action.ProjectID, action.ErrorID); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Исправлена (%s)", user.Name), nil
+	case bugsnagOpAssign:
+		email, err := a.identitySvc.GetExternalID(ctx, user.ID, domain.IdentityProviderBugsnag)
+		if err != nil {
+			return "", err
+		}
+		if email == "" {
+			return "", errBugsnagNotLinked
+		}
+		if err := a.bugsnagSvc.AssignError(ctx, token, action.ProjectID, action.ErrorID, email); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Открыта, назначена на %s", user.Name), nil
+	default:
+		return "", fmt.Errorf("unknown bugsnag operation %q", action.Op)
+	}
+}
+
+func bugsnagActionError(err error) string {
+	switch {
+	case errors.Is(err, errBugsnagNotLinked):
+		return "Свяжите аккаунт Bugsnag в разделе «Мои аккаунты»."
+	case errors.Is(err, service.ErrBugsnagCollaboratorNotFound):
+		return "Ваш email Bugsnag не найден среди участников проекта."
+	default:
+		return "Bugsnag не принял изменение, подробности в логах."
+	}
+}
@@ -0,0 +1,64 @@
+package notification_bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"victa/internal/bot/bot_common"
+)
+
+// Кнопка под упавшей сборкой Codemagic: «cmr:<company_id>:<build_id>».
+const codemagicActionPrefix = "cmr:"
+
+type codemagicAction struct {
+	CompanyID int64
+	BuildID   string
+}
+
+func (a codemagicAction) String() string {
+	return fmt.Sprintf("%s%d:%s", codemagicActionPrefix, a.CompanyID, a.BuildID)
+}
+
+func parseCodemagicAction(data string) (codemagicAction, error) {
+	parts := strings.Split(strings.TrimPrefix(data, codemagicActionPrefix), ":")
+	if len(parts) != 2 || parts[1] == "" {
+		return codemagicAction{}, fmt.Errorf("malformed codemagic action %q", data)
+	}
+	companyID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return codemagicAction{}, fmt.Errorf("malformed codemagic action %q: %w", data, err)
+	}
+	return codemagicAction{CompanyID: companyID, BuildID: parts[1]}, nil
+}
+
+// handleCodemagic перезапускает упавшую сборку. Новая сборка пришлёт
+// свои уведомления через вебхук, поэтому карточку не трогаем.
+func (a *Actions) handleCodemagic(ctx context.Context, base *bot_common.BaseBot, callback *tgbotapi.CallbackQuery) {
+	action, err := parseCodemagicAction(callback.Data)
+	if err != nil {
+		a.logger.Warn("notification action: %v", err)
+		base.AnswerCallback(callback, "Неверная команда.")
+		return
+	}
+
+	integration, _, denied := a.authorize(ctx, base, callback.From.ID, action.CompanyID)
+	if denied != "" {
+		base.AnswerCallback(callback, denied)
+		return
+	}
+	if integration.CodemagicAPIKey == nil {
+		base.AnswerCallback(callback, "Ключ Codemagic API не настроен в интеграциях компании.")
+		return
+	}
+
+	if _, err := a.codemagicSvc.Rebuild(ctx, action.BuildID, *integration.CodemagicAPIKey); err != nil {
+		a.logger.Error("codemagic rebuild %s: %v", action.BuildID, err)
+		base.AnswerCallback(callback, "Codemagic не запустил сборку, подробности в логах.")
+		return
+	}
+	base.AnswerCallback(callback, "Сборка запущена")
+}
@@ -0,0 +1,238 @@
+package notification_bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"victa/internal/bot/bot_common"
+	"victa/internal/domain"
+	"victa/internal/service"
+)
+
+// Кнопки под задачами GitLab: «gli:<op>:<company_id>:<project_id>:<issue_iid>».
+const (
+	gitlabActionPrefix = "gli:"
+
+	gitlabOpClose   byte = 'c'
+	gitlabOpReopen  byte = 'r'
+	gitlabOpAssign  byte = 'a'
+	gitlabOpComment byte = 'm'
+)
+
+type gitlabAction struct {
+	Op        byte
+	CompanyID int64
+	ProjectID int64
+	IssueIID  int
+}
+
+func (a gitlabAction) String() string {
+	return fmt.Sprintf("%s%c:%d:%d:%d", gitlabActionPrefix, a.Op, a.CompanyID, a.ProjectID, a.IssueIID)
+}
+
+func parseGitlabAction(data string) (gitlabAction, error) {
+	parts := strings.Split(strings.TrimPrefix(data, gitlabActionPrefix), ":")
+	if len(parts) != 4 || len(parts[0]) != 1 {
+		return gitlabAction{}, fmt.Errorf("malformed gitlab action %q", data)
+	}
+	companyID, err1 := strconv.ParseInt(parts[1], 10, 64)
+	projectID, err2 := strconv.ParseInt(parts[2], 10, 64)
+	issueIID, err3 := strconv.Atoi(parts[3])
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return gitlabAction{}, fmt.Errorf("malformed gitlab action %q: %w", data, err)
+	}
+	return gitlabAction{
+		Op:        parts[0][0],
+		CompanyID: companyID,
+		ProjectID: projectID,
+		IssueIID:  issueIID,
+	}, nil
+}
+
+var errGitlabNotLinked = errors.New("gitlab account is not linked")
+
+func (a *Actions) handleGitlab(ctx context.Context, base *bot_common.BaseBot, callback *tgbotapi.CallbackQuery) {
+	action, err := parseGitlabAction(callback.Data)
+	if err != nil {
+		a.logger.Warn("notification action: %v", err)
+		base.AnswerCallback(callback, "Неверная команда.")
+		return
+	}
+
+	integration, user, denied := a.authorize(ctx, base, callback.From.ID, action.CompanyID)
+	if denied != "" {
+		base.AnswerCallback(callback, denied)
+		return
+	}
+	if integration.GitlabAPIToken == nil {
+		base.AnswerCallback(callback, "Токен GitLab API не настроен в интеграциях компании.")
+		return
+	}
+	token := *integration.GitlabAPIToken
+	baseURL := a.gitlabSvc.ResolveBaseURL(integration.GitlabBaseURL)
+
+	message := callback.Message
+	switch action.Op {
+	case gitlabOpClose, gitlabOpReopen:
+		stateEvent, state := "close", "closed"
+		if action.Op == gitlabOpReopen {
+			stateEvent, state = "reopen", "opened"
+		}
+		err = a.gitlabSvc.UpdateIssueState(ctx, baseURL, token, action.ProjectID, action.IssueIID, stateEvent)
+		if err == nil {
+			status := fmt.Sprintf("%s (%s)", ruIssueStatus[state], user.Name)
+			a.editLine(base, message, "• Статус:", status, toggleStateButton(message.ReplyMarkup, action))
+		}
+	case gitlabOpAssign:
+		var username string
+		username, err = a.identitySvc.GetExternalID(ctx, user.ID, domain.IdentityProviderGitlab)
+		if err == nil && username == "" {
+			err = errGitlabNotLinked
+		}
+		if err == nil {
+			err = a.gitlabSvc.AssignIssue(ctx, baseURL, token, action.ProjectID, action.IssueIID, username)
+		}
+		if err == nil {
+			a.editLine(base, message, "• Исполнители:", fmt.Sprintf("%s (@%s)", user.Name, username), nil)
+		}
+	case gitlabOpComment:
+		a.askComment(ctx, base, callback, action, baseURL, user)
+		return
+	default:
+		err = fmt.Errorf("unknown gitlab operation %q", action.Op)
+	}
+
+	if err != nil {
+		a.logger.Error("gitlab action %c on %d#%d: %v", action.Op, action.ProjectID, action.IssueIID, err)
+		base.AnswerCallback(callback, gitlabActionError(err))
+		return
+	}
+	base.AnswerCallback(callback, "Готово")
+}
+
+// askComment просит ответить на сообщение текстом комментария (ForceReply).
+func (a *Actions) askComment(
+	ctx context.Context,
+	base *bot_common.BaseBot,
+	callback *tgbotapi.CallbackQuery,
+	action gitlabAction,
+	baseURL string,
+	user *domain.User,
+) {
+	chatID := callback.Message.Chat.ID
+
+	prompt := base.NewHtmlMessage(chatID, fmt.Sprintf(
+		"💬 <a href=\"tg://user?id=%d\">%s</a>, ответьте на это сообщение текстом комментария к задаче #%d.",
+		callback.From.ID, html.EscapeString(user.Name), action.IssueIID,
+	))
+	prompt.ReplyToMessageID = callback.Message.MessageID
+	prompt.ReplyMarkup = tgbotapi.ForceReply{
+		ForceReply:            true,
+		Selective:             true,
+		InputFieldPlaceholder: "Комментарий",
+	}
+
+	sent := base.SendMessage(prompt)
+	if sent == nil {
+		base.AnswerCallback(callback, "Не удалось отправить запрос комментария.")
+		return
+	}
+
+	err := a.replySvc.Add(ctx, &domain.PendingReply{
+		ChatID:    chatID,
+		MessageID: sent.MessageID,
+		Action:    action.String(),
+		BaseURL:   baseURL,
+		TgUserID:  callback.From.ID,
+	})
+	if err != nil {
+		a.logger.Error("pending reply: %v", err)
+		base.DeleteMessage(chatID, sent.MessageID)
+		base.AnswerCallback(callback, "Не удалось отправить запрос комментария.")
+		return
+	}
+	base.AnswerCallback(callback, "")
+}
+
+// commentGitlabIssue публикует ответ пользователя комментарием к задаче.
+// Комментарий уходит от владельца токена, поэтому подписываем автора.
+func (a *Actions) commentGitlabIssue(
+	ctx context.Context,
+	base *bot_common.BaseBot,
+	message *tgbotapi.Message,
+	action gitlabAction,
+	baseURL string,
+) {
+	chatID := message.Chat.ID
+
+	integration, user, denied := a.authorize(ctx, base, message.From.ID, action.CompanyID)
+	if denied != "" {
+		base.SendMessage(base.NewHtmlMessage(chatID, html.EscapeString(denied)))
+		return
+	}
+	if integration.GitlabAPIToken == nil {
+		base.SendMessage(base.NewHtmlMessage(chatID, "Токен GitLab API не настроен в интеграциях компании."))
+		return
+	}
+
+	body := fmt.Sprintf("**%s** (через Telegram):\n\n%s", user.Name, message.Text)
+	err := a.gitlabSvc.CreateIssueNote(ctx, baseURL, *integration.GitlabAPIToken, action.ProjectID, action.IssueIID, body)
+
+	text := fmt.Sprintf("✅ Комментарий добавлен в задачу #%d", action.IssueIID)
+	if err != nil {
+		a.logger.Error("gitlab comment on %d#%d: %v", action.ProjectID, action.IssueIID, err)
+		text = gitlabActionError(err)
+	}
+
+	confirm := base.NewHtmlMessage(chatID, html.EscapeString(text))
+	confirm.ReplyToMessageID = message.MessageID
+	base.SendMessage(confirm)
+	base.DeleteMessage(chatID, message.ReplyToMessage.MessageID)
+}
+
+// toggleStateButton меняет «Закрыть» на «Открыть заново» и обратно.
+func toggleStateButton(markup *tgbotapi.InlineKeyboardMarkup, done gitlabAction) *tgbotapi.InlineKeyboardMarkup {
+	if markup == nil {
+		return nil
+	}
+
+	current := done.String()
+	state := "opened"
+	if done.Op == gitlabOpClose {
+		state = "closed"
+	}
+
+	out := tgbotapi.InlineKeyboardMarkup{InlineKeyboard: make([][]tgbotapi.InlineKeyboardButton, len(markup.InlineKeyboard))}
+	for i, row := range markup.InlineKeyboard {
+		out.InlineKeyboard[i] = make([]tgbotapi.InlineKeyboardButton, len(row))
+		for j, btn := range row {
+			if btn.CallbackData != nil && *btn.CallbackData == current {
+				b := gitlabStateButton(state, func(text string, op byte) domain.NotificationButton {
+					next := done
+					next.Op = op
+					return domain.NotificationButton{Text: text, CallbackData: next.String()}
+				})
+				btn = tgbotapi.NewInlineKeyboardButtonData(b.Text, b.CallbackData)
+			}
+			out.InlineKeyboard[i][j] = btn
+		}
+	}
+	return &out
+}
+
+func gitlabActionError(err error) string {
+	switch {
+	case errors.Is(err, errGitlabNotLinked):
+		return "Свяжите аккаунт GitLab в разделе «Мои аккаунты»."
+	case errors.Is(err, service.ErrGitlabUserNotFound):
+		return "Ваш логин GitLab не найден на инстансе."
+	default:
+		return "GitLab не принял изменение, подробности в логах."
+	}
+}
@@ -0,0 +1,48 @@
+package notification_bot
+
+import (
+	"context"
+	"fmt"
+
+	"victa/internal/domain"
+)
+
+// Identities находит Telegram‑пользователей компании по их учётным записям
+// во внешних сервисах.
+type Identities interface {
+	ResolveTgIDs(ctx context.Context, companyID int64, provider string, externalIDs []string) (map[string]string, error)
+}
+
+// WithIdentities включает упоминания связанных пользователей в уведомлениях.
+func (bot *Bot) WithIdentities(identities Identities) *Bot {
+	bot.identities = identities
+	return bot
+}
+
+// mentions возвращает tg_id по учётным записям провайдера. Без справочника
+// или при ошибке — пустой результат: уведомление уйдёт без упоминаний.
+func (bot *Bot) mentions(ctx context.Context, provider string, externalIDs ...string) map[string]string {
+	if bot.identities == nil || len(externalIDs) == 0 {
+		return nil
+	}
+
+	tgIDs, err := bot.identities.ResolveTgIDs(ctx, bot.companyID, provider, externalIDs)
+	if err != nil {
+		bot.Logger.Warn("resolve %s mentions: %v", provider, err)
+		return nil
+	}
+	return tgIDs
+}
+
+// mention — HTML‑упоминание пользователя, если учётная запись связана,
+// иначе просто экранированное имя.
+func (bot *Bot) mention(tgIDs map[string]string, provider, externalID, name string) string {
+	if name == "" {
+		name = externalID
+	}
+	tgID, ok := tgIDs[domain.NormalizeIdentity(provider, externalID)]
+	if !ok {
+		return bot.Escape(name)
+	}
+	return fmt.Sprintf("<a href=\"tg://user?id=%s\">%s</a>", bot.Escape(tgID), bot.Escape(name))
+}
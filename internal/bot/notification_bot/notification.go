@@ -14,6 +14,10 @@ import (
 type Bot struct {
 	*bot_common.BaseBot
 	chatID int64
+
+	companyID  int64
+	outbox     Outbox
+	identities Identities
 }
 
 // NewBot создаёт нового бота
@@ -32,6 +36,20 @@ func NewBot(
 	}, nil
 }
 
+// WithOutbox переводит бота на отправку через очередь: Send* только
+// сохраняют уведомление, доставкой занимается воркер.
+func (bot *Bot) WithOutbox(companyID int64, outbox Outbox) *Bot {
+	bot.companyID = companyID
+	bot.outbox = outbox
+	return bot
+}
+
+// CompanyID возвращает компанию, от имени которой бот ставит уведомления в очередь.
+func (bot *Bot) CompanyID() int64 { return bot.companyID }
+
+// ChatID возвращает чат бота в том виде, в каком он хранится в настройках.
+func (bot *Bot) ChatID() string { return strconv.FormatInt(bot.chatID, 10) }
+
 func (bot *Bot) Escape(s string) string { return html.EscapeString(s) }
 
 // MarkdownToHTML конвертит Markdown в HTML.
@@ -0,0 +1,146 @@
+package notification_bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/skip2/go-qrcode"
+
+	"victa/internal/domain"
+)
+
+// Outbox — очередь уведомлений, которую разбирает воркер доставки.
+type Outbox interface {
+	Enqueue(ctx context.Context, n *domain.Notification) error
+}
+
+// send ставит текст в очередь, а без очереди отправляет сразу.
+func (bot *Bot) send(ctx context.Context, text string) error {
+	return bot.enqueue(ctx, &domain.Notification{Text: text})
+}
+
+// sendWithButtons — то же, что send, но с inline‑кнопками под сообщением.
+func (bot *Bot) sendWithButtons(ctx context.Context, text string, buttons [][]domain.NotificationButton) error {
+	return bot.enqueue(ctx, &domain.Notification{Text: text, Buttons: buttons})
+}
+
+func (bot *Bot) enqueue(ctx context.Context, n *domain.Notification) error {
+	if bot.outbox == nil {
+		_, err := bot.Deliver(n.Text, 0, n.Buttons)
+		return err
+	}
+
+	n.CompanyID = bot.companyID
+	n.ChatID = strconv.FormatInt(bot.chatID, 10)
+	return bot.outbox.Enqueue(ctx, n)
+}
+
+// Deliver отправляет HTML‑сообщение или, если передан messageID,
+// редактирует уже отправленное. Если старое сообщение удалено,
+// отправляет новое. Возвращает ID сообщения и ошибку Telegram как есть,
+// чтобы воркер мог решить, повторять ли попытку.
+func (bot *Bot) Deliver(text string, messageID int, buttons [][]domain.NotificationButton) (int, error) {
+	keyboard := buildKeyboard(buttons)
+
+	if messageID != 0 {
+		edit := tgbotapi.NewEditMessageText(bot.chatID, messageID, text)
+		edit.ParseMode = tgbotapi.ModeHTML
+		edit.DisableWebPagePreview = true
+		edit.ReplyMarkup = keyboard
+
+		_, err := bot.BotAPI.Send(edit)
+		switch {
+		case err == nil:
+			return messageID, nil
+		case strings.Contains(err.Error(), "message is not modified"):
+			return messageID, nil
+		case !isMessageGone(err):
+			return 0, err
+		}
+	}
+
+	config := bot.NewHtmlMessage(bot.chatID, text)
+	if keyboard != nil {
+		config.ReplyMarkup = *keyboard
+	}
+
+	msg, err := bot.BotAPI.Send(config)
+	if err != nil {
+		return 0, fmt.Errorf("send message: %w", err)
+	}
+	return msg.MessageID, nil
+}
+
+// MaxDocumentSize — предел Bot API на загрузку файла ботом.
+const MaxDocumentSize int64 = 50 << 20
+
+// DeliverDocument отправляет файл ответом на сообщение replyTo и возвращает
+// file_id, по которому этот же бот может переслать файл без загрузки.
+func (bot *Bot) DeliverDocument(file tgbotapi.RequestFileData, replyTo int) (string, error) {
+	config := tgbotapi.NewDocument(bot.chatID, file)
+	config.ReplyToMessageID = replyTo
+	config.AllowSendingWithoutReply = true
+
+	msg, err := bot.BotAPI.Send(config)
+	if err != nil {
+		return "", fmt.Errorf("send document: %w", err)
+	}
+	if msg.Document == nil {
+		return "", errors.New("send document: no document in response")
+	}
+	return msg.Document.FileID, nil
+}
+
+// qrCodeSize — сторона PNG с QR‑кодом в пикселях.
+const qrCodeSize = 512
+
+// DeliverQRCode отправляет QR‑код со ссылкой url фото в ответ на replyTo.
+func (bot *Bot) DeliverQRCode(url, caption string, replyTo int) error {
+	png, err := qrcode.Encode(url, qrcode.Medium, qrCodeSize)
+	if err != nil {
+		return fmt.Errorf("encode qr code: %w", err)
+	}
+
+	config := tgbotapi.NewPhoto(bot.chatID, tgbotapi.FileBytes{Name: "qr.png", Bytes: png})
+	config.Caption = caption
+	config.ReplyToMessageID = replyTo
+	config.AllowSendingWithoutReply = true
+
+	if _, err := bot.BotAPI.Send(config); err != nil {
+		return fmt.Errorf("send qr code: %w", err)
+	}
+	return nil
+}
+
+// buildKeyboard переводит кнопки уведомления в разметку Telegram.
+func buildKeyboard(buttons [][]domain.NotificationButton) *tgbotapi.InlineKeyboardMarkup {
+	if len(buttons) == 0 {
+		return nil
+	}
+
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(buttons))
+	for _, row := range buttons {
+		keys := make([]tgbotapi.InlineKeyboardButton, 0, len(row))
+		for _, btn := range row {
+			keys = append(keys, tgbotapi.NewInlineKeyboardButtonData(btn.Text, btn.CallbackData))
+		}
+		rows = append(rows, keys)
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &keyboard
+}
+
+// isMessageGone — редактировать нечего: сообщение удалили или оно слишком старое.
+func isMessageGone(err error) bool {
+	var tgErr *tgbotapi.Error
+	if !errors.As(err, &tgErr) || tgErr.Code != 400 {
+		return false
+	}
+	return strings.Contains(tgErr.Message, "message to edit not found") ||
+		strings.Contains(tgErr.Message, "message can't be edited")
+}
@@ -1,18 +1,59 @@
 package notification_bot
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 	"victa/internal/domain"
 )
 
-func (bot *Bot) SendBugsnagNotification(w domain.BugsnagWebhook) {
-	text := bot.buildErrorText(w)
-	_ = bot.SendMessage(bot.NewHtmlMessage(bot.chatID, text))
+// SendBugsnagNotification отправляет карточку ошибки. С withActions под ней
+// появляются кнопки, меняющие статус ошибки через Bugsnag API (см. Actions).
+func (bot *Bot) SendBugsnagNotification(ctx context.Context, w domain.BugsnagWebhook, withActions bool) error {
+	var tgIDs map[string]string
+	if w.Error.AssignedTo != nil {
+		tgIDs = bot.mentions(ctx, domain.IdentityProviderBugsnag, w.Error.AssignedTo.Email)
+	}
+	text := bot.buildErrorText(w, tgIDs)
+	if !withActions {
+		return bot.send(ctx, text)
+	}
+	return bot.sendWithButtons(ctx, text, bot.buildErrorButtons(w))
+}
+
+// buildErrorButtons — Отложить / Игнорировать / Исправлена / Взять себе.
+// Если ID не влезают в callback_data, кнопок не будет.
+func (bot *Bot) buildErrorButtons(w domain.BugsnagWebhook) [][]domain.NotificationButton {
+	if w.Project.ID == "" || w.Error.ErrorID == "" {
+		return nil
+	}
+
+	button := func(text string, op byte) (domain.NotificationButton, bool) {
+		data := bugsnagAction{
+			Op:        op,
+			CompanyID: bot.companyID,
+			ProjectID: w.Project.ID,
+			ErrorID:   w.Error.ErrorID,
+		}.String()
+		return domain.NotificationButton{Text: text, CallbackData: data}, len(data) <= maxCallbackData
+	}
+
+	snooze, ok := button("💤 Отложить", bugsnagOpSnooze)
+	if !ok {
+		return nil
+	}
+	ignore, _ := button("🙈 Игнорировать", bugsnagOpIgnore)
+	fix, _ := button("✅ Исправлена", bugsnagOpFix)
+	assign, _ := button("🙋 Взять себе", bugsnagOpAssign)
+
+	return [][]domain.NotificationButton{
+		{snooze, ignore},
+		{fix, assign},
+	}
 }
 
-func (bot *Bot) buildErrorText(w domain.BugsnagWebhook) string {
+func (bot *Bot) buildErrorText(w domain.BugsnagWebhook, tgIDs map[string]string) string {
 	var b strings.Builder
 	b.Grow(512)
 
@@ -32,17 +73,18 @@ func (bot *Bot) buildErrorText(w domain.BugsnagWebhook) string {
 		fmt.Sprintf("<b>• Обработана:</b> %v", !w.Error.Unhandled),
 		fmt.Sprintf("<b>• User ID:</b> <code>%v</code>", w.Error.UserID),
 	}
+	if assignee := w.Error.AssignedTo; assignee != nil {
+		meta = append(meta, fmt.Sprintf("<b>• Ответственный:</b> %s",
+			bot.mention(tgIDs, domain.IdentityProviderBugsnag, assignee.Email, assignee.Name)))
+	}
 
 	for _, m := range meta {
 		b.WriteString(m + "\n")
 	}
 
-	b.WriteString(fmt.Sprintf("\n<i>️Текст ошибки:</i>\n<pre>%s</pre>",
-		bot.Escape(w.Error.Message)))
-
-	b.WriteString("\n\n<i>Информация об устройстве:</i>\n<blockquote expandable>")
+	bot.writeErrorMessage(&b, w.Error.Message)
 
-	deviceMeta := []string{
+	bot.writeDeviceInfo(&b, []string{
 		fmt.Sprintf("<b>• Устройство:</b> %s %s", bot.Escape(device.Manufacturer), bot.Escape(device.Model)),
 		fmt.Sprintf("<b>• OS:</b> %s %s", bot.Escape(device.OSName), bot.Escape(device.OSVersion)),
 		fmt.Sprintf("<b>• Locale:</b> %s", bot.Escape(device.Locale)),
@@ -53,37 +95,65 @@ func (bot *Bot) buildErrorText(w domain.BugsnagWebhook) string {
 		fmt.Sprintf("<b>• Disk:</b> %s свободно", bot.formatBytes(device.FreeDisk)),
 		fmt.Sprintf("<b>• Jailbreak:</b> %v", device.JailBroken),
 		fmt.Sprintf("<b>• Время на устройстве:</b> %s", device.Time.Format(time.RFC3339)),
+	})
+
+	var frames []stackFrame
+	for _, ex := range w.Error.Exceptions {
+		for _, frame := range ex.StackTrace {
+			frames = append(frames, stackFrame{
+				File:   frame.File,
+				Line:   frame.LineNumber,
+				Method: frame.Method,
+			})
+		}
 	}
+	bot.writeStackTrace(&b, frames)
+
+	bot.writeErrorLink(&b, w.Error.URL)
+
+	return b.String()
+}
+
+// stackFrame — строка стектрейса в формате, общем для всех трекеров ошибок.
+type stackFrame struct {
+	File   string
+	Line   string
+	Method string
+}
+
+func (bot *Bot) writeErrorMessage(b *strings.Builder, message string) {
+	b.WriteString(fmt.Sprintf("\n<i>️Текст ошибки:</i>\n<pre>%s</pre>",
+		bot.Escape(message)))
+}
+
+func (bot *Bot) writeDeviceInfo(b *strings.Builder, deviceMeta []string) {
+	b.WriteString("\n\n<i>Информация об устройстве:</i>\n<blockquote expandable>")
 
 	for _, m := range deviceMeta {
 		b.WriteString(m + "\n")
 	}
 
 	b.WriteString("</blockquote>")
+}
 
+func (bot *Bot) writeStackTrace(b *strings.Builder, frames []stackFrame) {
 	b.WriteString("\n\n<i>StackTrace:</i>\n<blockquote expandable>")
 
-	for _, ex := range w.Error.Exceptions {
-		if len(ex.StackTrace) == 0 {
-			continue
-		}
-
-		for _, frame := range ex.StackTrace {
-			b.WriteString(bot.Escape(
-				fmt.Sprintf("%s:%s — %s\n\n",
-					frame.File,
-					frame.LineNumber,
-					frame.Method,
-				)))
-		}
+	for _, frame := range frames {
+		b.WriteString(bot.Escape(
+			fmt.Sprintf("%s:%s — %s\n\n",
+				frame.File,
+				frame.Line,
+				frame.Method,
+			)))
 	}
 
 	b.WriteString("</blockquote>")
+}
 
+func (bot *Bot) writeErrorLink(b *strings.Builder, url string) {
 	b.WriteString(fmt.Sprintf("\n\n🔗 <b><a href=\"%s\">Информация об ошибке</a></b>",
-		bot.Escape(w.Error.URL)))
-
-	return b.String()
+		bot.Escape(url)))
 }
 
 func (bot *Bot) formatBytes(b int64) string {
@@ -1,6 +1,7 @@
 package notification_bot
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -9,30 +10,91 @@ import (
 )
 
 var ruBuildStatus = map[string]string{
+	"queued":     "Сборка в очереди",
+	"preparing":  "Подготовка к сборке",
+	"fetching":   "Загрузка исходников",
+	"building":   "Идёт сборка",
+	"testing":    "Идут тесты",
 	"publishing": "Сборка завершена",
 	"finished":   "Сборка завершена",
 	"success":    "Сборка завершена",
 	"cancel":     "Сборка отменена",
 	"canceled":   "Сборка отменена",
 	"failed":     "Ошибка при сборке",
+	"timeout":    "Превышено время сборки",
+	"skipped":    "Сборка пропущена",
 }
 
 var emojiByBuildStatus = map[string]string{
+	"queued":     "⏳",
+	"preparing":  "⏳",
+	"fetching":   "🔄",
+	"building":   "🔄",
+	"testing":    "🔄",
 	"publishing": "✅",
 	"finished":   "✅",
 	"success":    "✅",
 	"cancel":     "⚠️",
 	"canceled":   "⚠️",
 	"failed":     "❌",
+	"timeout":    "❌",
+	"skipped":    "⚠️",
 }
 
 var buildStepAlias = map[string]string{
 	"Set up code signing identities": "Set up code signing",
 }
 
-func (bot *Bot) SendDeployNotification(app domain.CodemagicApplication, build domain.CodemagicBuild) {
-	text := bot.buildDeployText(app, build)
-	_ = bot.SendMessage(bot.NewHtmlMessage(bot.chatID, text))
+// SendDeployNotification ставит в очередь сообщение о билде. docs —
+// артефакты, которые отправятся файлами в ответ на итоговое сообщение,
+// qrCodes — QR‑коды ссылок на артефакты, которые уйдут туда же фото.
+func (bot *Bot) SendDeployNotification(
+	ctx context.Context,
+	app domain.CodemagicApplication,
+	build domain.CodemagicBuild,
+	docs []domain.NotificationDocument,
+	qrCodes []domain.NotificationQRCode,
+) error {
+	// автора упоминаем, только когда сборка упала
+	var tgIDs map[string]string
+	if bot.isBuildFailed(build.Status) {
+		tgIDs = bot.mentions(ctx, domain.IdentityProviderGitEmail, build.Commit.AuthorEmail)
+	}
+	text := bot.buildDeployText(app, build, tgIDs)
+	return bot.enqueue(ctx, &domain.Notification{
+		Text:      text,
+		Buttons:   bot.buildDeployButtons(build),
+		Documents: docs,
+		QRCodes:   qrCodes,
+		Build: &domain.NotificationBuild{
+			BuildID:  build.ID,
+			AppID:    app.ID,
+			Status:   build.Status,
+			Finished: build.IsFinished(),
+		},
+	})
+}
+
+// buildDeployButtons — «Пересобрать» под упавшей сборкой (см. Actions).
+func (bot *Bot) buildDeployButtons(build domain.CodemagicBuild) [][]domain.NotificationButton {
+	if build.ID == "" || !bot.isBuildFailed(build.Status) {
+		return nil
+	}
+	data := codemagicAction{CompanyID: bot.companyID, BuildID: build.ID}.String()
+	if len(data) > maxCallbackData {
+		return nil
+	}
+	return [][]domain.NotificationButton{
+		{{Text: "🔁 Пересобрать", CallbackData: data}},
+	}
+}
+
+func (bot *Bot) isBuildFailed(status string) bool {
+	switch strings.ToLower(status) {
+	case "failed", "timeout":
+		return true
+	}
+	return false
 }
 
 func (bot *Bot) ruBuildStatus(en string) string {
@@ -56,9 +118,56 @@ func (bot *Bot) shortBuildStep(name string) string {
 	return name
 }
 
+// writeArtefacts выводит ссылки на артефакты, сгруппированные по платформам.
+// Ссылки есть только у видов, которыми компания делится в чатах.
+func (bot *Bot) writeArtefacts(b *strings.Builder, artefacts []domain.CodemagicArtefact) {
+	groups := make(map[string][]domain.CodemagicArtefact)
+	for _, art := range artefacts {
+		if art.PublicURL != "" {
+			groups[art.Platform()] = append(groups[art.Platform()], art)
+		}
+	}
+	if len(groups) == 0 {
+		return
+	}
+
+	b.WriteString("\n📦 <b>Артефакты:</b>\n")
+	for _, platform := range domain.ArtefactPlatforms {
+		list := groups[platform]
+		if len(list) == 0 {
+			continue
+		}
+		fmt.Fprintf(b, "<i>%s</i>\n", bot.Escape(platform))
+		for _, art := range list {
+			name := art.Name
+			if name == "" {
+				name = strings.ToUpper(art.Kind())
+			}
+			fmt.Fprintf(b, "• <a href=\"%s\">%s</a>", bot.Escape(art.PublicURL), bot.Escape(name))
+			if art.Size > 0 {
+				fmt.Fprintf(b, " (%s)", bot.formatSize(art.Size))
+			}
+			b.WriteString("\n")
+		}
+	}
+}
+
+// formatSize — размер файла в KB/MB.
+func (bot *Bot) formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
+
 func (bot *Bot) buildDeployText(
 	app domain.CodemagicApplication,
 	build domain.CodemagicBuild,
+	tgIDs map[string]string,
 ) string {
 	var b strings.Builder
 	b.Grow(512)
@@ -71,24 +180,24 @@ func (bot *Bot) buildDeployText(
 		bot.Escape(bot.buildStatusEmoji(build.Status)),
 	)
 
-	for _, art := range build.Artefacts {
-		if strings.EqualFold(art.Type, "apk") && art.PublicURL != "" {
-			fmt.Fprintf(
-				&b,
-				"\n📦 <b><a href=\"%s\">Скачать APK</a></b>\n",
-				bot.Escape(art.PublicURL),
-			)
-			break
+	bot.writeArtefacts(&b, build.Artefacts)
+
+	durationText := "—"
+	if !build.StartedAt.IsZero() {
+		var duration time.Duration
+		if build.FinishedAt.IsZero() {
+			duration = time.Since(build.StartedAt)
+		} else {
+			duration = build.FinishedAt.Sub(build.StartedAt)
 		}
+		durationText = duration.Round(time.Second).String()
 	}
 
-	var duration time.Duration
-	if build.FinishedAt.IsZero() {
-		duration = time.Since(build.StartedAt)
-	} else {
-		duration = build.FinishedAt.Sub(build.StartedAt)
+	branch := build.Commit.Branch
+	if branch == "" {
+		branch = build.Branch
 	}
-	duration = duration.Round(time.Second)
+
 	version := build.Version
 	if version == "" {
 		version = "Не определена"
@@ -96,15 +205,16 @@ func (bot *Bot) buildDeployText(
 
 	meta := []string{
 		fmt.Sprintf("\n<b>• Версия:</b> %s", bot.Escape(version)),
-		fmt.Sprintf("<b>• Время сборки:</b> %s", duration.String()),
+		fmt.Sprintf("<b>• Время сборки:</b> %s", durationText),
 
 		fmt.Sprintf("<b>• ID билда:</b> <code>%s</code>", bot.Escape(build.ID)),
 		fmt.Sprintf("<b>• Платформы:</b> %s", bot.Escape(strings.Join(build.Config.BuildSettings.Platforms, ", "))),
 		fmt.Sprintf("<b>• Версия Flutter:</b> %s", bot.Escape(build.Config.BuildSettings.FlutterVersion)),
 
-		fmt.Sprintf("<b>• Ветка:</b> %s", bot.Escape(build.Commit.Branch)),
+		fmt.Sprintf("<b>• Ветка:</b> %s", bot.Escape(branch)),
 		fmt.Sprintf("<b>• Коммит:</b> <code>%s</code>", bot.Escape(build.Commit.CommitMessage)),
-		fmt.Sprintf("<b>• Автор коммита:</b> %s", bot.Escape(build.Commit.AuthorName)),
+		fmt.Sprintf("<b>• Автор коммита:</b> %s",
+			bot.mention(tgIDs, domain.IdentityProviderGitEmail, build.Commit.AuthorEmail, build.Commit.AuthorName)),
 	}
 
 	for _, m := range meta {
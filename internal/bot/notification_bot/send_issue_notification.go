@@ -1,6 +1,7 @@
 package notification_bot
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"victa/internal/domain"
@@ -11,12 +12,48 @@ var ruIssueStatus = map[string]string{
 	"closed": "Закрыта",
 }
 
-func (bot *Bot) SendIssueNotification(issue domain.GitlabWebhook) {
-	text := bot.buildIssueText(issue)
-	_ = bot.SendMessage(bot.NewHtmlMessage(bot.chatID, text))
+// SendIssueNotification отправляет карточку задачи. С withActions под ней
+// появляются кнопки, работающие через API GitLab (см. Actions).
+func (bot *Bot) SendIssueNotification(ctx context.Context, issue domain.GitlabWebhook, withActions bool) error {
+	tgIDs := bot.mentions(ctx, domain.IdentityProviderGitlab, gitlabUsernames(issue.Assignees)...)
+	text := bot.buildIssueText(issue, tgIDs)
+	if !withActions {
+		return bot.send(ctx, text)
+	}
+	return bot.sendWithButtons(ctx, text, bot.buildIssueButtons(issue))
 }
 
-func (bot *Bot) buildIssueText(issue domain.GitlabWebhook) string {
+// buildIssueButtons — Закрыть или Открыть заново / Взять себе / Ответить.
+func (bot *Bot) buildIssueButtons(issue domain.GitlabWebhook) [][]domain.NotificationButton {
+	obj := bot.getIssueObject(issue)
+	if issue.Project.ID == 0 || obj.IID == 0 {
+		return nil
+	}
+
+	button := func(text string, op byte) domain.NotificationButton {
+		return domain.NotificationButton{Text: text, CallbackData: gitlabAction{
+			Op:        op,
+			CompanyID: bot.companyID,
+			ProjectID: issue.Project.ID,
+			IssueIID:  obj.IID,
+		}.String()}
+	}
+
+	return [][]domain.NotificationButton{
+		{gitlabStateButton(obj.State, button), button("🙋 Взять себе", gitlabOpAssign)},
+		{button("💬 Ответить", gitlabOpComment)},
+	}
+}
+
+// gitlabStateButton — «Закрыть» для открытой задачи, «Открыть заново» для закрытой.
+func gitlabStateButton(state string, button func(string, byte) domain.NotificationButton) domain.NotificationButton {
+	if strings.EqualFold(state, "closed") {
+		return button("🔓 Открыть заново", gitlabOpReopen)
+	}
+	return button("🔒 Закрыть", gitlabOpClose)
+}
+
+func (bot *Bot) buildIssueText(issue domain.GitlabWebhook, tgIDs map[string]string) string {
 	var b strings.Builder
 	b.Grow(512)
 
@@ -31,9 +68,15 @@ func (bot *Bot) buildIssueText(issue domain.GitlabWebhook) string {
 		projectURL, projectName, issueURL, issueIID,
 	)
 
+	// строку исполнителей выводим всегда: её правит кнопка «Взять себе»
+	assignees := "—"
+	if len(issue.Assignees) > 0 {
+		assignees = bot.joinGitlabUsers(issue.Assignees, tgIDs)
+	}
 	meta := []string{
 		fmt.Sprintf("\n<b>• Задача:</b> %s", bot.Escape(obj.Title)),
 		fmt.Sprintf("<b>• Статус:</b> %s", bot.Escape(obj.State)),
+		fmt.Sprintf("<b>• Исполнители:</b> %s", assignees),
 	}
 
 	for _, m := range meta {
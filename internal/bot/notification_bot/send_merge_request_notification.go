@@ -0,0 +1,202 @@
+package notification_bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"victa/internal/domain"
+)
+
+var ruMergeRequestStatus = map[string]string{
+	"opened": "Открыт",
+	"closed": "Закрыт",
+	"merged": "Влит",
+	"locked": "Заблокирован",
+}
+
+var ruDetailedMergeStatus = map[string]string{
+	"mergeable":                "Можно вливать",
+	"ci_still_running":         "Пайплайн выполняется",
+	"ci_must_pass":             "Ожидает успешного пайплайна",
+	"conflict":                 "Есть конфликты",
+	"not_approved":             "Требуется одобрение",
+	"draft_status":             "Черновик",
+	"discussions_not_resolved": "Есть нерешённые обсуждения",
+	"need_rebase":              "Требуется rebase",
+	"blocked_status":           "Заблокирован другим MR",
+	"checking":                 "Проверяется",
+	"unchecked":                "Проверяется",
+	"can_be_merged":            "Можно вливать",
+	"cannot_be_merged":         "Есть конфликты",
+}
+
+func (bot *Bot) SendMergeRequestNotification(ctx context.Context, mr domain.GitlabWebhook) error {
+	usernames := append(gitlabUsernames(mr.Assignees), gitlabUsernames(mr.Reviewers)...)
+	tgIDs := bot.mentions(ctx, domain.IdentityProviderGitlab, usernames...)
+	text := bot.buildMergeRequestText(mr, tgIDs)
+	return bot.send(ctx, text)
+}
+
+func (bot *Bot) buildMergeRequestText(mr domain.GitlabWebhook, tgIDs map[string]string) string {
+	var b strings.Builder
+	b.Grow(512)
+
+	obj := bot.getMergeRequestObject(mr)
+	projectName := bot.Escape(mr.Project.Name)
+	projectURL := bot.Escape(mr.Project.Homepage)
+	mrURL := bot.Escape(obj.URL)
+
+	fmt.Fprintf(&b,
+		"🔀 <b><a href=\"%s\">%s</a> | <a href=\"%s\">!%d</a></b>\n",
+		projectURL, projectName, mrURL, obj.IID,
+	)
+
+	meta := []string{
+		fmt.Sprintf("\n<b>• Merge request:</b> %s", bot.Escape(obj.Title)),
+		fmt.Sprintf("<b>• Ветки:</b> <code>%s</code> → <code>%s</code>",
+			bot.Escape(obj.SourceBranch), bot.Escape(obj.TargetBranch)),
+		fmt.Sprintf("<b>• Статус:</b> %s", bot.Escape(bot.ruMergeRequestStatus(obj))),
+	}
+
+	if status := bot.ruDetailedMergeStatus(obj); status != "" {
+		meta = append(meta, fmt.Sprintf("<b>• Готовность:</b> %s", bot.Escape(status)))
+	}
+	if obj.HeadPipelineID != nil {
+		pipelineURL := fmt.Sprintf("%s/-/pipelines/%d", mr.Project.Homepage, *obj.HeadPipelineID)
+		meta = append(meta, fmt.Sprintf("<b>• Пайплайн:</b> <a href=\"%s\">#%d</a>",
+			bot.Escape(pipelineURL), *obj.HeadPipelineID))
+	}
+	if len(mr.Assignees) > 0 {
+		meta = append(meta, fmt.Sprintf("<b>• Исполнители:</b> %s", bot.joinGitlabUsers(mr.Assignees, tgIDs)))
+	}
+	if len(mr.Reviewers) > 0 {
+		meta = append(meta, fmt.Sprintf("<b>• Ревьюеры:</b> %s", bot.joinGitlabUsers(mr.Reviewers, tgIDs)))
+	}
+
+	for _, m := range meta {
+		b.WriteString(m + "\n")
+	}
+
+	b.WriteString("\n")
+
+	switch mr.ObjectKind {
+	case "merge_request":
+		switch mr.ObjectAttributes.Action {
+		case "open":
+			b.WriteString("🚀 <b>Merge request открыт</b>")
+		case "reopen":
+			b.WriteString("🚀 <b>Merge request переоткрыт</b>")
+		case "close":
+			b.WriteString("🚫 <b>Merge request закрыт</b>")
+		case "merge":
+			b.WriteString("✅ <b>Merge request влит</b>")
+		case "approved", "approval":
+			b.WriteString("👍 <b>Merge request одобрен</b>")
+		case "unapproved", "unapproval":
+			b.WriteString("👎 <b>Одобрение отозвано</b>")
+		case "update":
+			b.WriteString("🔄 <b>Merge request обновлён</b>")
+		default:
+			fmt.Fprintf(&b, "<b>%s</b>", bot.Escape(mr.ObjectAttributes.Action))
+		}
+
+		fmt.Fprintf(&b,
+			"<i> by %s</i>\n\n",
+			bot.Escape(mr.User.Name),
+		)
+
+	case "note":
+		switch mr.ObjectAttributes.Action {
+		case "create":
+			b.WriteString("💬 <b>Новый комментарий</b>")
+		case "update":
+			b.WriteString("💬 <b>Комментарий отредактирован</b>")
+		default:
+			fmt.Fprintf(&b, "<b>%s</b>", bot.Escape(mr.ObjectAttributes.Action))
+		}
+
+		fmt.Fprintf(&b,
+			"<i> by %s</i>\n",
+			bot.Escape(mr.User.Name),
+		)
+
+		fmt.Fprintf(&b,
+			"🔗 <a href=\"%s\">Ссылка на комментарий</a>\n\n",
+			bot.Escape(mr.ObjectAttributes.URL),
+		)
+
+		comment := mr.ObjectAttributes.Description
+		if comment != "" {
+			fmt.Fprintf(&b,
+				"<i>️Текст комментария:</i>\n<blockquote expandable>%s</blockquote>\n\n",
+				bot.MarkdownToHTML(comment),
+			)
+		}
+	}
+
+	desc := bot.MarkdownToHTML(obj.Description)
+	if desc != "" {
+		fmt.Fprintf(&b,
+			"<i>Описание:</i>\n<blockquote expandable>%s</blockquote>\n",
+			desc,
+		)
+	}
+
+	return b.String()
+}
+
+func (bot *Bot) getMergeRequestObject(mr domain.GitlabWebhook) domain.Attributes {
+	if mr.ObjectKind == "note" {
+		return mr.MergeRequest
+	}
+	return mr.ObjectAttributes
+}
+
+func (bot *Bot) ruMergeRequestStatus(obj domain.Attributes) string {
+	status := obj.State
+	if v, ok := ruMergeRequestStatus[strings.ToLower(obj.State)]; ok {
+		status = v
+	}
+	if obj.Draft {
+		status += " (черновик)"
+	}
+	return status
+}
+
+func (bot *Bot) ruDetailedMergeStatus(obj domain.Attributes) string {
+	if obj.State != "opened" {
+		return ""
+	}
+	status := obj.DetailedMergeStatus
+	if status == "" {
+		status = obj.MergeStatus
+	}
+	if v, ok := ruDetailedMergeStatus[strings.ToLower(status)]; ok {
+		return v
+	}
+	return status
+}
+
+// joinGitlabUsers перечисляет пользователей через запятую (HTML);
+// связанные с Victa упоминаются в Telegram.
+func (bot *Bot) joinGitlabUsers(users []domain.GitlabUser, tgIDs map[string]string) string {
+	names := make([]string, 0, len(users))
+	for _, u := range users {
+		name := bot.mention(tgIDs, domain.IdentityProviderGitlab, u.Username, u.Name)
+		if u.Username != "" {
+			name = fmt.Sprintf("%s (@%s)", name, bot.Escape(u.Username))
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func gitlabUsernames(users []domain.GitlabUser) []string {
+	out := make([]string, 0, len(users))
+	for _, u := range users {
+		if u.Username != "" {
+			out = append(out, u.Username)
+		}
+	}
+	return out
+}
@@ -0,0 +1,294 @@
+package notification_bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"victa/internal/domain"
+)
+
+const (
+	// maxMessageLen — лимит Telegram на длину текста сообщения. Считаем
+	// по размеченному HTML: он не короче видимого текста, так что запас есть.
+	maxMessageLen = 4096
+	// maxCommitLen ограничивает сообщение коммита в карточке задачи.
+	maxCommitLen = 300
+)
+
+var ruPipelineStatus = map[string]string{
+	"success":  "Пайплайн завершён",
+	"failed":   "Ошибка в пайплайне",
+	"canceled": "Пайплайн отменён",
+	"running":  "Пайплайн выполняется",
+	"pending":  "Пайплайн ожидает",
+	"skipped":  "Пайплайн пропущен",
+	"manual":   "Ожидает ручного запуска",
+}
+
+var emojiByPipelineStatus = map[string]string{
+	"success":  "✅",
+	"failed":   "❌",
+	"canceled": "⚠️",
+	"running":  "🔄",
+	"pending":  "⏳",
+	"created":  "🔹",
+	"skipped":  "⏭",
+	"manual":   "✋",
+}
+
+// SendPipelineNotification отправляет сводку по пайплайну.
+// Логи упавших задач в неё не входят: их несут карточки задач.
+func (bot *Bot) SendPipelineNotification(ctx context.Context, p domain.GitlabPipelineWebhook) error {
+	var tgIDs map[string]string
+	if strings.EqualFold(p.ObjectAttributes.Status, "failed") {
+		tgIDs = bot.mentions(ctx, domain.IdentityProviderGitEmail, p.Commit.Author.Email)
+	}
+	text := bot.buildPipelineText(p, tgIDs)
+	return bot.send(ctx, text)
+}
+
+// SendJobNotification отправляет уведомление об упавшей CI‑задаче. Хвост
+// лога допишет воркер доставки (AppendJobTrace): скачивать его в обработчике
+// вебхука значит задерживать ответ GitLab.
+func (bot *Bot) SendJobNotification(ctx context.Context, j domain.GitlabJobWebhook) error {
+	tgIDs := bot.mentions(ctx, domain.IdentityProviderGitEmail, j.Commit.AuthorEmail)
+	return bot.enqueue(ctx, &domain.Notification{
+		Text:     bot.buildJobText(j, tgIDs),
+		JobTrace: &domain.NotificationJobTrace{ProjectID: j.ProjectID, JobID: j.BuildID},
+	})
+}
+
+// AppendJobTrace дописывает в конец карточки задачи хвост лога, сколько
+// влезет в лимит сообщения.
+func (bot *Bot) AppendJobTrace(text, trace string) string {
+	if trace == "" {
+		return text
+	}
+	const open, closing = "\n<i>Лог задачи:</i>\n<pre>", "</pre>"
+	budget := maxMessageLen - runeLen(text) - runeLen(open) - runeLen(closing)
+	tail := bot.escapedTail(trace, budget)
+	if tail == "" {
+		return text
+	}
+	return text + open + tail + closing
+}
+
+func (bot *Bot) ruPipelineStatus(en string) string {
+	if v, ok := ruPipelineStatus[strings.ToLower(en)]; ok {
+		return v
+	}
+	return en
+}
+
+func (bot *Bot) pipelineStatusEmoji(status string) string {
+	if v, ok := emojiByPipelineStatus[strings.ToLower(status)]; ok {
+		return v
+	}
+	return "🔹"
+}
+
+func (bot *Bot) buildPipelineText(p domain.GitlabPipelineWebhook, tgIDs map[string]string) string {
+	var b strings.Builder
+	b.Grow(1024)
+
+	attrs := p.ObjectAttributes
+	pipelineURL := attrs.URL
+	if pipelineURL == "" {
+		pipelineURL = fmt.Sprintf("%s/-/pipelines/%d", p.Project.WebURL, attrs.ID)
+	}
+
+	fmt.Fprintf(
+		&b,
+		"<b>⚙️ <a href=\"%s\">%s</a> | %s %s</b>\n",
+		bot.Escape(p.Project.WebURL),
+		bot.Escape(p.Project.Name),
+		bot.Escape(bot.ruPipelineStatus(attrs.Status)),
+		bot.pipelineStatusEmoji(attrs.Status),
+	)
+
+	meta := []string{
+		fmt.Sprintf("\n<b>• Пайплайн:</b> <a href=\"%s\">#%d</a>", bot.Escape(pipelineURL), attrs.ID),
+		fmt.Sprintf("<b>• Ветка:</b> %s", bot.Escape(attrs.Ref)),
+		fmt.Sprintf("<b>• Коммит:</b> <code>%s</code>", bot.Escape(p.Commit.Title)),
+		fmt.Sprintf("<b>• Автор коммита:</b> %s",
+			bot.mention(tgIDs, domain.IdentityProviderGitEmail, p.Commit.Author.Email, p.Commit.Author.Name)),
+		fmt.Sprintf("<b>• Запустил:</b> %s", bot.Escape(p.User.Name)),
+	}
+	if attrs.Duration != nil {
+		meta = append(meta, fmt.Sprintf("<b>• Время выполнения:</b> %s", bot.formatSeconds(*attrs.Duration)))
+	}
+
+	for _, m := range meta {
+		b.WriteString(m + "\n")
+	}
+
+	jobsByStage := make(map[string][]domain.GitlabPipelineJob, len(attrs.Stages))
+	for _, job := range p.Builds {
+		jobsByStage[job.Stage] = append(jobsByStage[job.Stage], job)
+	}
+
+	footer := fmt.Sprintf("\n\n🔗 <b><a href=\"%s\">Информация о пайплайне</a></b>\n", bot.Escape(pipelineURL))
+
+	if len(p.Builds) > 0 {
+		var lines []string
+		for _, stage := range bot.pipelineStages(attrs.Stages, jobsByStage) {
+			jobs := jobsByStage[stage]
+			sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+
+			lines = append(lines, fmt.Sprintf("%s <b>%s</b>\n", bot.pipelineStatusEmoji(bot.stageStatus(jobs)), bot.Escape(stage)))
+			for _, job := range jobs {
+				lines = append(lines, fmt.Sprintf("    %s %s\n", bot.pipelineStatusEmoji(job.Status), bot.Escape(job.Name)))
+			}
+		}
+
+		const open, closing = "\n<i>Этапы:</i><blockquote expandable>\n", "</blockquote>"
+		// Обрезаем список целыми строками, чтобы не разорвать HTML‑теги;
+		// резервируем место под строку «…и ещё N».
+		budget := maxMessageLen - runeLen(b.String()) - runeLen(footer) - runeLen(open) - runeLen(closing) - 32
+		b.WriteString(open)
+		for i, line := range lines {
+			if budget -= runeLen(line); budget < 0 {
+				fmt.Fprintf(&b, "…и ещё %d\n", len(lines)-i)
+				break
+			}
+			b.WriteString(line)
+		}
+		b.WriteString(closing)
+	}
+
+	b.WriteString(footer)
+
+	return b.String()
+}
+
+func (bot *Bot) buildJobText(j domain.GitlabJobWebhook, tgIDs map[string]string) string {
+	var b strings.Builder
+	b.Grow(1024)
+
+	projectURL := j.Repository.Homepage
+	jobURL := fmt.Sprintf("%s/-/jobs/%d", projectURL, j.BuildID)
+	pipelineURL := fmt.Sprintf("%s/-/pipelines/%d", projectURL, j.PipelineID)
+
+	fmt.Fprintf(
+		&b,
+		"<b>⚙️ <a href=\"%s\">%s</a> | Ошибка в задаче %s</b>\n",
+		bot.Escape(projectURL),
+		bot.Escape(j.Repository.Name),
+		bot.pipelineStatusEmoji(j.BuildStatus),
+	)
+
+	meta := []string{
+		fmt.Sprintf("\n<b>• Задача:</b> <a href=\"%s\">%s</a>", bot.Escape(jobURL), bot.Escape(j.BuildName)),
+		fmt.Sprintf("<b>• Этап:</b> %s", bot.Escape(j.BuildStage)),
+		fmt.Sprintf("<b>• Пайплайн:</b> <a href=\"%s\">#%d</a>", bot.Escape(pipelineURL), j.PipelineID),
+		fmt.Sprintf("<b>• Ветка:</b> %s", bot.Escape(j.Ref)),
+		fmt.Sprintf("<b>• Коммит:</b> <code>%s</code>", bot.Escape(cutHead(strings.TrimSpace(j.Commit.Message), maxCommitLen))),
+		fmt.Sprintf("<b>• Автор коммита:</b> %s",
+			bot.mention(tgIDs, domain.IdentityProviderGitEmail, j.Commit.AuthorEmail, j.Commit.AuthorName)),
+	}
+	if j.BuildFailureReason != "" {
+		meta = append(meta, fmt.Sprintf("<b>• Причина:</b> %s", bot.Escape(j.BuildFailureReason)))
+	}
+	if j.BuildDuration != nil {
+		meta = append(meta, fmt.Sprintf("<b>• Время выполнения:</b> %s", bot.formatSeconds(*j.BuildDuration)))
+	}
+
+	for _, m := range meta {
+		b.WriteString(m + "\n")
+	}
+
+	fmt.Fprintf(
+		&b,
+		"\n🔗 <b><a href=\"%s\">Информация о задаче</a></b>\n",
+		bot.Escape(jobURL),
+	)
+
+	return b.String()
+}
+
+// pipelineStages возвращает этапы в порядке из .gitlab-ci.yml,
+// добавляя в конец те, что встретились только в builds.
+func (bot *Bot) pipelineStages(order []string, jobsByStage map[string][]domain.GitlabPipelineJob) []string {
+	stages := make([]string, 0, len(jobsByStage))
+	seen := make(map[string]bool, len(jobsByStage))
+	for _, s := range order {
+		if _, ok := jobsByStage[s]; ok && !seen[s] {
+			stages = append(stages, s)
+			seen[s] = true
+		}
+	}
+	var rest []string
+	for s := range jobsByStage {
+		if !seen[s] {
+			rest = append(rest, s)
+		}
+	}
+	sort.Strings(rest)
+	return append(stages, rest...)
+}
+
+// stageStatus сворачивает статусы задач этапа в один.
+func (bot *Bot) stageStatus(jobs []domain.GitlabPipelineJob) string {
+	priority := []string{"failed", "running", "pending", "canceled", "manual", "created", "success", "skipped"}
+	present := make(map[string]bool, len(jobs))
+	for _, j := range jobs {
+		if j.Status == "failed" && j.AllowFailure {
+			present["success"] = true
+			continue
+		}
+		present[j.Status] = true
+	}
+	for _, s := range priority {
+		if present[s] {
+			return s
+		}
+	}
+	return ""
+}
+
+// escapedTail возвращает экранированный хвост лога, который вместе с
+// ведущим «…» укладывается в limit символов. Конец лога важнее начала:
+// там обычно сама ошибка.
+func (bot *Bot) escapedTail(trace string, limit int) string {
+	r := []rune(trace)
+	parts := make([]string, 0, len(r))
+	size := 0
+	for i := len(r) - 1; i >= 0; i-- {
+		esc := bot.Escape(string(r[i]))
+		n := runeLen(esc)
+		if i > 0 && size+n+1 > limit || i == 0 && size+n > limit {
+			if size+1 > limit {
+				return ""
+			}
+			parts = append(parts, "…")
+			break
+		}
+		parts = append(parts, esc)
+		size += n
+	}
+	var b strings.Builder
+	b.Grow(size + len("…"))
+	for i := len(parts) - 1; i >= 0; i-- {
+		b.WriteString(parts[i])
+	}
+	return b.String()
+}
+
+// cutHead обрезает s до limit символов, помечая обрезку «…».
+func cutHead(s string, limit int) string {
+	r := []rune(s)
+	if len(r) <= limit {
+		return s
+	}
+	return string(r[:limit-1]) + "…"
+}
+
+func runeLen(s string) int { return utf8.RuneCountInString(s) }
+
+func (bot *Bot) formatSeconds(sec float64) string {
+	return (time.Duration(sec * float64(time.Second))).Round(time.Second).String()
+}
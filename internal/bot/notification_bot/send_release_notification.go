@@ -0,0 +1,75 @@
+package notification_bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"victa/internal/domain"
+)
+
+func (bot *Bot) SendReleaseNotification(ctx context.Context, r domain.GitlabReleaseWebhook) error {
+	text := bot.buildReleaseText(r)
+	return bot.send(ctx, text)
+}
+
+func (bot *Bot) buildReleaseText(r domain.GitlabReleaseWebhook) string {
+	var b strings.Builder
+	b.Grow(512)
+
+	name := r.Name
+	if name == "" {
+		name = r.Tag
+	}
+
+	fmt.Fprintf(&b,
+		"🏷 <b><a href=\"%s\">%s</a> | <a href=\"%s\">%s</a></b>\n",
+		bot.Escape(r.Project.WebURL), bot.Escape(r.Project.Name),
+		bot.Escape(r.URL), bot.Escape(name),
+	)
+
+	meta := []string{
+		fmt.Sprintf("\n<b>• Тег:</b> <code>%s</code>", bot.Escape(r.Tag)),
+	}
+	if r.Commit.Title != "" {
+		meta = append(meta, fmt.Sprintf("<b>• Коммит:</b> <code>%s</code>", bot.Escape(r.Commit.Title)))
+	}
+	if r.Commit.Author.Name != "" {
+		meta = append(meta, fmt.Sprintf("<b>• Автор коммита:</b> %s", bot.Escape(r.Commit.Author.Name)))
+	}
+
+	for _, m := range meta {
+		b.WriteString(m + "\n")
+	}
+
+	b.WriteString("\n")
+
+	switch r.Action {
+	case "create":
+		b.WriteString("🚀 <b>Опубликован релиз</b>\n\n")
+	case "update":
+		b.WriteString("🔄 <b>Релиз обновлён</b>\n\n")
+	case "delete":
+		b.WriteString("🗑 <b>Релиз удалён</b>\n\n")
+	default:
+		fmt.Fprintf(&b, "<b>%s</b>\n\n", bot.Escape(r.Action))
+	}
+
+	if len(r.Assets.Links) > 0 {
+		b.WriteString("<i>Файлы:</i>\n")
+		for _, l := range r.Assets.Links {
+			fmt.Fprintf(&b, "📦 <a href=\"%s\">%s</a>\n", bot.Escape(l.URL), bot.Escape(l.Name))
+		}
+		b.WriteString("\n")
+	}
+
+	desc := bot.MarkdownToHTML(r.Description)
+	if desc != "" {
+		fmt.Fprintf(&b,
+			"<i>Описание релиза:</i>\n<blockquote expandable>%s</blockquote>\n",
+			desc,
+		)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,215 @@
+package notification_bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"victa/internal/domain"
+)
+
+var ruSentryLevel = map[string]string{
+	"fatal":   "Fatal",
+	"error":   "Ошибка",
+	"warning": "Предупреждение",
+	"info":    "Info",
+	"debug":   "Debug",
+}
+
+var ruMetricAlertAction = map[string]string{
+	"critical": "Критический порог",
+	"warning":  "Порог предупреждения",
+	"resolved": "Алерт закрыт",
+}
+
+var emojiByMetricAlertAction = map[string]string{
+	"critical": "🔴",
+	"warning":  "🟡",
+	"resolved": "🟢",
+}
+
+func (bot *Bot) SendSentryEventNotification(ctx context.Context, w domain.SentryWebhook) error {
+	text := bot.buildSentryEventText(w)
+	return bot.send(ctx, text)
+}
+
+func (bot *Bot) SendSentryMetricAlertNotification(ctx context.Context, w domain.SentryWebhook) error {
+	text := bot.buildSentryMetricAlertText(w)
+	return bot.send(ctx, text)
+}
+
+func (bot *Bot) buildSentryEventText(w domain.SentryWebhook) string {
+	var b strings.Builder
+	b.Grow(512)
+
+	ev := w.Data.Event
+	device := ev.Contexts.Device
+	osCtx := ev.Contexts.OS
+
+	issueURL := ev.WebURL
+	if issueURL == "" {
+		issueURL = ev.IssueURL
+	}
+
+	title := w.Data.TriggeredRule
+	if title == "" {
+		title = "Новое событие"
+	}
+
+	b.WriteString(fmt.Sprintf("⚠️ <b><a href=\"%s\">%s</a> | %s</b>\n",
+		bot.Escape(issueURL), bot.Escape(ev.Title), bot.Escape(title)))
+
+	version := ev.Release
+	if ev.Contexts.App.AppVersion != "" {
+		version = ev.Contexts.App.AppVersion
+		if ev.Contexts.App.AppBuild != "" {
+			version += "+" + ev.Contexts.App.AppBuild
+		}
+	}
+
+	meta := []string{
+		fmt.Sprintf("\n<b>• Версия приложения:</b> %s", bot.Escape(version)),
+		fmt.Sprintf("<b>• Платформа:</b> %s", bot.Escape(ev.Platform)),
+		fmt.Sprintf("<b>• Уровень:</b> %s", bot.Escape(bot.ruSentryLevel(ev.Level))),
+	}
+	if ev.Environment != "" {
+		meta = append(meta, fmt.Sprintf("<b>• Окружение:</b> %s", bot.Escape(ev.Environment)))
+	}
+	if ev.Culprit != "" {
+		meta = append(meta, fmt.Sprintf("<b>• Источник:</b> <code>%s</code>", bot.Escape(ev.Culprit)))
+	}
+	if ev.User != nil && ev.User.ID != "" {
+		meta = append(meta, fmt.Sprintf("<b>• User ID:</b> <code>%s</code>", bot.Escape(ev.User.ID)))
+	}
+
+	for _, m := range meta {
+		b.WriteString(m + "\n")
+	}
+
+	bot.writeErrorMessage(&b, bot.sentryErrorMessage(ev))
+
+	if device.Model != "" || osCtx.Name != "" {
+		deviceMeta := []string{
+			fmt.Sprintf("<b>• Устройство:</b> %s %s", bot.Escape(device.Manufacturer), bot.Escape(device.Model)),
+			fmt.Sprintf("<b>• OS:</b> %s %s", bot.Escape(osCtx.Name), bot.Escape(osCtx.Version)),
+			fmt.Sprintf("<b>• Orientation:</b> %s", bot.Escape(device.Orientation)),
+			fmt.Sprintf("<b>• Battery:</b> %.0f %% (Charging: %v)", device.BatteryLevel, device.Charging),
+			fmt.Sprintf("<b>• RAM:</b> %s свободно из %s",
+				bot.formatBytes(device.FreeMemory), bot.formatBytes(device.MemorySize)),
+			fmt.Sprintf("<b>• Disk:</b> %s свободно", bot.formatBytes(device.FreeStorage)),
+			fmt.Sprintf("<b>• Jailbreak:</b> %v", osCtx.Rooted),
+			fmt.Sprintf("<b>• Эмулятор:</b> %v", device.Simulator),
+		}
+		if ev.Datetime != nil {
+			deviceMeta = append(deviceMeta,
+				fmt.Sprintf("<b>• Время события:</b> %s", ev.Datetime.Format(time.RFC3339)))
+		}
+		bot.writeDeviceInfo(&b, deviceMeta)
+	}
+
+	// Sentry отдаёт фреймы от внешнего к внутреннему — разворачиваем,
+	// чтобы порядок совпадал с Bugsnag.
+	var frames []stackFrame
+	for _, ex := range ev.Exception.Values {
+		if ex.Stacktrace == nil {
+			continue
+		}
+		for i := len(ex.Stacktrace.Frames) - 1; i >= 0; i-- {
+			f := ex.Stacktrace.Frames[i]
+			file := f.Filename
+			if file == "" {
+				file = f.Module
+			}
+			frames = append(frames, stackFrame{
+				File:   file,
+				Line:   strconv.Itoa(f.LineNo),
+				Method: f.Function,
+			})
+		}
+	}
+	if len(frames) > 0 {
+		bot.writeStackTrace(&b, frames)
+	}
+
+	bot.writeErrorLink(&b, issueURL)
+
+	return b.String()
+}
+
+func (bot *Bot) buildSentryMetricAlertText(w domain.SentryWebhook) string {
+	var b strings.Builder
+	b.Grow(512)
+
+	alert := w.Data.MetricAlert
+
+	title := w.Data.DescriptionTitle
+	if title == "" {
+		title = alert.AlertRule.Name
+	}
+
+	b.WriteString(fmt.Sprintf("📈 <b><a href=\"%s\">%s</a> | %s %s</b>\n",
+		bot.Escape(w.Data.WebURL), bot.Escape(title),
+		bot.Escape(bot.ruMetricAlertAction(w.Action)), bot.metricAlertEmoji(w.Action)))
+
+	meta := []string{
+		fmt.Sprintf("\n<b>• Правило:</b> %s", bot.Escape(alert.AlertRule.Name)),
+		fmt.Sprintf("<b>• Метрика:</b> <code>%s</code>", bot.Escape(alert.AlertRule.Aggregate)),
+	}
+	if alert.AlertRule.Query != "" {
+		meta = append(meta, fmt.Sprintf("<b>• Фильтр:</b> <code>%s</code>", bot.Escape(alert.AlertRule.Query)))
+	}
+	if alert.AlertRule.TimeWindow > 0 {
+		meta = append(meta, fmt.Sprintf("<b>• Окно:</b> %d мин", alert.AlertRule.TimeWindow))
+	}
+	if alert.DateDetected != nil {
+		meta = append(meta, fmt.Sprintf("<b>• Обнаружено:</b> %s", alert.DateDetected.Format(time.RFC3339)))
+	}
+
+	for _, m := range meta {
+		b.WriteString(m + "\n")
+	}
+
+	if w.Data.DescriptionText != "" {
+		fmt.Fprintf(&b, "\n<i>%s</i>\n", bot.Escape(w.Data.DescriptionText))
+	}
+
+	fmt.Fprintf(&b, "\n🔗 <b><a href=\"%s\">Информация об алерте</a></b>",
+		bot.Escape(w.Data.WebURL))
+
+	return b.String()
+}
+
+func (bot *Bot) sentryErrorMessage(ev *domain.SentryEvent) string {
+	for _, ex := range ev.Exception.Values {
+		if ex.Value != "" {
+			return fmt.Sprintf("%s: %s", ex.Type, ex.Value)
+		}
+	}
+	if ev.Message != "" {
+		return ev.Message
+	}
+	return ev.Title
+}
+
+func (bot *Bot) ruSentryLevel(level string) string {
+	if v, ok := ruSentryLevel[strings.ToLower(level)]; ok {
+		return v
+	}
+	return level
+}
+
+func (bot *Bot) ruMetricAlertAction(action string) string {
+	if v, ok := ruMetricAlertAction[strings.ToLower(action)]; ok {
+		return v
+	}
+	return action
+}
+
+func (bot *Bot) metricAlertEmoji(action string) string {
+	if v, ok := emojiByMetricAlertAction[strings.ToLower(action)]; ok {
+		return v
+	}
+	return "🔹"
+}
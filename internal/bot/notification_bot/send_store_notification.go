@@ -0,0 +1,67 @@
+package notification_bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"victa/internal/domain"
+)
+
+// SendStoreVersionNotification сообщает, что в магазине вышла новая версия.
+func (bot *Bot) SendStoreVersionNotification(ctx context.Context, st domain.AppStoreState, version string) error {
+	text := fmt.Sprintf(
+		"🏪 <b>%s | %s</b>\n\n✅ Версия <code>%s</code> опубликована\n",
+		bot.Escape(st.AppName),
+		bot.Escape(st.StoreName),
+		bot.Escape(version),
+	)
+	return bot.send(ctx, text)
+}
+
+// SendStoreReviewNotification пересылает новый отзыв из магазина.
+func (bot *Bot) SendStoreReviewNotification(ctx context.Context, st domain.AppStoreState, r domain.StoreReview) error {
+	return bot.send(ctx, bot.buildStoreReviewText(st, r))
+}
+
+func (bot *Bot) buildStoreReviewText(st domain.AppStoreState, r domain.StoreReview) string {
+	var b strings.Builder
+	b.Grow(512)
+
+	fmt.Fprintf(&b,
+		"💬 <b>%s | %s | Новый отзыв</b>\n",
+		bot.Escape(st.AppName),
+		bot.Escape(st.StoreName),
+	)
+
+	meta := []string{
+		fmt.Sprintf("\n<b>• Оценка:</b> %s", bot.ratingStars(r.Rating)),
+	}
+	if r.Author != "" {
+		meta = append(meta, fmt.Sprintf("<b>• Автор:</b> %s", bot.Escape(r.Author)))
+	}
+	if r.Version != "" {
+		meta = append(meta, fmt.Sprintf("<b>• Версия:</b> <code>%s</code>", bot.Escape(r.Version)))
+	}
+
+	for _, m := range meta {
+		b.WriteString(m + "\n")
+	}
+
+	if r.Title != "" || r.Text != "" {
+		b.WriteString("\n<blockquote expandable>")
+		if r.Title != "" {
+			fmt.Fprintf(&b, "<b>%s</b>\n", bot.Escape(r.Title))
+		}
+		b.WriteString(bot.Escape(r.Text))
+		b.WriteString("</blockquote>\n")
+	}
+
+	return b.String()
+}
+
+// ratingStars рисует оценку звёздами: ★★★☆☆.
+func (bot *Bot) ratingStars(rating int) string {
+	rating = max(0, min(rating, 5))
+	return strings.Repeat("★", rating) + strings.Repeat("☆", 5-rating)
+}
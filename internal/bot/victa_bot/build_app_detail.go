@@ -19,8 +19,18 @@ func (b *Bot) BuildAppDetail(ctx context.Context, chatID int64, app *domain.App,
 			b.BuildDeleteButton(fmt.Sprintf("%v?app_id=%d&company_id=%d", CallbackDeleteApp, app.ID, app.CompanyID)),
 			b.BuildEditButton(fmt.Sprintf("%v?app_id=%d&company_id=%d", CallbackUpdateApp, app.ID, app.CompanyID)),
 		))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🧩 Интеграции", fmt.Sprintf("%v?app_id=%d&company_id=%d", CallbackAppIntegrations, app.ID, app.CompanyID)),
+		))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🏪 Сторы", fmt.Sprintf("%v?app_id=%d&company_id=%d", CallbackAppStores, app.ID, app.CompanyID)),
+		))
 	}
 
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🏗 Сборки", fmt.Sprintf("%v?app_id=%d&company_id=%d", CallbackListBuild, app.ID, app.CompanyID)),
+	))
+
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
 		b.BuildCloseButton(),
 	))
@@ -0,0 +1,45 @@
+package victa_bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"victa/internal/domain"
+	appErr "victa/internal/errors"
+)
+
+func (b *Bot) BuildAppIntegrationsDetail(ctx context.Context, chatID int64, app *domain.App) (*tgbotapi.MessageConfig, error) {
+	ai, err := b.AppSvc.GetIntegration(ctx, app.ID)
+	if err != nil && !errors.Is(err, appErr.ErrAppIntegrationNotFound) {
+		return nil, err
+	}
+
+	tmpl, err := b.BuildIntegrationTemplate(ai)
+	if err != nil {
+		return nil, err
+	}
+
+	var text = fmt.Sprintf("📱 *%s | Интеграции* 🧩", app.Name)
+	if ai == nil {
+		text = fmt.Sprintf("%s\n\n%s", text, "🔴 Приложение не привязано, уведомления идут в чаты компании")
+	} else {
+		text = fmt.Sprintf("%s\n\n%s\n\n```json\n%s\n```", text, "🟢 Приложение привязано. Пустой чат — уведомление уйдёт в чат компании, upload_artifacts: null — как у компании", tmpl)
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.BuildEditButton(fmt.Sprintf("%s?app_id=%d&company_id=%d", CallbackUpdateAppIntegrations, app.ID, app.CompanyID)),
+	))
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.BuildBackButton(fmt.Sprintf("%v?app_id=%d&company_id=%d", CallbackBackToDetailApp, app.ID, app.CompanyID)),
+	))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	config := b.NewKeyboardMessage(chatID, text, keyboard)
+
+	return &config, nil
+}
@@ -0,0 +1,71 @@
+package victa_bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"victa/internal/domain"
+)
+
+func (b *Bot) BuildAppStoresDetail(ctx context.Context, chatID int64, app *domain.App) (*tgbotapi.MessageConfig, error) {
+	states, err := b.StoreSvc.GetByAppID(ctx, app.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	text := fmt.Sprintf("📱 *%s | Сторы* 🏪", app.Name)
+	if len(states) == 0 {
+		text = fmt.Sprintf("%s\n\n%s", text, "🔴 Приложение не привязано к сторам")
+	} else {
+		var lines []string
+		for _, st := range states {
+			version := "—"
+			if st.LastVersion != nil {
+				version = *st.LastVersion
+			}
+			status := "🟢"
+			if _, ok := b.StoreSvc.Client(st.StoreSlug); !ok {
+				status = "⚪️"
+			}
+			lines = append(lines, fmt.Sprintf("%s *%s*: `%s`, версия %s", status, st.StoreName, st.StoreAppID, version))
+		}
+		text = fmt.Sprintf("%s\n\n%s\n\n⚪️ — стор пока не отслеживается", text, strings.Join(lines, "\n"))
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.BuildEditButton(fmt.Sprintf("%s?app_id=%d&company_id=%d", CallbackUpdateAppStores, app.ID, app.CompanyID)),
+	))
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.BuildBackButton(fmt.Sprintf("%v?app_id=%d&company_id=%d", CallbackBackToDetailApp, app.ID, app.CompanyID)),
+	))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	config := b.NewKeyboardMessage(chatID, text, keyboard)
+
+	return &config, nil
+}
+
+// BuildAppStoresTemplate собирает JSON {"<slug>": "<ID в сторе>"} по
+// поддерживаемым сторам и уже сделанным привязкам (их можно отвязать).
+func (b *Bot) BuildAppStoresTemplate(states []domain.AppStoreState) (string, error) {
+	m := make(map[string]string, len(domain.StoreSlugs))
+	for _, slug := range b.StoreSvc.SupportedSlugs() {
+		m[slug] = ""
+	}
+	for _, st := range states {
+		m[st.StoreSlug] = st.StoreAppID
+	}
+
+	bytes, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
@@ -0,0 +1,102 @@
+package victa_bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"html"
+	"strings"
+	"victa/internal/domain"
+)
+
+// buildListLimit — сколько последних сборок показываем.
+const buildListLimit = 10
+
+func (b *Bot) BuildBuildList(ctx context.Context, chatID int64, app *domain.App) (*tgbotapi.MessageConfig, error) {
+	apiKey, codemagicAppID, err := b.codemagicSetup(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+
+	builds, err := b.CodemagicSvc.ListBuilds(ctx, codemagicAppID, apiKey, buildListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📱 <b>%s | Сборки</b> 🏗\n", html.EscapeString(app.Name))
+	if len(builds) == 0 {
+		sb.WriteString("\nСборок пока не было.")
+	}
+
+	var buttons []tgbotapi.InlineKeyboardButton
+	for _, build := range builds {
+		branch := build.Branch
+		if branch == "" {
+			branch = build.Commit.Branch
+		}
+		fmt.Fprintf(&sb, "\n%s <b>#%d</b> %s · <code>%s</code> — %s",
+			buildStatusEmoji(build), build.Index,
+			html.EscapeString(build.Config.Name), html.EscapeString(branch),
+			html.EscapeString(build.Status))
+		if !build.StartedAt.IsZero() {
+			fmt.Fprintf(&sb, ", %s", build.StartedAt.Local().Format("02.01 15:04"))
+		}
+
+		switch {
+		case !build.IsFinished():
+			buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(
+				fmt.Sprintf("⏹ #%d", build.Index),
+				fmt.Sprintf("%v?app_id=%d&build_id=%s", CallbackCancelBuild, app.ID, build.ID),
+			))
+		case isBuildFailed(build):
+			buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(
+				fmt.Sprintf("🔁 #%d", build.Index),
+				fmt.Sprintf("%v?app_id=%d&build_id=%s", CallbackRebuildBuild, app.ID, build.ID),
+			))
+		}
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for len(buttons) > 0 {
+		n := min(3, len(buttons))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(buttons[:n]...))
+		buttons = buttons[n:]
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("▶️ Запустить сборку", fmt.Sprintf("%v?app_id=%d", CallbackStartBuild, app.ID)),
+		tgbotapi.NewInlineKeyboardButtonData("🔄 Обновить", fmt.Sprintf("%v?app_id=%d&company_id=%d", CallbackListBuild, app.ID, app.CompanyID)),
+	))
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.BuildBackButton(fmt.Sprintf("%v?app_id=%d&company_id=%d", CallbackBackToDetailApp, app.ID, app.CompanyID)),
+	))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	msg := b.NewKeyboardMessage(chatID, sb.String(), keyboard)
+	msg.ParseMode = tgbotapi.ModeHTML
+	return &msg, nil
+}
+
+func buildStatusEmoji(build domain.CodemagicBuild) string {
+	switch {
+	case !build.IsFinished():
+		return "🔄"
+	case isBuildFailed(build):
+		return "❌"
+	case strings.HasPrefix(strings.ToLower(build.Status), "cancel"), strings.EqualFold(build.Status, "skipped"):
+		return "⚠️"
+	default:
+		return "✅"
+	}
+}
+
+func isBuildFailed(build domain.CodemagicBuild) bool {
+	switch strings.ToLower(build.Status) {
+	case "failed", "timeout":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package victa_bot
+
+import (
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"victa/internal/domain"
+)
+
+// BuildCompanyIntegrationFields собирает меню мастера: текущие значения
+// полей (от секретов — последние 4 символа) и по кнопке на каждое поле.
+func (b *Bot) BuildCompanyIntegrationFields(
+	chatID int64,
+	company *domain.Company,
+	ci *domain.CompanyIntegration,
+	notice string,
+) tgbotapi.MessageConfig {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "💼 *%s | Настройка интеграций* 🧩\n\n", company.Name)
+	if notice != "" {
+		sb.WriteString(notice + "\n\n")
+	}
+
+	var (
+		rows [][]tgbotapi.InlineKeyboardButton
+		row  []tgbotapi.InlineKeyboardButton
+	)
+	for _, f := range companyIntegrationFields {
+		fmt.Fprintf(&sb, "%s: %s\n", f.Title, b.integrationFieldValue(ci, f))
+
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(f.Title, fmt.Sprintf(
+			"%s?company_id=%d&field=%s", CallbackEditIntegrationField, company.ID, f.Key,
+		)))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.BuildBackButton(fmt.Sprintf("%s?company_id=%d", CallbackCompanyIntegrations, company.ID)),
+	))
+
+	return b.NewKeyboardMessage(chatID, sb.String(), tgbotapi.NewInlineKeyboardMarkup(rows...))
+}
+
+func (b *Bot) integrationFieldValue(ci *domain.CompanyIntegration, f integrationField) string {
+	if f.Kind == integrationFieldFlag {
+		if v := *f.flag(ci); v != nil && *v {
+			return "вкл."
+		}
+		return "выкл."
+	}
+
+	v := *f.text(ci)
+	switch {
+	case v == nil || *v == "":
+		return "—"
+	case f.IsSecret():
+		return "`" + b.MaskSecret(*v) + "`"
+	default:
+		return "`" + *v + "`"
+	}
+}
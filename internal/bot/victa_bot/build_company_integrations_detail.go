@@ -37,6 +37,14 @@ func (b *Bot) BuildCompanyIntegrationsDetail(ctx context.Context, chatID int64,
 		tgbotapi.NewInlineKeyboardButtonData("🔒 Сгенерировать API токен", fmt.Sprintf("%v?company_id=%d", CallbackCreateJwtToken, company.ID)),
 	))
 
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("📨 Последние вебхуки", fmt.Sprintf("%v?company_id=%d", CallbackListDelivery, company.ID)),
+	))
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔀 Правила маршрутизации", fmt.Sprintf("%v?company_id=%d", CallbackListRoutingRule, company.ID)),
+	))
+
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
 		b.BuildBackButton(fmt.Sprintf("%v?company_id=%d", CallbackBackToDetailCompany, company.ID)),
 	))
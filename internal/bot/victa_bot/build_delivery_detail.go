@@ -0,0 +1,68 @@
+package victa_bot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"html"
+	"sort"
+	"strings"
+	"victa/internal/domain"
+)
+
+// deliveryBodyLimit — сколько символов тела показывать: лимит сообщения 4096.
+const deliveryBodyLimit = 2500
+
+func (b *Bot) BuildDeliveryDetail(chatID int64, d *domain.WebhookDelivery) tgbotapi.MessageConfig {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "📨 <b>%s | #%d</b> %s\n\n",
+		html.EscapeString(d.Provider), d.ID, emojiByDeliveryOutcome[d.Outcome])
+	fmt.Fprintf(&sb, "<b>Получен:</b> %s\n", d.CreatedAt.Format("02.01.2006 15:04:05"))
+	fmt.Fprintf(&sb, "<b>Ответ:</b> %d (%s)\n", d.Status, html.EscapeString(d.Outcome))
+	if d.Error != nil {
+		fmt.Fprintf(&sb, "<b>Ошибка:</b> <code>%s</code>\n", html.EscapeString(*d.Error))
+	}
+
+	keys := make([]string, 0, len(d.Headers))
+	for k := range d.Headers {
+		// в старых записях секретные заголовки ещё могли сохраниться
+		if domain.IsSecretWebhookHeader(k) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	sb.WriteString("\n<i>Заголовки:</i><blockquote expandable>")
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "%s: %s\n", html.EscapeString(k), html.EscapeString(d.Headers[k]))
+	}
+	sb.WriteString("</blockquote>\n")
+
+	body := d.Body
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, []byte(body), "", "  ") == nil {
+		body = pretty.String()
+	}
+	if r := []rune(body); len(r) > deliveryBodyLimit {
+		body = string(r[:deliveryBodyLimit]) + "\n…"
+	}
+	fmt.Fprintf(&sb, "\n<i>Тело:</i>\n<pre>%s</pre>", html.EscapeString(body))
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔁 Повторить", fmt.Sprintf("%v?company_id=%d&delivery_id=%d", CallbackReplayDelivery, d.CompanyID, d.ID)),
+	))
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.BuildBackButton(fmt.Sprintf("%v?company_id=%d", CallbackListDelivery, d.CompanyID)),
+	))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	message := b.NewKeyboardMessage(chatID, sb.String(), keyboard)
+	message.ParseMode = tgbotapi.ModeHTML
+	return message
+}
@@ -0,0 +1,52 @@
+package victa_bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"victa/internal/domain"
+)
+
+var emojiByDeliveryOutcome = map[string]string{
+	domain.DeliveryOutcomeOK:        "✅",
+	domain.DeliveryOutcomeIgnored:   "⚪️",
+	domain.DeliveryOutcomeDuplicate: "🔁",
+	domain.DeliveryOutcomeFailed:    "❌",
+}
+
+func (b *Bot) BuildDeliveryList(ctx context.Context, chatID int64, company *domain.Company) (*tgbotapi.MessageConfig, error) {
+	deliveries, err := b.ArchiveSvc.GetLastByCompanyID(ctx, company.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, d := range deliveries {
+		cbData := fmt.Sprintf("%v?company_id=%d&delivery_id=%d", CallbackDetailDelivery, company.ID, d.ID)
+		title := fmt.Sprintf("%s %s | %s | %d",
+			emojiByDeliveryOutcome[d.Outcome],
+			d.Provider,
+			d.CreatedAt.Format("02.01 15:04:05"),
+			d.Status,
+		)
+		rows = append(rows,
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(title, cbData),
+			),
+		)
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.BuildBackButton(fmt.Sprintf("%v?company_id=%d", CallbackCompanyIntegrations, company.ID)),
+	))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	text := fmt.Sprintf("💼 *%s | Вебхуки* 📨", company.Name)
+	if len(deliveries) == 0 {
+		text = fmt.Sprintf("%s\n\n%s", text, "Вебхуков пока не было")
+	}
+
+	msg := b.NewKeyboardMessage(chatID, text, keyboard)
+	return &msg, nil
+}
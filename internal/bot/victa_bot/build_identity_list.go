@@ -0,0 +1,68 @@
+package victa_bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"html"
+	"strings"
+	"victa/internal/domain"
+)
+
+// identityProviders — какие учётные записи можно связать и как они называются в боте.
+var identityProviders = []struct {
+	Provider string
+	Title    string
+	Hint     string
+}{
+	{domain.IdentityProviderGitlab, "GitLab", "логин GitLab, например @jsmith"},
+	{domain.IdentityProviderGitEmail, "Email коммитов", "email, которым подписаны ваши коммиты"},
+	{domain.IdentityProviderBugsnag, "Bugsnag", "email вашей учётной записи Bugsnag"},
+}
+
+func (b *Bot) BuildIdentityList(ctx context.Context, chatID int64, user *domain.User) (*tgbotapi.MessageConfig, error) {
+	identities, err := b.IdentitySvc.GetAllByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := make(map[string]string, len(identities))
+	for _, ui := range identities {
+		linked[ui.Provider] = ui.ExternalID
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🔗 <b>Мои аккаунты</b>\n\n")
+	sb.WriteString("По связанным аккаунтам уведомления упоминают вас в Telegram: " +
+		"задачи и MR на вас, упавшие сборки ваших коммитов, назначенные ошибки.\n")
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, p := range identityProviders {
+		value, ok := linked[p.Provider]
+		if ok {
+			fmt.Fprintf(&sb, "\n<b>• %s:</b> <code>%s</code>", p.Title, html.EscapeString(value))
+		} else {
+			fmt.Fprintf(&sb, "\n<b>• %s:</b> не связан", p.Title)
+		}
+
+		buttons := []tgbotapi.InlineKeyboardButton{
+			tgbotapi.NewInlineKeyboardButtonData("✏️ "+p.Title, fmt.Sprintf("%v?provider=%s", CallbackLinkIdentity, p.Provider)),
+		}
+		if ok {
+			buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(
+				"🗑 "+p.Title, fmt.Sprintf("%v?provider=%s", CallbackUnlinkIdentity, p.Provider),
+			))
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(buttons...))
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.BuildBackButton(CallbackMainMenu),
+	))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	msg := b.NewKeyboardMessage(chatID, sb.String(), keyboard)
+	msg.ParseMode = tgbotapi.ModeHTML
+	return &msg, nil
+}
@@ -15,5 +15,12 @@ func (b *Bot) BuildMainMenu(ctx context.Context, chatID int64, user *domain.User
 		return nil, err
 	}
 	msg.Text = fmt.Sprintf("%s\n\n%s", text, msg.Text)
+
+	if keyboard, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup); ok {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔗 Мои аккаунты", CallbackListIdentity),
+		))
+		msg.ReplyMarkup = keyboard
+	}
 	return msg, nil
 }
@@ -0,0 +1,86 @@
+package victa_bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"html"
+	"strings"
+	"victa/internal/domain"
+)
+
+func (b *Bot) BuildRoutingRuleList(ctx context.Context, chatID int64, company *domain.Company) (*tgbotapi.MessageConfig, error) {
+	rules, err := b.RoutingSvc.GetAllByCompanyID(ctx, company.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "💼 <b>%s | Правила маршрутизации</b> 🔀\n\n", html.EscapeString(company.Name))
+	if len(rules) == 0 {
+		sb.WriteString("Правил нет — уведомления идут в чаты из интеграций.")
+	} else {
+		sb.WriteString("Срабатывает первое подходящее правило, иначе — чат из интеграций.\n")
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, rule := range rules {
+		fmt.Fprintf(&sb, "\n<b>%d.</b> %s", i+1, b.describeRoutingRule(rule))
+
+		var buttons []tgbotapi.InlineKeyboardButton
+		if i > 0 {
+			buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(
+				fmt.Sprintf("⬆️ %d", i+1),
+				fmt.Sprintf("%v?company_id=%d&rule_id=%d", CallbackMoveRoutingRule, company.ID, rule.ID),
+			))
+		}
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("🗑 %d", i+1),
+			fmt.Sprintf("%v?company_id=%d&rule_id=%d", CallbackDeleteRoutingRule, company.ID, rule.ID),
+		))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(buttons...))
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("➕ Добавить правило", fmt.Sprintf("%v?company_id=%d", CallbackCreateRoutingRule, company.ID)),
+	))
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.BuildBackButton(fmt.Sprintf("%v?company_id=%d", CallbackCompanyIntegrations, company.ID)),
+	))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	msg := b.NewKeyboardMessage(chatID, sb.String(), keyboard)
+	msg.ParseMode = tgbotapi.ModeHTML
+	return &msg, nil
+}
+
+// describeRoutingRule — условия и действие правила одной строкой (HTML).
+func (b *Bot) describeRoutingRule(rule domain.RoutingRule) string {
+	var conds []string
+	add := func(name string, v *string) {
+		if v != nil {
+			conds = append(conds, fmt.Sprintf("%s=<code>%s</code>", name, html.EscapeString(*v)))
+		}
+	}
+	add("source", rule.Source)
+	if rule.AppID != nil {
+		conds = append(conds, fmt.Sprintf("app_id=<code>%d</code>", *rule.AppID))
+	}
+	add("branch", rule.Branch)
+	add("status", rule.Status)
+	add("trigger", rule.TriggerType)
+	add("severity", rule.Severity)
+	add("label", rule.Label)
+
+	when := "любое событие"
+	if len(conds) > 0 {
+		when = strings.Join(conds, ", ")
+	}
+
+	if rule.Action == domain.RoutingActionMute {
+		return when + " → 🔇 не отправлять"
+	}
+	return fmt.Sprintf("%s → 💬 <code>%s</code>", when, html.EscapeString(strings.Join(rule.ChatIDs, ", ")))
+}
@@ -22,16 +22,42 @@ const (
 	CallbackCompanyIntegrations       = "company_integrations"
 	CallbackBackToDetailCompany       = "back_to_detail_company"
 	CallbackUpdateCompanyIntegrations = "update_integrations"
+	CallbackEditIntegrationField      = "edit_integration_field"
+	CallbackClearIntegrationField     = "clear_integration_field"
 	CallbackCreateJwtToken            = "create_jwt_token"
+	CallbackListDelivery              = "list_delivery"
+	CallbackDetailDelivery            = "detail_delivery"
+	CallbackReplayDelivery            = "replay_delivery"
+	CallbackListRoutingRule           = "list_routing_rule"
+	CallbackCreateRoutingRule         = "create_routing_rule"
+	CallbackDeleteRoutingRule         = "delete_routing_rule"
+	CallbackMoveRoutingRule           = "move_routing_rule"
 )
 
 const (
-	CallbackCreateApp       = "create_app"
-	CallbackDeleteApp       = "delete_app"
-	CallbackUpdateApp       = "update_app"
-	CallbackListApp         = "list_app"
-	CallbackDetailApp       = "detail_app"
-	CallbackAppIntegrations = "app_integrations"
+	CallbackCreateApp             = "create_app"
+	CallbackDeleteApp             = "delete_app"
+	CallbackUpdateApp             = "update_app"
+	CallbackListApp               = "list_app"
+	CallbackDetailApp             = "detail_app"
+	CallbackBackToDetailApp       = "back_to_detail_app"
+	CallbackAppIntegrations       = "app_integrations"
+	CallbackUpdateAppIntegrations = "edit_app_integrations"
+	CallbackAppStores             = "app_stores"
+	CallbackUpdateAppStores       = "edit_app_stores"
+)
+
+const (
+	CallbackListBuild    = "list_build"
+	CallbackStartBuild   = "start_build"
+	CallbackCancelBuild  = "cancel_build"
+	CallbackRebuildBuild = "rebuild_build"
+)
+
+const (
+	CallbackListIdentity   = "list_identity"
+	CallbackLinkIdentity   = "link_identity"
+	CallbackUnlinkIdentity = "unlink_identity"
 )
 
 const (
@@ -0,0 +1,155 @@
+package victa_bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"victa/internal/domain"
+	"victa/internal/service"
+)
+
+// integrationCheckTimeout — сколько ждать Telegram и Codemagic при проверке настроек.
+const integrationCheckTimeout = 5 * time.Second
+
+var (
+	errEmptyIntegrationValue = errors.New("пустое значение — чтобы очистить поле, нажмите «Очистить»")
+	errInvalidBotToken       = errors.New("Telegram не принял токен бота")
+	errBotTokenRequired      = errors.New("сначала укажите токен бота уведомлений")
+	errInvalidChat           = errors.New("чат не найден: отправьте числовой ID, @username или перешлите сообщение из чата")
+	errBotNotInChat          = errors.New("бот уведомлений не состоит в чате — добавьте его и отправьте ID ещё раз")
+	errInvalidCodemagicKey   = errors.New("Codemagic не принял API‑ключ")
+	errInvalidGitlabURL      = errors.New("нужен адрес вида https://gitlab.example.com")
+)
+
+// validateIntegrationField проверяет введённое значение поля и приводит его
+// к виду, в котором оно хранится: чат — числовым ID, URL — без «/» в конце.
+func (b *Bot) validateIntegrationField(
+	ctx context.Context,
+	ci *domain.CompanyIntegration,
+	field integrationField,
+	message *tgbotapi.Message,
+) (string, error) {
+	value := strings.TrimSpace(message.Text)
+	if value == "" && message.ForwardFromChat == nil {
+		return "", errEmptyIntegrationValue
+	}
+
+	switch field.Kind {
+	case integrationFieldCodemagicKey:
+		cmCtx, cancel := context.WithTimeout(ctx, integrationCheckTimeout)
+		defer cancel()
+
+		err := b.CodemagicSvc.CheckAPIKey(cmCtx, value)
+		if errors.Is(err, service.ErrInvalidCodemagicAPIKey) {
+			return "", errInvalidCodemagicKey
+		}
+		if err != nil {
+			return "", fmt.Errorf("не удалось проверить ключ Codemagic: %w", err)
+		}
+
+	case integrationFieldBotToken:
+		if _, err := newCheckBotAPI(value); err != nil {
+			return "", err
+		}
+
+	case integrationFieldChat:
+		if ci.NotificationBotToken == nil || *ci.NotificationBotToken == "" {
+			return "", errBotTokenRequired
+		}
+		api, err := newCheckBotAPI(*ci.NotificationBotToken)
+		if err != nil {
+			return "", err
+		}
+		chatID, err := resolveNotificationChat(api, message)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(chatID, 10), nil
+
+	case integrationFieldURL:
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", errInvalidGitlabURL
+		}
+		return strings.TrimRight(value, "/"), nil
+	}
+
+	return value, nil
+}
+
+// newCheckBotAPI подключается к боту по token; NewBotAPI сразу вызывает getMe.
+func newCheckBotAPI(token string) (*tgbotapi.BotAPI, error) {
+	client := &http.Client{Timeout: integrationCheckTimeout}
+	api, err := tgbotapi.NewBotAPIWithClient(token, tgbotapi.APIEndpoint, client)
+	if err != nil {
+		return nil, errInvalidBotToken
+	}
+	return api, nil
+}
+
+// resolveNotificationChat находит чат по пересланному сообщению, @username
+// или ID и проверяет, что бот api в нём состоит.
+func resolveNotificationChat(api *tgbotapi.BotAPI, message *tgbotapi.Message) (int64, error) {
+	var chatID int64
+	switch text := strings.TrimSpace(message.Text); {
+	case message.ForwardFromChat != nil:
+		chatID = message.ForwardFromChat.ID
+	case strings.HasPrefix(text, "@"):
+		chat, err := api.GetChat(tgbotapi.ChatInfoConfig{
+			ChatConfig: tgbotapi.ChatConfig{SuperGroupUsername: text},
+		})
+		if err != nil {
+			return 0, errInvalidChat
+		}
+		chatID = chat.ID
+	default:
+		id, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return 0, errInvalidChat
+		}
+		chatID = id
+	}
+
+	if !botInChat(api, chatID) {
+		return 0, errBotNotInChat
+	}
+	return chatID, nil
+}
+
+// botInChat — бот api состоит в чате и может в него писать.
+func botInChat(api *tgbotapi.BotAPI, chatID int64) bool {
+	member, err := api.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chatID, UserID: api.Self.ID},
+	})
+	if err != nil {
+		return false
+	}
+	return !member.HasLeft() && !member.WasKicked()
+}
+
+// chatsWithoutBot возвращает названия полей‑чатов, в которых нет бота api.
+// Нужен после смены токена: новый бот мог не попасть в уже настроенные чаты.
+func chatsWithoutBot(api *tgbotapi.BotAPI, ci *domain.CompanyIntegration) []string {
+	var missing []string
+	for _, f := range companyIntegrationFields {
+		if f.Kind != integrationFieldChat {
+			continue
+		}
+		v := *f.text(ci)
+		if v == nil || *v == "" {
+			continue
+		}
+		chatID, err := strconv.ParseInt(*v, 10, 64)
+		if err != nil || !botInChat(api, chatID) {
+			missing = append(missing, f.Title)
+		}
+	}
+	return missing
+}
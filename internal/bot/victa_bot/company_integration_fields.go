@@ -0,0 +1,135 @@
+package victa_bot
+
+import (
+	"victa/internal/domain"
+)
+
+type integrationFieldKind int
+
+const (
+	integrationFieldText integrationFieldKind = iota
+	integrationFieldSecret
+	integrationFieldCodemagicKey
+	integrationFieldBotToken
+	integrationFieldChat
+	integrationFieldURL
+	integrationFieldFlag
+)
+
+// integrationField — шаг мастера настройки интеграций компании.
+// Key короткий, потому что уходит в callback data (не больше 64 байт).
+type integrationField struct {
+	Key   string
+	Title string
+	Hint  string
+	Kind  integrationFieldKind
+
+	text func(ci *domain.CompanyIntegration) **string
+	flag func(ci *domain.CompanyIntegration) **bool
+}
+
+// IsSecret — значение поля нельзя показывать в чате.
+func (f integrationField) IsSecret() bool {
+	switch f.Kind {
+	case integrationFieldSecret, integrationFieldCodemagicKey, integrationFieldBotToken:
+		return true
+	default:
+		return false
+	}
+}
+
+var companyIntegrationFields = []integrationField{
+	{
+		Key: "cm_key", Title: "🔑 Codemagic API", Kind: integrationFieldCodemagicKey,
+		Hint: "Отправьте API‑ключ из Codemagic → Teams → Integrations → Codemagic API.",
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.CodemagicAPIKey },
+	},
+	{
+		Key: "bot", Title: "🤖 Бот уведомлений", Kind: integrationFieldBotToken,
+		Hint: "Отправьте токен бота уведомлений от @BotFather.",
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.NotificationBotToken },
+	},
+	{
+		Key: "deploy", Title: "🚀 Чат сборок", Kind: integrationFieldChat,
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.DeployNotificationChatID },
+	},
+	{
+		Key: "issues", Title: "📝 Чат задач", Kind: integrationFieldChat,
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.IssuesNotificationChatID },
+	},
+	{
+		Key: "errors", Title: "🐞 Чат ошибок", Kind: integrationFieldChat,
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.ErrorsNotificationChatID },
+	},
+	{
+		Key: "mrs", Title: "🔀 Чат merge requests", Kind: integrationFieldChat,
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.MergeRequestsNotificationChatID },
+	},
+	{
+		Key: "pipes", Title: "⚙️ Чат пайплайнов", Kind: integrationFieldChat,
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.PipelinesNotificationChatID },
+	},
+	{
+		Key: "reviews", Title: "⭐️ Чат отзывов", Kind: integrationFieldChat,
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.ReviewsNotificationChatID },
+	},
+	{
+		Key: "gl_url", Title: "🌐 GitLab URL", Kind: integrationFieldURL,
+		Hint: "Отправьте адрес self‑hosted GitLab, например `https://gitlab.example.com`. " +
+			"Без него запросы к API и токен уходят только на gitlab.com.",
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.GitlabBaseURL },
+	},
+	{
+		Key: "gl_token", Title: "🦊 GitLab API", Kind: integrationFieldSecret,
+		Hint: "Отправьте токен GitLab API с правом `api`.",
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.GitlabAPIToken },
+	},
+	{
+		Key: "gl_hook", Title: "🦊 GitLab webhook", Kind: integrationFieldSecret,
+		Hint: "Отправьте Secret token, указанный в настройках вебхука GitLab.",
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.GitlabWebhookToken },
+	},
+	{
+		Key: "gh_hook", Title: "🐙 GitHub webhook", Kind: integrationFieldSecret,
+		Hint: "Отправьте Secret, указанный в настройках вебхука GitHub.",
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.GithubWebhookSecret },
+	},
+	{
+		Key: "sentry", Title: "🛡 Sentry", Kind: integrationFieldSecret,
+		Hint: "Отправьте Client Secret интеграции Sentry.",
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.SentryClientSecret },
+	},
+	{
+		Key: "bs_hook", Title: "🐛 Bugsnag webhook", Kind: integrationFieldSecret,
+		Hint: "Отправьте секрет вебхука Bugsnag.",
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.BugsnagWebhookSecret },
+	},
+	{
+		Key: "bs_token", Title: "🐛 Bugsnag API", Kind: integrationFieldSecret,
+		Hint: "Отправьте Personal auth token Bugsnag.",
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.BugsnagAPIToken },
+	},
+	{
+		Key: "art_types", Title: "📦 Виды артефактов", Kind: integrationFieldText,
+		Hint: "Отправьте виды артефактов через запятую (`apk,aab,ipa,web`) или `*` для всех.",
+		text: func(ci *domain.CompanyIntegration) **string { return &ci.ArtifactTypes },
+	},
+	{
+		Key: "upload", Title: "📤 Загружать APK/AAB", Kind: integrationFieldFlag,
+		flag: func(ci *domain.CompanyIntegration) **bool { return &ci.UploadArtifacts },
+	},
+	{
+		Key: "qr", Title: "🔳 QR‑коды артефактов", Kind: integrationFieldFlag,
+		flag: func(ci *domain.CompanyIntegration) **bool { return &ci.ArtifactQRCodes },
+	},
+}
+
+// findIntegrationField ищет поле мастера по ключу из callback.
+func findIntegrationField(key string) (integrationField, bool) {
+	for _, f := range companyIntegrationFields {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	return integrationField{}, false
+}
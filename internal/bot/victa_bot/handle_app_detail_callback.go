@@ -14,7 +14,7 @@ func (b *Bot) HandleDetailAppCallback(ctx context.Context, callback *tgbotapi.Ca
 		return
 	}
 
-	b.ClearChatState(chatID)
+	b.ClearChatState(ctx, chatID)
 	b.SendMessage(*message)
 }
 
@@ -28,7 +28,7 @@ func (b *Bot) HandleBackToDetailAppCallback(ctx context.Context, callback *tgbot
 		return
 	}
 
-	b.ClearChatState(chatID)
+	b.ClearChatState(ctx, chatID)
 	b.EditMessage(messageID, *message)
 }
 
@@ -0,0 +1,124 @@
+package victa_bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"victa/internal/domain"
+	appErr "victa/internal/errors"
+)
+
+// HandleAppIntegrationsCallback показывает привязки приложения к внешним сервисам.
+func (b *Bot) HandleAppIntegrationsCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверные параметры."))
+		return
+	}
+
+	app, err := b.checkAppAdmin(ctx, callback.From.ID, params.AppID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	config, err := b.BuildAppIntegrationsDetail(ctx, chatID, app)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+	b.EditMessage(messageID, *config)
+}
+
+func (b *Bot) HandleUpdateAppIntegrationCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверные параметры."))
+		return
+	}
+
+	app, err := b.checkAppAdmin(ctx, callback.From.ID, params.AppID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	ai, err := b.AppSvc.GetIntegration(ctx, app.ID)
+	if err != nil && !errors.Is(err, appErr.ErrAppIntegrationNotFound) {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	tmpl, err := b.BuildIntegrationTemplate(ai)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	msgText := fmt.Sprintf(
+		"Отправьте обновленный JSON с привязками приложения. "+
+			"GitLab‑проект можно указать ID или путём `group/project`:\n\n```json\n%s\n```",
+		tmpl,
+	)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			b.BuildCancelButton(),
+		),
+	)
+
+	b.AddChatState(ctx, chatID, StateWaitingUpdateAppIntegration)
+	b.AddPendingAppData(ctx, chatID, PendingAppData{ID: app.ID})
+	b.AddPendingCompanyID(ctx, chatID, app.CompanyID)
+
+	b.SendPendingMessage(ctx, b.NewKeyboardMessage(chatID, msgText, keyboard))
+}
+
+func (b *Bot) HandleUpdateAppIntegration(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	data := b.GetPendingAppData(ctx, chatID)
+
+	app, err := b.checkAppAdmin(ctx, message.From.ID, data.ID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	_, err = b.AppSvc.CreateOrUpdateIntegration(ctx, app.ID, message.Text)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	config, err := b.BuildAppIntegrationsDetail(ctx, chatID, app)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	b.ClearChatState(ctx, chatID)
+
+	b.SendMessage(*config)
+}
+
+// checkAppAdmin пускает к привязкам приложения только админов его компании.
+func (b *Bot) checkAppAdmin(ctx context.Context, tgID, appID int64) (*domain.App, error) {
+	user, err := b.UserSvc.GetByTgID(ctx, tgID)
+	if err != nil {
+		return nil, err
+	}
+	app, err := b.AppSvc.GetByID(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.CompanySvc.CheckAdmin(ctx, user.ID, app.CompanyID); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
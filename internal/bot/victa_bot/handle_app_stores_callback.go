@@ -0,0 +1,107 @@
+package victa_bot
+
+import (
+	"context"
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// HandleAppStoresCallback показывает привязки приложения к сторам.
+func (b *Bot) HandleAppStoresCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверные параметры."))
+		return
+	}
+
+	app, err := b.checkAppAdmin(ctx, callback.From.ID, params.AppID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	config, err := b.BuildAppStoresDetail(ctx, chatID, app)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+	b.EditMessage(messageID, *config)
+}
+
+func (b *Bot) HandleUpdateAppStoresCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверные параметры."))
+		return
+	}
+
+	app, err := b.checkAppAdmin(ctx, callback.From.ID, params.AppID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	states, err := b.StoreSvc.GetByAppID(ctx, app.ID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	tmpl, err := b.BuildAppStoresTemplate(states)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	msgText := fmt.Sprintf(
+		"Отправьте обновленный JSON с ID приложения в сторах. "+
+			"Для App Store — числовой ID (можно с регионом: `us/1234567890`), "+
+			"для RuStore — имя пакета. Play Store и AppGallery пока не отслеживаются. "+
+			"Пустая строка отвязывает стор:\n\n```json\n%s\n```",
+		tmpl,
+	)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			b.BuildCancelButton(),
+		),
+	)
+
+	b.AddChatState(ctx, chatID, StateWaitingUpdateAppStores)
+	b.AddPendingAppData(ctx, chatID, PendingAppData{ID: app.ID})
+	b.AddPendingCompanyID(ctx, chatID, app.CompanyID)
+
+	b.SendPendingMessage(ctx, b.NewKeyboardMessage(chatID, msgText, keyboard))
+}
+
+func (b *Bot) HandleUpdateAppStores(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	data := b.GetPendingAppData(ctx, chatID)
+
+	app, err := b.checkAppAdmin(ctx, message.From.ID, data.ID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	if err := b.StoreSvc.UpdateAppStores(ctx, app.ID, message.Text); err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	config, err := b.BuildAppStoresDetail(ctx, chatID, app)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	b.ClearChatState(ctx, chatID)
+
+	b.SendMessage(*config)
+}
@@ -0,0 +1,239 @@
+package victa_bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"victa/internal/domain"
+	appErr "victa/internal/errors"
+)
+
+// errCodemagicNotConfigured — для сборок нужны ключ API компании и ID приложения в Codemagic.
+var errCodemagicNotConfigured = errors.New(
+	"Codemagic не настроен: укажите codemagic_api_key в интеграциях компании " +
+		"и codemagic_app_id в интеграциях приложения",
+)
+
+// errBuildNotInApp — ID сборки из callback принадлежит другому приложению
+// Codemagic: ключ API компании открывает все её приложения, поэтому
+// принадлежность проверяем сами.
+var errBuildNotInApp = errors.New("Сборка не относится к этому приложению.")
+
+// HandleListBuildCallback показывает последние сборки приложения.
+func (b *Bot) HandleListBuildCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверные параметры."))
+		return
+	}
+
+	app, err := b.checkAppMember(ctx, callback.From.ID, params.AppID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	config, err := b.BuildBuildList(ctx, chatID, app)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+	b.EditMessage(messageID, *config)
+}
+
+func (b *Bot) HandleStartBuildCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверные параметры."))
+		return
+	}
+
+	app, err := b.checkAppMember(ctx, callback.From.ID, params.AppID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	apiKey, codemagicAppID, err := b.codemagicSetup(ctx, app)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	// подставляем workflow и ветку последней сборки
+	tmpl := domain.CodemagicBuildRequest{WorkflowID: "default-workflow", Branch: "main"}
+	if builds, err := b.CodemagicSvc.ListBuilds(ctx, codemagicAppID, apiKey, 1); err == nil && len(builds) > 0 {
+		tmpl.WorkflowID = builds[0].WorkflowID
+		tmpl.Branch = builds[0].Branch
+	}
+	tmpl.Variables = map[string]string{}
+
+	raw, err := json.MarshalIndent(struct {
+		WorkflowID string            `json:"workflow_id"`
+		Branch     string            `json:"branch"`
+		Variables  map[string]string `json:"variables"`
+	}{tmpl.WorkflowID, tmpl.Branch, tmpl.Variables}, "", "  ")
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	msgText := fmt.Sprintf(
+		"Отправьте параметры сборки в JSON: `workflow_id`, `branch` и, "+
+			"если нужно, переменные окружения в `variables`.\n\n```json\n%s\n```",
+		raw,
+	)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			b.BuildCancelButton(),
+		),
+	)
+
+	b.AddChatState(ctx, chatID, StateWaitingStartBuild)
+	b.AddPendingAppData(ctx, chatID, PendingAppData{ID: app.ID})
+
+	b.SendPendingMessage(ctx, b.NewKeyboardMessage(chatID, msgText, keyboard))
+}
+
+func (b *Bot) HandleBuildStarted(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	appID := b.GetPendingAppData(ctx, chatID).ID
+
+	app, err := b.checkAppMember(ctx, message.From.ID, appID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	apiKey, codemagicAppID, err := b.codemagicSetup(ctx, app)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	var req domain.CodemagicBuildRequest
+	if err := json.Unmarshal([]byte(message.Text), &req); err != nil {
+		b.SendErrorMessage(chatID, fmt.Errorf("invalid JSON: %w", err))
+		return
+	}
+	req.AppID = codemagicAppID
+
+	if _, err := b.CodemagicSvc.StartBuild(ctx, apiKey, req); err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	config, err := b.BuildBuildList(ctx, chatID, app)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	b.ClearChatState(ctx, chatID)
+
+	b.SendMessage(*config)
+}
+
+func (b *Bot) HandleCancelBuildCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	b.changeBuild(ctx, callback, func(ctx context.Context, buildID, apiKey string) error {
+		return b.CodemagicSvc.CancelBuild(ctx, buildID, apiKey)
+	})
+}
+
+func (b *Bot) HandleRebuildBuildCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	b.changeBuild(ctx, callback, func(ctx context.Context, buildID, apiKey string) error {
+		_, err := b.CodemagicSvc.Rebuild(ctx, buildID, apiKey)
+		return err
+	})
+}
+
+// changeBuild применяет change к сборке приложения и перерисовывает список.
+func (b *Bot) changeBuild(
+	ctx context.Context,
+	callback *tgbotapi.CallbackQuery,
+	change func(ctx context.Context, buildID, apiKey string) error,
+) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil || params.BuildID == "" {
+		b.SendMessage(b.NewMessage(chatID, "Неверные параметры."))
+		return
+	}
+
+	app, err := b.checkAppMember(ctx, callback.From.ID, params.AppID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	apiKey, codemagicAppID, err := b.codemagicSetup(ctx, app)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	build, err := b.CodemagicSvc.GetBuildByID(ctx, params.BuildID, apiKey)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+	if build.Build.AppID != codemagicAppID {
+		b.SendErrorMessage(chatID, errBuildNotInApp)
+		return
+	}
+
+	if err := change(ctx, params.BuildID, apiKey); err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	config, err := b.BuildBuildList(ctx, chatID, app)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+	b.EditMessage(messageID, *config)
+}
+
+// checkAppMember возвращает приложение, если пользователь состоит в его компании.
+func (b *Bot) checkAppMember(ctx context.Context, tgID, appID int64) (*domain.App, error) {
+	user, err := b.UserSvc.GetByTgID(ctx, tgID)
+	if err != nil {
+		return nil, err
+	}
+	app, err := b.AppSvc.GetByID(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.CompanySvc.CheckMember(ctx, user.ID, app.CompanyID); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+// codemagicSetup возвращает ключ Codemagic API компании и ID приложения в Codemagic.
+func (b *Bot) codemagicSetup(ctx context.Context, app *domain.App) (string, string, error) {
+	ci, err := b.CompanySvc.GetCompanyIntegrationByID(ctx, app.CompanyID)
+	if err != nil && !errors.Is(err, appErr.ErrIntegrationNotFound) {
+		return "", "", err
+	}
+	ai, err := b.AppSvc.GetIntegration(ctx, app.ID)
+	if err != nil && !errors.Is(err, appErr.ErrAppIntegrationNotFound) {
+		return "", "", err
+	}
+
+	if ci == nil || ci.CodemagicAPIKey == nil || ai == nil || ai.CodemagicAppID == nil {
+		return "", "", errCodemagicNotConfigured
+	}
+	return *ci.CodemagicAPIKey, *ai.CodemagicAppID, nil
+}
@@ -14,7 +14,7 @@ func (b *Bot) HandleDetailCompanyCallback(ctx context.Context, callback *tgbotap
 		return
 	}
 
-	b.ClearChatState(chatID)
+	b.ClearChatState(ctx, chatID)
 	b.SendMessage(*message)
 }
 
@@ -28,7 +28,7 @@ func (b *Bot) HandleBackToDetailCompanyCallback(ctx context.Context, callback *t
 		return
 	}
 
-	b.ClearChatState(chatID)
+	b.ClearChatState(ctx, chatID)
 	b.EditMessage(messageID, *message)
 }
 
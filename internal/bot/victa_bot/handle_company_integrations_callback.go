@@ -6,7 +6,6 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"reflect"
 	"strings"
-	"victa/internal/domain"
 )
 
 // HandleCompanyIntegrationsCallback обрабатывает нажатие кнопки «Интеграции»
@@ -36,38 +35,49 @@ func (b *Bot) HandleCompanyIntegrationsCallback(ctx context.Context, callback *t
 }
 
 // BuildIntegrationTemplate автоматически собирает JSON-шаблон
-// для всех полей CompanyIntegration (кроме company_id).
-func (b *Bot) BuildIntegrationTemplate(ci *domain.CompanyIntegration) (string, error) {
-	// 1) Разворачиваем nil-поинтерн в zero-value struct
-	inst := domain.CompanyIntegration{}
-	if ci != nil {
-		inst = *ci
+// по полям‑указателям интеграций (CompanyIntegration, AppIntegration):
+// строки выводятся как есть, флаги — true/false или null, если не заданы.
+// От полей с тегом secret:"true" остаются только последние символы.
+// Идентификаторы владельца (company_id, app_id) в шаблон не попадают.
+func (b *Bot) BuildIntegrationTemplate(v any) (string, error) {
+	// 1) Разворачиваем указатель; nil даёт zero-value struct
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv = reflect.New(rv.Type().Elem()).Elem()
+		} else {
+			rv = rv.Elem()
+		}
 	}
+	rt := rv.Type()
 
-	// 2) Готовим отражения типа и значения
-	rt := reflect.TypeOf(inst)
-	rv := reflect.ValueOf(inst)
-
-	// 3) Собираем map[tag]value
-	m := make(map[string]string, rt.NumField())
+	// 2) Собираем map[tag]value
+	m := make(map[string]any, rt.NumField())
 	for i := 0; i < rt.NumField(); i++ {
 		field := rt.Field(i)
 		tag := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
-		if tag == "" || tag == "company_id" {
+		fv := rv.Field(i)
+		// все поля, кроме ID владельца, — это *string или *bool
+		if tag == "" || tag == "-" || fv.Kind() != reflect.Ptr {
 			continue
 		}
-		fv := rv.Field(i)
-		var val string
-		// все поля кроме company_id — это *string
-		if fv.IsNil() {
-			val = ""
-		} else {
-			val = fv.Elem().String()
+		switch {
+		case field.Type.Elem().Kind() == reflect.Bool:
+			if fv.IsNil() {
+				m[tag] = nil
+			} else {
+				m[tag] = fv.Elem().Bool()
+			}
+		case fv.IsNil():
+			m[tag] = ""
+		case field.Tag.Get("secret") == "true":
+			m[tag] = b.MaskSecret(fv.Elem().String())
+		default:
+			m[tag] = fv.Elem().String()
 		}
-		m[tag] = val
 	}
 
-	// 4) Красиво маршалим
+	// 3) Красиво маршалим
 	bytes, err := json.MarshalIndent(m, "", "  ")
 	if err != nil {
 		return "", err
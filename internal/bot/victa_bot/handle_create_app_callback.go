@@ -5,7 +5,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-func (b *Bot) HandleCreateAppCallback(callback *tgbotapi.CallbackQuery) {
+func (b *Bot) HandleCreateAppCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
 	chatID := callback.Message.Chat.ID
 	params, err := b.GetCallbackArgs(callback.Data)
 
@@ -19,13 +19,13 @@ func (b *Bot) HandleCreateAppCallback(callback *tgbotapi.CallbackQuery) {
 		b.BuildCancelButton(),
 	))
 
-	b.AddPendingCompanyID(chatID, params.CompanyID)
-	b.AddChatState(chatID, StateWaitingCreateAppName)
+	b.AddPendingCompanyID(ctx, chatID, params.CompanyID)
+	b.AddChatState(ctx, chatID, StateWaitingCreateAppName)
 
-	b.SendPendingMessage(b.NewKeyboardMessage(chatID, msgText, keyboard))
+	b.SendPendingMessage(ctx, b.NewKeyboardMessage(chatID, msgText, keyboard))
 }
 
-func (b *Bot) HandleAppNameCreated(message *tgbotapi.Message) {
+func (b *Bot) HandleAppNameCreated(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
 
 	msgText := "Отправьте короткий тэг приложения"
@@ -33,21 +33,21 @@ func (b *Bot) HandleAppNameCreated(message *tgbotapi.Message) {
 		b.BuildCancelButton(),
 	))
 
-	data := b.pendingAppData[chatID]
+	data := b.GetPendingAppData(ctx, chatID)
 	data.Name = message.Text
 
-	b.AddPendingAppData(chatID, data)
-	b.AddChatState(chatID, StateWaitingCreateAppSlug)
+	b.AddPendingAppData(ctx, chatID, data)
+	b.AddChatState(ctx, chatID, StateWaitingCreateAppSlug)
 
-	b.SendPendingMessage(b.NewKeyboardMessage(chatID, msgText, keyboard))
+	b.SendPendingMessage(ctx, b.NewKeyboardMessage(chatID, msgText, keyboard))
 }
 
 func (b *Bot) HandleAppSlugCreated(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
-	companyID := b.pendingCompanyIDs[chatID]
+	companyID := b.GetPendingCompanyID(ctx, chatID)
 	tgID := message.From.ID
 
-	data := b.pendingAppData[chatID]
+	data := b.GetPendingAppData(ctx, chatID)
 	data.Slug = message.Text
 
 	user, err := b.UserSvc.GetByTgID(ctx, tgID)
@@ -64,7 +64,7 @@ func (b *Bot) HandleAppSlugCreated(ctx context.Context, message *tgbotapi.Messag
 
 	config := b.BuildAppDetail(ctx, chatID, app, user)
 
-	b.ClearChatState(chatID)
+	b.ClearChatState(ctx, chatID)
 
 	b.SendMessage(config)
 }
@@ -5,16 +5,16 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-func (b *Bot) HandleCreateCompanyCallback(callback *tgbotapi.CallbackQuery) {
+func (b *Bot) HandleCreateCompanyCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
 	chatID := callback.Message.Chat.ID
 
-	b.AddChatState(chatID, StateWaitingCreateCompanyName)
+	b.AddChatState(ctx, chatID, StateWaitingCreateCompanyName)
 
 	msgText := "Отправьте название компании"
 	cancelButton := b.BuildCancelButton()
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(cancelButton))
 
-	b.SendPendingMessage(b.NewKeyboardMessage(chatID, msgText, keyboard))
+	b.SendPendingMessage(ctx, b.NewKeyboardMessage(chatID, msgText, keyboard))
 }
 
 func (b *Bot) HandleCompanyNameCreated(ctx context.Context, message *tgbotapi.Message) {
@@ -35,7 +35,7 @@ func (b *Bot) HandleCompanyNameCreated(ctx context.Context, message *tgbotapi.Me
 
 	config := b.BuildCompanyDetail(ctx, chatID, company, user)
 
-	b.ClearChatState(chatID)
+	b.ClearChatState(ctx, chatID)
 
 	b.SendMessage(config)
 }
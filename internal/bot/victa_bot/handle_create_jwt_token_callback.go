@@ -1,11 +1,12 @@
 package victa_bot
 
 import (
+	"context"
 	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-func (b *Bot) HandleCreateJwtToken(callback *tgbotapi.CallbackQuery) {
+func (b *Bot) HandleCreateJwtToken(ctx context.Context, callback *tgbotapi.CallbackQuery) {
 	chatID := callback.Message.Chat.ID
 	params, err := b.GetCallbackArgs(callback.Data)
 	if err != nil {
@@ -19,7 +20,12 @@ func (b *Bot) HandleCreateJwtToken(callback *tgbotapi.CallbackQuery) {
 		return
 	}
 
-	text := fmt.Sprintf("`%s`", token)
+	text := fmt.Sprintf(
+		"`%s`\n\n"+
+			"Для GitLab, GitHub, Bugsnag и Sentry токен можно не передавать: "+
+			"укажите секрет вебхука в интеграциях и используйте адрес `/webhook/<провайдер>/%d`",
+		token, params.CompanyID,
+	)
 
 	var rows [][]tgbotapi.InlineKeyboardButton
 
@@ -29,6 +35,6 @@ func (b *Bot) HandleCreateJwtToken(callback *tgbotapi.CallbackQuery) {
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
 
-	b.ClearChatState(chatID)
+	b.ClearChatState(ctx, chatID)
 	b.SendMessage(b.NewKeyboardMessage(chatID, text, keyboard))
 }
@@ -6,7 +6,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-func (b *Bot) HandleDeleteAppCallback(callback *tgbotapi.CallbackQuery) {
+func (b *Bot) HandleDeleteAppCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
 	chatID := callback.Message.Chat.ID
 	params, err := b.GetCallbackArgs(callback.Data)
 	if err != nil {
@@ -14,12 +14,12 @@ func (b *Bot) HandleDeleteAppCallback(callback *tgbotapi.CallbackQuery) {
 		return
 	}
 
-	b.AddChatState(chatID, StateWaitingConfirmDeleteApp)
+	b.AddChatState(ctx, chatID, StateWaitingConfirmDeleteApp)
 
 	msgText := "Подтвердите удаление приложения"
 	confirmMessage := b.BuildConfirmMessage(chatID, msgText, fmt.Sprintf("%s?app_id=%v&company_id=%v", CallbackConfirmOperation, params.AppID, params.CompanyID))
 
-	b.SendPendingMessage(confirmMessage)
+	b.SendPendingMessage(ctx, confirmMessage)
 }
 
 func (b *Bot) HandleConfirmDeleteAppCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
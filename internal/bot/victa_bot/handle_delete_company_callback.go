@@ -6,7 +6,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-func (b *Bot) HandleDeleteCompanyCallback(callback *tgbotapi.CallbackQuery) {
+func (b *Bot) HandleDeleteCompanyCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
 	chatID := callback.Message.Chat.ID
 	params, err := b.GetCallbackArgs(callback.Data)
 	if err != nil {
@@ -14,12 +14,12 @@ func (b *Bot) HandleDeleteCompanyCallback(callback *tgbotapi.CallbackQuery) {
 		return
 	}
 
-	b.AddChatState(chatID, StateWaitingConfirmDeleteCompany)
+	b.AddChatState(ctx, chatID, StateWaitingConfirmDeleteCompany)
 
 	msgText := "Подтвердите удаление компании"
 	confirmMessage := b.BuildConfirmMessage(chatID, msgText, fmt.Sprintf("%s?company_id=%v", CallbackConfirmOperation, params.CompanyID))
 
-	b.SendPendingMessage(confirmMessage)
+	b.SendPendingMessage(ctx, confirmMessage)
 }
 
 func (b *Bot) HandleConfirmDeleteCompanyCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
@@ -1,13 +1,14 @@
 package victa_bot
 
 import (
+	"context"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-func (b *Bot) HandleDeleteMessageCallback(cb *tgbotapi.CallbackQuery) {
+func (b *Bot) HandleDeleteMessageCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
 	chatID := cb.Message.Chat.ID
 	messageID := cb.Message.MessageID
 
-	b.ClearChatState(cb.Message.Chat.ID)
+	b.ClearChatState(ctx, cb.Message.Chat.ID)
 	b.DeleteMessage(chatID, messageID)
 }
@@ -6,7 +6,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-func (b *Bot) HandleDeleteUserCallback(callback *tgbotapi.CallbackQuery) {
+func (b *Bot) HandleDeleteUserCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
 	chatID := callback.Message.Chat.ID
 	params, err := b.GetCallbackArgs(callback.Data)
 	if err != nil {
@@ -14,12 +14,12 @@ func (b *Bot) HandleDeleteUserCallback(callback *tgbotapi.CallbackQuery) {
 		return
 	}
 
-	b.AddChatState(chatID, StateWaitingConfirmDeleteUser)
+	b.AddChatState(ctx, chatID, StateWaitingConfirmDeleteUser)
 
 	msgText := "Подтвердите удаление пользователя из компании"
 	confirmMessage := b.BuildConfirmMessage(chatID, msgText, fmt.Sprintf("%s?company_id=%v&user_id=%v", CallbackConfirmOperation, params.CompanyID, params.UserID))
 
-	b.SendPendingMessage(confirmMessage)
+	b.SendPendingMessage(ctx, confirmMessage)
 }
 
 func (b *Bot) HandleConfirmDeleteUserCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
@@ -0,0 +1,116 @@
+package victa_bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"html"
+	"victa/internal/domain"
+)
+
+// HandleListDeliveryCallback показывает последние вебхуки компании.
+func (b *Bot) HandleListDeliveryCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверная команда."))
+		return
+	}
+
+	company, err := b.checkCompanyAdmin(ctx, callback.From.ID, params.CompanyID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	message, err := b.BuildDeliveryList(ctx, chatID, company)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	b.EditMessage(messageID, *message)
+}
+
+// HandleDetailDeliveryCallback показывает заголовки и тело вебхука.
+func (b *Bot) HandleDetailDeliveryCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверная команда."))
+		return
+	}
+
+	if _, err := b.checkCompanyAdmin(ctx, callback.From.ID, params.CompanyID); err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	delivery, err := b.ArchiveSvc.GetByID(ctx, params.DeliveryID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+	if delivery.CompanyID != params.CompanyID {
+		b.SendMessage(b.NewMessage(chatID, "Вебхук не найден."))
+		return
+	}
+
+	b.EditMessage(messageID, b.BuildDeliveryDetail(chatID, delivery))
+}
+
+// HandleReplayDeliveryCallback прогоняет вебхук через обработчик ещё раз.
+func (b *Bot) HandleReplayDeliveryCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверная команда."))
+		return
+	}
+
+	if _, err := b.checkCompanyAdmin(ctx, callback.From.ID, params.CompanyID); err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	status, msg, err := b.Replayer.Replay(ctx, params.CompanyID, params.DeliveryID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	// ответ обработчика произвольный (может содержать ` или *), поэтому экранируем его
+	text := fmt.Sprintf("🔁 Вебхук #%d обработан повторно\n\n<b>Ответ:</b> %d\n<code>%s</code>", params.DeliveryID, status, html.EscapeString(msg))
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		b.BuildCloseButton(),
+	))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	message := b.NewKeyboardMessage(chatID, text, keyboard)
+	message.ParseMode = tgbotapi.ModeHTML
+	b.SendMessage(message)
+}
+
+// checkCompanyAdmin пускает только админов компании: в телах вебхуков
+// бывают персональные данные, правила маршрутизации меняют доставку,
+// а интеграции хранят секреты.
+func (b *Bot) checkCompanyAdmin(ctx context.Context, tgID, companyID int64) (*domain.Company, error) {
+	user, err := b.UserSvc.GetByTgID(ctx, tgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.CompanySvc.CheckAdmin(ctx, user.ID, companyID); err != nil {
+		return nil, err
+	}
+
+	return b.CompanySvc.GetByID(ctx, companyID)
+}
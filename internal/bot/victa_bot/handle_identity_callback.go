@@ -0,0 +1,114 @@
+package victa_bot
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// HandleListIdentityCallback показывает связанные аккаунты пользователя.
+func (b *Bot) HandleListIdentityCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	user, err := b.UserSvc.GetByTgID(ctx, callback.From.ID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	message, err := b.BuildIdentityList(ctx, chatID, user)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	b.EditMessage(messageID, *message)
+}
+
+func (b *Bot) HandleLinkIdentityCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверная команда."))
+		return
+	}
+
+	hint := ""
+	for _, p := range identityProviders {
+		if p.Provider == params.Provider {
+			hint = p.Hint
+		}
+	}
+	if hint == "" {
+		b.SendMessage(b.NewMessage(chatID, "Неизвестный сервис."))
+		return
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		b.BuildCancelButton(),
+	))
+
+	b.AddPendingProvider(ctx, chatID, params.Provider)
+	b.AddChatState(ctx, chatID, StateWaitingLinkIdentity)
+
+	msg := b.NewKeyboardMessage(chatID, fmt.Sprintf("Отправьте %s", hint), keyboard)
+	b.SendPendingMessage(ctx, msg)
+}
+
+func (b *Bot) HandleIdentityLinked(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	provider := b.GetPendingProvider(ctx, chatID)
+
+	user, err := b.UserSvc.GetByTgID(ctx, message.From.ID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	if _, err := b.IdentitySvc.Link(ctx, user.ID, provider, message.Text); err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	config, err := b.BuildIdentityList(ctx, chatID, user)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	b.ClearChatState(ctx, chatID)
+
+	b.SendMessage(*config)
+}
+
+func (b *Bot) HandleUnlinkIdentityCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверная команда."))
+		return
+	}
+
+	user, err := b.UserSvc.GetByTgID(ctx, callback.From.ID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	if err := b.IdentitySvc.Unlink(ctx, user.ID, params.Provider); err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	message, err := b.BuildIdentityList(ctx, chatID, user)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	b.EditMessage(messageID, *message)
+}
@@ -27,6 +27,6 @@ func (b *Bot) HandleMainMenuCallback(ctx context.Context, callback *tgbotapi.Cal
 		return
 	}
 
-	b.ClearChatState(chatID)
+	b.ClearChatState(ctx, chatID)
 	b.EditMessage(messageID, *menu)
 }
@@ -0,0 +1,158 @@
+package victa_bot
+
+import (
+	"context"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// routingRuleTemplate — пример правила для ввода; пустые условия
+// подходят под любое значение.
+const routingRuleTemplate = `{
+  "source": "codemagic",
+  "app_id": 0,
+  "branch": "release/*",
+  "status": "",
+  "trigger_type": "",
+  "severity": "",
+  "label": "",
+  "action": "send",
+  "chat_ids": ["-1001234567890"]
+}`
+
+// HandleListRoutingRuleCallback показывает правила маршрутизации компании.
+func (b *Bot) HandleListRoutingRuleCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверная команда."))
+		return
+	}
+
+	company, err := b.checkCompanyAdmin(ctx, callback.From.ID, params.CompanyID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	message, err := b.BuildRoutingRuleList(ctx, chatID, company)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	b.EditMessage(messageID, *message)
+}
+
+func (b *Bot) HandleCreateRoutingRuleCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверная команда."))
+		return
+	}
+
+	if _, err := b.checkCompanyAdmin(ctx, callback.From.ID, params.CompanyID); err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	msgText := "Отправьте правило в JSON.\n\n" +
+		"`source` — codemagic, gitlab, bugsnag или sentry; `app_id` — ID приложения Victa; " +
+		"`branch` — ветка или шаблон; `status`, `trigger_type`, `severity`, `label` — " +
+		"статус, тип триггера Bugsnag, важность и метка задачи. Пустое поле — любое значение.\n" +
+		"`action` — send (в чаты из `chat_ids`) или mute (не отправлять).\n\n" +
+		"```json\n" + routingRuleTemplate + "\n```"
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			b.BuildCancelButton(),
+		),
+	)
+
+	b.AddChatState(ctx, chatID, StateWaitingCreateRoutingRule)
+	b.AddPendingCompanyID(ctx, chatID, params.CompanyID)
+
+	b.SendPendingMessage(ctx, b.NewKeyboardMessage(chatID, msgText, keyboard))
+}
+
+func (b *Bot) HandleRoutingRuleCreated(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	companyID := b.GetPendingCompanyID(ctx, chatID)
+
+	company, err := b.checkCompanyAdmin(ctx, message.From.ID, companyID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	if _, err := b.RoutingSvc.Create(ctx, company.ID, message.Text); err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	config, err := b.BuildRoutingRuleList(ctx, chatID, company)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	b.ClearChatState(ctx, chatID)
+
+	b.SendMessage(*config)
+}
+
+func (b *Bot) HandleDeleteRoutingRuleCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	b.changeRoutingRule(ctx, callback, b.RoutingSvc.Delete)
+}
+
+func (b *Bot) HandleMoveRoutingRuleCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	b.changeRoutingRule(ctx, callback, b.RoutingSvc.MoveUp)
+}
+
+// changeRoutingRule применяет change к правилу компании и перерисовывает список.
+func (b *Bot) changeRoutingRule(
+	ctx context.Context,
+	callback *tgbotapi.CallbackQuery,
+	change func(ctx context.Context, ruleID int64) error,
+) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверная команда."))
+		return
+	}
+
+	company, err := b.checkCompanyAdmin(ctx, callback.From.ID, params.CompanyID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	rule, err := b.RoutingSvc.GetByID(ctx, params.RuleID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+	if rule.CompanyID != company.ID {
+		b.SendMessage(b.NewMessage(chatID, "Правило не найдено."))
+		return
+	}
+
+	if err := change(ctx, rule.ID); err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	message, err := b.BuildRoutingRuleList(ctx, chatID, company)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	b.EditMessage(messageID, *message)
+}
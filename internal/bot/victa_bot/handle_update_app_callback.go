@@ -5,7 +5,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-func (b *Bot) HandleUpdateAppCallback(callback *tgbotapi.CallbackQuery) {
+func (b *Bot) HandleUpdateAppCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
 	chatID := callback.Message.Chat.ID
 	params, err := b.GetCallbackArgs(callback.Data)
 
@@ -19,15 +19,15 @@ func (b *Bot) HandleUpdateAppCallback(callback *tgbotapi.CallbackQuery) {
 		b.BuildCancelButton(),
 	))
 
-	b.AddPendingAppData(chatID, PendingAppData{ID: params.AppID})
+	b.AddPendingAppData(ctx, chatID, PendingAppData{ID: params.AppID})
 
-	b.AddPendingCompanyID(chatID, params.CompanyID)
-	b.AddChatState(chatID, StateWaitingUpdateAppName)
+	b.AddPendingCompanyID(ctx, chatID, params.CompanyID)
+	b.AddChatState(ctx, chatID, StateWaitingUpdateAppName)
 
-	b.SendPendingMessage(b.NewKeyboardMessage(chatID, msgText, keyboard))
+	b.SendPendingMessage(ctx, b.NewKeyboardMessage(chatID, msgText, keyboard))
 }
 
-func (b *Bot) HandleAppNameUpdated(message *tgbotapi.Message) {
+func (b *Bot) HandleAppNameUpdated(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
 
 	msgText := "Отправьте короткий тэг приложения"
@@ -35,20 +35,20 @@ func (b *Bot) HandleAppNameUpdated(message *tgbotapi.Message) {
 		b.BuildCancelButton(),
 	))
 
-	data := b.pendingAppData[chatID]
+	data := b.GetPendingAppData(ctx, chatID)
 	data.Name = message.Text
 
-	b.AddPendingAppData(chatID, data)
-	b.AddChatState(chatID, StateWaitingUpdateAppSlug)
+	b.AddPendingAppData(ctx, chatID, data)
+	b.AddChatState(ctx, chatID, StateWaitingUpdateAppSlug)
 
-	b.SendPendingMessage(b.NewKeyboardMessage(chatID, msgText, keyboard))
+	b.SendPendingMessage(ctx, b.NewKeyboardMessage(chatID, msgText, keyboard))
 }
 
 func (b *Bot) HandleAppSlugUpdated(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
 	tgID := message.From.ID
 
-	data := b.pendingAppData[chatID]
+	data := b.GetPendingAppData(ctx, chatID)
 	data.Slug = message.Text
 
 	user, err := b.UserSvc.GetByTgID(ctx, tgID)
@@ -65,7 +65,7 @@ func (b *Bot) HandleAppSlugUpdated(ctx context.Context, message *tgbotapi.Messag
 
 	config := b.BuildAppDetail(ctx, chatID, app, user)
 
-	b.ClearChatState(chatID)
+	b.ClearChatState(ctx, chatID)
 
 	b.SendMessage(config)
 }
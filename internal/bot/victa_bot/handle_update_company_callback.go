@@ -5,7 +5,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-func (b *Bot) HandleUpdateCompanyCallback(callback *tgbotapi.CallbackQuery) {
+func (b *Bot) HandleUpdateCompanyCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
 	chatID := callback.Message.Chat.ID
 	params, err := b.GetCallbackArgs(callback.Data)
 	if err != nil {
@@ -13,14 +13,14 @@ func (b *Bot) HandleUpdateCompanyCallback(callback *tgbotapi.CallbackQuery) {
 		return
 	}
 
-	b.AddChatState(chatID, StateWaitingUpdateCompanyName)
-	b.AddPendingCompanyID(chatID, params.CompanyID)
+	b.AddChatState(ctx, chatID, StateWaitingUpdateCompanyName)
+	b.AddPendingCompanyID(ctx, chatID, params.CompanyID)
 
 	msgText := "Отправьте название компании"
 	cancelButton := b.BuildCancelButton()
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(cancelButton))
 
-	b.SendPendingMessage(b.NewKeyboardMessage(chatID, msgText, keyboard))
+	b.SendPendingMessage(ctx, b.NewKeyboardMessage(chatID, msgText, keyboard))
 }
 
 func (b *Bot) HandleCompanyNameUpdated(ctx context.Context, message *tgbotapi.Message) {
@@ -33,7 +33,7 @@ func (b *Bot) HandleCompanyNameUpdated(ctx context.Context, message *tgbotapi.Me
 		return
 	}
 
-	companyID := b.pendingCompanyIDs[chatID]
+	companyID := b.GetPendingCompanyID(ctx, chatID)
 
 	_, err = b.CompanySvc.Update(ctx, companyID, message.Text, user.ID)
 	if err != nil {
@@ -47,7 +47,7 @@ func (b *Bot) HandleCompanyNameUpdated(ctx context.Context, message *tgbotapi.Me
 		return
 	}
 
-	b.ClearChatState(chatID)
+	b.ClearChatState(ctx, chatID)
 
 	b.SendMessage(*menu)
 }
@@ -4,72 +4,211 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"victa/internal/domain"
 	appErr "victa/internal/errors"
 )
 
+// HandleUpdateCompanyIntegrationCallback открывает мастер настройки интеграций.
 func (b *Bot) HandleUpdateCompanyIntegrationCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
 	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
 
 	params, err := b.GetCallbackArgs(callback.Data)
 	if err != nil {
 		b.SendMessage(b.NewMessage(chatID, "Неверные параметры."))
 		return
 	}
-	companyID := params.CompanyID
 
-	ci, err := b.CompanySvc.GetCompanyIntegrationByID(ctx, companyID)
-	if err != nil && !errors.Is(err, appErr.ErrIntegrationNotFound) {
+	company, err := b.checkCompanyAdmin(ctx, callback.From.ID, params.CompanyID)
+	if err != nil {
 		b.SendErrorMessage(chatID, err)
 		return
 	}
 
-	tmpl, err := b.BuildIntegrationTemplate(ci)
+	ci, err := b.getCompanyIntegration(ctx, company.ID)
 	if err != nil {
 		b.SendErrorMessage(chatID, err)
 		return
 	}
 
-	msgText := fmt.Sprintf(
-		"Отправьте обновленный JSON с данными для интеграции:\n\n```json\n%s\n```",
-		tmpl,
-	)
+	b.EditMessage(messageID, b.BuildCompanyIntegrationFields(chatID, company, ci, ""))
+}
+
+// HandleEditIntegrationFieldCallback переключает флаг или просит ввести
+// новое значение поля.
+func (b *Bot) HandleEditIntegrationFieldCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверные параметры."))
+		return
+	}
+	field, ok := findIntegrationField(params.Field)
+	if !ok {
+		b.SendMessage(b.NewMessage(chatID, "Неверные параметры."))
+		return
+	}
+
+	company, err := b.checkCompanyAdmin(ctx, callback.From.ID, params.CompanyID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	ci, err := b.getCompanyIntegration(ctx, company.ID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	if field.Kind == integrationFieldFlag {
+		flag := field.flag(ci)
+		v := *flag == nil || !**flag
+		*flag = &v
+
+		if ci, err = b.CompanySvc.SaveCompanyIntegration(ctx, company.ID, ci); err != nil {
+			b.SendErrorMessage(chatID, err)
+			return
+		}
+		b.EditMessage(messageID, b.BuildCompanyIntegrationFields(chatID, company, ci, ""))
+		return
+	}
+
+	msgText := fmt.Sprintf("*%s*\n\n%s", field.Title, b.integrationFieldHint(field))
+	if v := *field.text(ci); v != nil && *v != "" && !field.IsSecret() {
+		msgText += fmt.Sprintf("\n\nСейчас: `%s`", *v)
+	}
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🧹 Очистить", fmt.Sprintf(
+				"%s?company_id=%d&field=%s", CallbackClearIntegrationField, company.ID, field.Key,
+			)),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			b.BuildCancelButton(),
 		),
 	)
 
-	b.AddChatState(chatID, StateWaitingUpdateCompanyIntegration)
-	b.AddPendingCompanyID(chatID, params.CompanyID)
+	b.AddChatState(ctx, chatID, StateWaitingUpdateCompanyIntegration)
+	b.AddPendingCompanyID(ctx, chatID, company.ID)
+	b.AddPendingField(ctx, chatID, field.Key)
+
+	b.SendPendingMessage(ctx, b.NewKeyboardMessage(chatID, msgText, keyboard))
+}
+
+// HandleClearIntegrationFieldCallback стирает значение поля.
+func (b *Bot) HandleClearIntegrationFieldCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+
+	params, err := b.GetCallbackArgs(callback.Data)
+	if err != nil {
+		b.SendMessage(b.NewMessage(chatID, "Неверные параметры."))
+		return
+	}
+	field, ok := findIntegrationField(params.Field)
+	if !ok || field.Kind == integrationFieldFlag {
+		b.SendMessage(b.NewMessage(chatID, "Неверные параметры."))
+		return
+	}
+
+	company, err := b.checkCompanyAdmin(ctx, callback.From.ID, params.CompanyID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	ci, err := b.getCompanyIntegration(ctx, company.ID)
+	if err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
 
-	b.SendPendingMessage(b.NewKeyboardMessage(chatID, msgText, keyboard))
+	*field.text(ci) = nil
+	if ci, err = b.CompanySvc.SaveCompanyIntegration(ctx, company.ID, ci); err != nil {
+		b.SendErrorMessage(chatID, err)
+		return
+	}
+
+	b.SendMessage(b.BuildCompanyIntegrationFields(chatID, company, ci, fmt.Sprintf("🧹 %s: очищено", field.Title)))
 }
 
+// HandleUpdateCompanyIntegration проверяет и сохраняет значение поля,
+// которое ждёт мастер. При ошибке диалог не прерывается: можно ввести ещё раз.
 func (b *Bot) HandleUpdateCompanyIntegration(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
-	companyID := b.pendingCompanyIDs[chatID]
+	companyID := b.GetPendingCompanyID(ctx, chatID)
+
+	field, ok := findIntegrationField(b.GetPendingField(ctx, chatID))
+	if !ok || field.Kind == integrationFieldFlag {
+		b.ClearChatState(ctx, chatID)
+		b.SendMessage(b.NewMessage(chatID, "Неизвестное действие."))
+		return
+	}
 
-	company, err := b.CompanySvc.GetByID(ctx, companyID)
+	// секреты не должны оставаться в истории чата
+	if field.IsSecret() {
+		b.DeleteMessage(chatID, message.MessageID)
+	}
+
+	company, err := b.checkCompanyAdmin(ctx, message.From.ID, companyID)
 	if err != nil {
 		b.SendErrorMessage(chatID, err)
 		return
 	}
 
-	_, err = b.CompanySvc.CreateOrUpdateCompanyIntegration(ctx, company.ID, message.Text)
+	ci, err := b.getCompanyIntegration(ctx, company.ID)
 	if err != nil {
 		b.SendErrorMessage(chatID, err)
 		return
 	}
 
-	config, err := b.BuildCompanyIntegrationsDetail(ctx, chatID, company)
+	value, err := b.validateIntegrationField(ctx, ci, field, message)
 	if err != nil {
+		warning := b.NewMessage(chatID, fmt.Sprintf("⚠️ %s: %v\n\nОтправьте другое значение или нажмите «Отмена».", field.Title, err))
+		warning.ParseMode = ""
+		b.SendPendingMessage(ctx, warning)
+		return
+	}
+
+	*field.text(ci) = &value
+	if ci, err = b.CompanySvc.SaveCompanyIntegration(ctx, company.ID, ci); err != nil {
 		b.SendErrorMessage(chatID, err)
 		return
 	}
 
-	b.ClearChatState(chatID)
+	notice := fmt.Sprintf("✅ %s: сохранено", field.Title)
+	if field.Kind == integrationFieldBotToken {
+		if api, err := newCheckBotAPI(value); err == nil {
+			if missing := chatsWithoutBot(api, ci); len(missing) > 0 {
+				notice += fmt.Sprintf("\n⚠️ `@%s` не состоит в чатах: %s", api.Self.UserName, strings.Join(missing, ", "))
+			}
+		}
+	}
+
+	b.ClearChatState(ctx, chatID)
 
-	b.SendMessage(*config)
+	b.SendMessage(b.BuildCompanyIntegrationFields(chatID, company, ci, notice))
+}
+
+// getCompanyIntegration возвращает настройки компании; если их ещё нет — пустые.
+func (b *Bot) getCompanyIntegration(ctx context.Context, companyID int64) (*domain.CompanyIntegration, error) {
+	ci, err := b.CompanySvc.GetCompanyIntegrationByID(ctx, companyID)
+	if errors.Is(err, appErr.ErrIntegrationNotFound) {
+		return &domain.CompanyIntegration{CompanyID: companyID}, nil
+	}
+	return ci, err
+}
+
+func (b *Bot) integrationFieldHint(field integrationField) string {
+	if field.Kind == integrationFieldChat {
+		return "Отправьте ID чата, @username канала или перешлите сюда сообщение из чата. " +
+			"Бот уведомлений должен состоять в этом чате."
+	}
+	return field.Hint
 }
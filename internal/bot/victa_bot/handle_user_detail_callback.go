@@ -14,7 +14,7 @@ func (b *Bot) HandleDetailUserCallback(ctx context.Context, callback *tgbotapi.C
 		return
 	}
 
-	b.ClearChatState(chatID)
+	b.ClearChatState(ctx, chatID)
 	b.SendMessage(*message)
 }
 
@@ -28,7 +28,7 @@ func (b *Bot) HandleBackToDetailUserCallback(ctx context.Context, callback *tgbo
 		return
 	}
 
-	b.ClearChatState(chatID)
+	b.ClearChatState(ctx, chatID)
 	b.EditMessage(messageID, *message)
 }
 
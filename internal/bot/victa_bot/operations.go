@@ -1,6 +1,7 @@
 package victa_bot
 
 import (
+	"context"
 	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/gorilla/schema"
@@ -10,26 +11,6 @@ import (
 	"victa/internal/domain"
 )
 
-func (b *Bot) AddPendingAppData(chatID int64, data PendingAppData) {
-	b.pendingAppData[chatID] = data
-}
-
-func (b *Bot) DeletePendingAppData(chatID int64) {
-	if _, ok := b.pendingAppData[chatID]; ok {
-		delete(b.pendingAppData, chatID)
-	}
-}
-
-func (b *Bot) AddPendingCompanyID(chatID int64, companyID int64) {
-	b.pendingCompanyIDs[chatID] = companyID
-}
-
-func (b *Bot) DeletePendingCompanyID(chatID int64) {
-	if _, ok := b.pendingCompanyIDs[chatID]; ok {
-		delete(b.pendingCompanyIDs, chatID)
-	}
-}
-
 func (b *Bot) GetAppDetailMessage(app *domain.App) string {
 	return fmt.Sprintf(
 		"📱 *%s | %s* \n\n*ID приложения*: `%d`\n*Создано*: %s\n*Обновлено*: %s",
@@ -59,6 +40,19 @@ func (b *Bot) GetCompanyDetailMessage(company *domain.Company) string {
 	)
 }
 
+// MaskSecret оставляет от секрета последние 4 символа,
+// короткие секреты скрывает целиком.
+func (b *Bot) MaskSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	r := []rune(s)
+	if len(r) <= 8 {
+		return "••••"
+	}
+	return "••••" + string(r[len(r)-4:])
+}
+
 func (b *Bot) GetRoleTitle(roleID int64) string {
 
 	switch roleID {
@@ -72,9 +66,14 @@ func (b *Bot) GetRoleTitle(roleID int64) string {
 }
 
 type CallbackParams struct {
-	UserID    int64 `schema:"user_id"`
-	CompanyID int64 `schema:"company_id"`
-	AppID     int64 `schema:"app_id"`
+	UserID     int64  `schema:"user_id"`
+	CompanyID  int64  `schema:"company_id"`
+	AppID      int64  `schema:"app_id"`
+	DeliveryID int64  `schema:"delivery_id"`
+	RuleID     int64  `schema:"rule_id"`
+	Provider   string `schema:"provider"`
+	BuildID    string `schema:"build_id"`
+	Field      string `schema:"field"`
 }
 
 var schemaDecoder = func() *schema.Decoder {
@@ -100,32 +99,119 @@ func (b *Bot) GetCallbackArgs(data string) (*CallbackParams, error) {
 	return &p, nil
 }
 
-func (b *Bot) AddChatState(chatID int64, state ChatState) {
-	b.states[chatID] = state
+// loadChatState читает диалог чата. Ошибка хранилища только логируется:
+// бот ответит так, будто диалога нет.
+func (b *Bot) loadChatState(ctx context.Context, chatID int64) *domain.ChatState {
+	st, err := b.ChatStateSvc.Get(ctx, chatID)
+	if err != nil {
+		b.Logger.Error("chat state %d: %v", chatID, err)
+		return &domain.ChatState{ChatID: chatID}
+	}
+	return st
+}
+
+func (b *Bot) updateChatState(ctx context.Context, chatID int64, fn func(st *domain.ChatState)) {
+	if err := b.ChatStateSvc.Update(ctx, chatID, fn); err != nil {
+		b.Logger.Error("save chat state %d: %v", chatID, err)
+	}
+}
+
+// GetChatState возвращает ввод, которого бот ждёт в чате.
+func (b *Bot) GetChatState(ctx context.Context, chatID int64) (ChatState, bool) {
+	st := b.loadChatState(ctx, chatID)
+	if st.State == nil {
+		return 0, false
+	}
+	return ChatState(*st.State), true
+}
+
+func (b *Bot) GetPendingAppData(ctx context.Context, chatID int64) PendingAppData {
+	st := b.loadChatState(ctx, chatID)
+	return PendingAppData{ID: st.AppID, Name: st.AppName, Slug: st.AppSlug}
 }
 
-func (b *Bot) DeleteChatState(chatID int64) {
-	delete(b.states, chatID)
+func (b *Bot) GetPendingCompanyID(ctx context.Context, chatID int64) int64 {
+	return b.loadChatState(ctx, chatID).CompanyID
 }
 
-func (b *Bot) ClearChatState(chatID int64) {
-	b.DeletePendingMessage(chatID)
-	b.DeleteChatState(chatID)
-	b.DeletePendingCompanyID(chatID)
-	b.DeletePendingAppData(chatID)
+func (b *Bot) GetPendingProvider(ctx context.Context, chatID int64) string {
+	return b.loadChatState(ctx, chatID).Provider
+}
+
+func (b *Bot) GetPendingField(ctx context.Context, chatID int64) string {
+	return b.loadChatState(ctx, chatID).IntegrationField
+}
+
+func (b *Bot) AddChatState(ctx context.Context, chatID int64, state ChatState) {
+	b.updateChatState(ctx, chatID, func(st *domain.ChatState) {
+		v := int(state)
+		st.State = &v
+	})
+}
+
+func (b *Bot) AddPendingAppData(ctx context.Context, chatID int64, data PendingAppData) {
+	b.updateChatState(ctx, chatID, func(st *domain.ChatState) {
+		st.AppID = data.ID
+		st.AppName = data.Name
+		st.AppSlug = data.Slug
+	})
+}
+
+func (b *Bot) AddPendingCompanyID(ctx context.Context, chatID int64, companyID int64) {
+	b.updateChatState(ctx, chatID, func(st *domain.ChatState) {
+		st.CompanyID = companyID
+	})
+}
+
+func (b *Bot) AddPendingProvider(ctx context.Context, chatID int64, provider string) {
+	b.updateChatState(ctx, chatID, func(st *domain.ChatState) {
+		st.Provider = provider
+	})
+}
+
+func (b *Bot) AddPendingField(ctx context.Context, chatID int64, field string) {
+	b.updateChatState(ctx, chatID, func(st *domain.ChatState) {
+		st.IntegrationField = field
+	})
+}
+
+// ClearChatState завершает диалог: удаляет его состояние и подсказки бота.
+func (b *Bot) ClearChatState(ctx context.Context, chatID int64) {
+	st, err := b.ChatStateSvc.Clear(ctx, chatID)
+	if err != nil {
+		b.Logger.Error("clear chat state %d: %v", chatID, err)
+		return
+	}
+	if st != nil {
+		b.deletePendingMessages(chatID, st.MessageIDs)
+	}
 }
 
 // SendPendingMessage отправляет сообщение и добавляет его ID в очередь для последующего удаления
-func (b *Bot) SendPendingMessage(config tgbotapi.MessageConfig) {
+func (b *Bot) SendPendingMessage(ctx context.Context, config tgbotapi.MessageConfig) {
 	sentMsg := b.SendMessage(config)
-	b.pendingMessages[config.ChatID] = append(b.pendingMessages[config.ChatID], sentMsg.MessageID)
+	if sentMsg == nil {
+		return
+	}
+	b.updateChatState(ctx, config.ChatID, func(st *domain.ChatState) {
+		st.MessageIDs = append(st.MessageIDs, sentMsg.MessageID)
+	})
 }
 
-func (b *Bot) DeletePendingMessage(chatID int64) {
-	if msgIDs, ok := b.pendingMessages[chatID]; ok {
-		for _, msgID := range msgIDs {
-			b.DeleteMessage(chatID, msgID)
-		}
-		delete(b.pendingMessages, chatID)
+func (b *Bot) deletePendingMessages(chatID int64, msgIDs []int) {
+	for _, msgID := range msgIDs {
+		b.DeleteMessage(chatID, msgID)
+	}
+}
+
+// purgeExpiredChatStates убирает брошенные диалоги вместе с их подсказками.
+func (b *Bot) purgeExpiredChatStates(ctx context.Context) {
+	expired, err := b.ChatStateSvc.PurgeExpired(ctx)
+	if err != nil {
+		b.Logger.Error("purge chat states: %v", err)
+		return
+	}
+	for _, st := range expired {
+		b.deletePendingMessages(st.ChatID, st.MessageIDs)
 	}
 }
@@ -1,5 +1,7 @@
 package victa_bot
 
+// ChatState хранится в chat_states числом: новые состояния добавляйте
+// только в конец списка.
 type ChatState int
 
 const (
@@ -13,4 +15,9 @@ const (
 	StateWaitingCreateAppSlug
 	StateWaitingUpdateAppName
 	StateWaitingUpdateAppSlug
+	StateWaitingUpdateAppIntegration
+	StateWaitingCreateRoutingRule
+	StateWaitingLinkIdentity
+	StateWaitingStartBuild
+	StateWaitingUpdateAppStores
 )
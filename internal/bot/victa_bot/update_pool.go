@@ -0,0 +1,147 @@
+package victa_bot
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"victa/internal/logger"
+)
+
+const (
+	// updateWorkers — сколько чатов бот обслуживает одновременно.
+	updateWorkers = 8
+	// updateQueueSize — сколько обновлений ждут своей очереди у одного воркера.
+	updateQueueSize = 64
+)
+
+// updateMetrics публикуется в /debug/vars на DEBUG_ADDR:
+//   - backlog — обновления в очередях, ещё не взятые в работу;
+//   - in_flight — обрабатываются прямо сейчас;
+//   - processed — обработано с запуска;
+//   - timeouts — обработчик не уложился в perUpdateTimeout;
+//   - queue_full — приём ждал, пока в очереди чата освободится место.
+var updateMetrics = expvar.NewMap("victa_bot_updates")
+
+// updatePool обрабатывает обновления параллельно, раскладывая их по воркерам
+// по chat ID: обновления одного чата идут строго по порядку, поэтому шаги
+// диалога не обгоняют друг друга, а медленный запрос одного пользователя
+// не задерживает остальных.
+type updatePool struct {
+	logger logger.Logger
+	handle func(ctx context.Context, upd tgbotapi.Update)
+	shards []chan tgbotapi.Update
+	wg     sync.WaitGroup
+
+	mu      sync.RWMutex
+	stopped bool
+}
+
+// errUpdatePoolStopped — бот остановлен и обновления больше не принимает.
+var errUpdatePoolStopped = errors.New("update pool is stopped")
+
+func newUpdatePool(logger logger.Logger, workers, queueSize int, handle func(ctx context.Context, upd tgbotapi.Update)) *updatePool {
+	p := &updatePool{
+		logger: logger,
+		handle: handle,
+		shards: make([]chan tgbotapi.Update, workers),
+	}
+	for i := range p.shards {
+		p.shards[i] = make(chan tgbotapi.Update, queueSize)
+	}
+	return p
+}
+
+// Start запускает воркеры. Обработчики получают ctx, отмена которого
+// прерывает незавершённые запросы.
+func (p *updatePool) Start(ctx context.Context) {
+	for _, shard := range p.shards {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.work(ctx, shard)
+		}()
+	}
+}
+
+// Stop закрывает очереди и ждёт, пока воркеры их разберут.
+func (p *updatePool) Stop() {
+	p.mu.Lock()
+	p.stopped = true
+	p.mu.Unlock()
+
+	for _, shard := range p.shards {
+		close(shard)
+	}
+	p.wg.Wait()
+}
+
+// Push ставит обновление в очередь его чата. Если очередь заполнена,
+// ждёт места, пока не отменят ctx. После Stop вернёт errUpdatePoolStopped.
+func (p *updatePool) Push(ctx context.Context, upd tgbotapi.Update) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.stopped {
+		return errUpdatePoolStopped
+	}
+
+	shard := p.shards[p.shardOf(upd)]
+
+	select {
+	case shard <- upd:
+	default:
+		updateMetrics.Add("queue_full", 1)
+		p.logger.Warn("update queue is full, update %d waits", upd.UpdateID)
+
+		select {
+		case shard <- upd:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	updateMetrics.Add("backlog", 1)
+	return nil
+}
+
+func (p *updatePool) work(ctx context.Context, shard <-chan tgbotapi.Update) {
+	for upd := range shard {
+		updateMetrics.Add("backlog", -1)
+		// после остановки очередь только вычерпывается
+		if ctx.Err() != nil {
+			continue
+		}
+
+		updateMetrics.Add("in_flight", 1)
+		updCtx, cancel := context.WithTimeout(ctx, perUpdateTimeout)
+		p.handle(updCtx, upd)
+		if errors.Is(updCtx.Err(), context.DeadlineExceeded) {
+			updateMetrics.Add("timeouts", 1)
+			p.logger.Warn("update %d: handler exceeded %s", upd.UpdateID, perUpdateTimeout)
+		}
+		cancel()
+		updateMetrics.Add("in_flight", -1)
+		updateMetrics.Add("processed", 1)
+	}
+}
+
+func (p *updatePool) shardOf(upd tgbotapi.Update) int {
+	chatID := updateChatID(upd)
+	if chatID < 0 {
+		chatID = -chatID
+	}
+	return int(chatID % int64(len(p.shards)))
+}
+
+// updateChatID возвращает чат, к которому относится обновление.
+// Для обновлений без чата берётся автор, чтобы его действия шли по порядку.
+func updateChatID(upd tgbotapi.Update) int64 {
+	if chat := upd.FromChat(); chat != nil {
+		return chat.ID
+	}
+	if user := upd.SentFrom(); user != nil {
+		return user.ID
+	}
+	return 0
+}
@@ -2,6 +2,7 @@ package victa_bot
 
 import (
 	"context"
+	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"strings"
 	"time"
@@ -12,17 +13,38 @@ import (
 // Bot хранит API и ссылку на БД
 type Bot struct {
 	*bot_common.BaseBot
-	BotTag     string
-	UserSvc    *service.UserService
-	CompanySvc *service.CompanyService
-	InviteSvc  *service.InviteService
-	AppSvc     *service.AppService
-	JwtSvc     *service.JWTService
-
-	states            map[int64]ChatState
-	pendingMessages   map[int64][]int
-	pendingCompanyIDs map[int64]int64
-	pendingAppData    map[int64]PendingAppData
+	BotTag       string
+	UserSvc      *service.UserService
+	CompanySvc   *service.CompanyService
+	InviteSvc    *service.InviteService
+	AppSvc       *service.AppService
+	JwtSvc       *service.JWTService
+	ArchiveSvc   *service.WebhookArchiveService
+	RoutingSvc   *service.RoutingService
+	IdentitySvc  *service.IdentityService
+	CodemagicSvc *service.CodemagicService
+	StoreSvc     *service.StoreService
+	ChatStateSvc *service.ChatStateService
+	Replayer     WebhookReplayer
+	Actions      NotificationActions
+
+	pool          *updatePool
+	webhookURL    string
+	webhookSecret string
+}
+
+// WebhookReplayer повторно прогоняет сохранённый вебхук через его обработчик.
+type WebhookReplayer interface {
+	Replay(ctx context.Context, companyID, deliveryID int64) (int, string, error)
+}
+
+// NotificationActions обрабатывает кнопки под уведомлениями, если компания
+// отправляет их этим же ботом.
+type NotificationActions interface {
+	Handles(data string) bool
+	HandleCallback(ctx context.Context, base *bot_common.BaseBot, callback *tgbotapi.CallbackQuery)
+	HandlesMessage(ctx context.Context, message *tgbotapi.Message) bool
+	HandleMessage(ctx context.Context, base *bot_common.BaseBot, message *tgbotapi.Message)
 }
 
 type PendingAppData struct {
@@ -40,44 +62,85 @@ func New(
 	is *service.InviteService,
 	as *service.AppService,
 	js *service.JWTService,
+	ws *service.WebhookArchiveService,
+	rs *service.RoutingService,
+	ids *service.IdentityService,
+	cms *service.CodemagicService,
+	ss *service.StoreService,
+	css *service.ChatStateService,
+	wr WebhookReplayer,
+	na NotificationActions,
 ) *Bot {
-	return &Bot{
-		BaseBot:    base,
-		BotTag:     botTag,
-		UserSvc:    us,
-		CompanySvc: cs,
-		InviteSvc:  is,
-		AppSvc:     as,
-		JwtSvc:     js,
-
-		states:            make(map[int64]ChatState),
-		pendingMessages:   make(map[int64][]int),
-		pendingCompanyIDs: make(map[int64]int64),
-		pendingAppData:    make(map[int64]PendingAppData),
+	b := &Bot{
+		BaseBot:      base,
+		BotTag:       botTag,
+		UserSvc:      us,
+		CompanySvc:   cs,
+		InviteSvc:    is,
+		AppSvc:       as,
+		JwtSvc:       js,
+		ArchiveSvc:   ws,
+		RoutingSvc:   rs,
+		IdentitySvc:  ids,
+		CodemagicSvc: cms,
+		StoreSvc:     ss,
+		ChatStateSvc: css,
+		Replayer:     wr,
+		Actions:      na,
 	}
+	b.pool = newUpdatePool(b.Logger, updateWorkers, updateQueueSize, b.dispatch)
+	return b
 }
 
 const perUpdateTimeout = 10 * time.Second // в конфиг/const
 
+// chatStatePurgeInterval — как часто убирать брошенные диалоги.
+const chatStatePurgeInterval = 10 * time.Minute
+
+// Run принимает обновления, пока не отменят ctx: в режиме webhook их
+// присылает Telegram на WebhookHandler, иначе бот читает их long polling'ом.
+// В обоих случаях обновления разбирает пул воркеров.
 func (b *Bot) Run(ctx context.Context) error {
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
+	b.pool.Start(ctx)
+	defer b.pool.Stop()
 
-	updates := b.BotAPI.GetUpdatesChan(u)
+	// в режиме webhook канал остаётся nil и select его не читает
+	var updates tgbotapi.UpdatesChannel
+	if b.webhookURL != "" {
+		if err := b.setWebhook(); err != nil {
+			return err
+		}
+	} else {
+		// getUpdates не работает, пока у бота зарегистрирован webhook
+		if _, err := b.BotAPI.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+			return fmt.Errorf("delete webhook: %w", err)
+		}
+
+		u := tgbotapi.NewUpdate(0)
+		u.Timeout = 60
+		updates = b.BotAPI.GetUpdatesChan(u)
+	}
+
+	purge := time.NewTicker(chatStatePurgeInterval)
+	defer purge.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 
+		case <-purge.C:
+			purgeCtx, cancel := context.WithTimeout(ctx, perUpdateTimeout)
+			b.purgeExpiredChatStates(purgeCtx)
+			cancel()
+
 		case upd, ok := <-updates:
 			if !ok {
 				return ctx.Err()
 			}
-
-			updCtx, cancel := context.WithTimeout(ctx, perUpdateTimeout)
-			b.dispatch(updCtx, upd)
-			cancel()
+			if err := b.pool.Push(ctx, upd); err != nil {
+				return err
+			}
 		}
 	}
 }
@@ -92,6 +155,11 @@ func (b *Bot) dispatch(ctx context.Context, upd tgbotapi.Update) {
 }
 
 func (b *Bot) handleMessage(ctx context.Context, msg *tgbotapi.Message) {
+	// ответ на запрос комментария под уведомлением
+	if b.Actions != nil && b.Actions.HandlesMessage(ctx, msg) {
+		b.Actions.HandleMessage(ctx, b.BaseBot, msg)
+		return
+	}
 	if msg.IsCommand() {
 		b.handleCommand(ctx, msg)
 		return
@@ -115,7 +183,7 @@ func (b *Bot) handleCommand(ctx context.Context, msg *tgbotapi.Message) {
 func (b *Bot) handleText(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
 
-	if state, exists := b.states[chatID]; exists {
+	if state, exists := b.GetChatState(ctx, chatID); exists {
 		switch state {
 		case StateWaitingCreateCompanyName:
 			b.HandleCompanyNameCreated(ctx, message)
@@ -124,13 +192,23 @@ func (b *Bot) handleText(ctx context.Context, message *tgbotapi.Message) {
 		case StateWaitingUpdateCompanyIntegration:
 			b.HandleUpdateCompanyIntegration(ctx, message)
 		case StateWaitingCreateAppName:
-			b.HandleAppNameCreated(message)
+			b.HandleAppNameCreated(ctx, message)
 		case StateWaitingCreateAppSlug:
 			b.HandleAppSlugCreated(ctx, message)
 		case StateWaitingUpdateAppName:
-			b.HandleAppNameUpdated(message)
+			b.HandleAppNameUpdated(ctx, message)
 		case StateWaitingUpdateAppSlug:
 			b.HandleAppSlugUpdated(ctx, message)
+		case StateWaitingUpdateAppIntegration:
+			b.HandleUpdateAppIntegration(ctx, message)
+		case StateWaitingCreateRoutingRule:
+			b.HandleRoutingRuleCreated(ctx, message)
+		case StateWaitingLinkIdentity:
+			b.HandleIdentityLinked(ctx, message)
+		case StateWaitingStartBuild:
+			b.HandleBuildStarted(ctx, message)
+		case StateWaitingUpdateAppStores:
+			b.HandleUpdateAppStores(ctx, message)
 		default:
 		}
 	}
@@ -141,6 +219,12 @@ func (b *Bot) isCallbackWithPrefix(data, prefix string) bool {
 }
 
 func (b *Bot) handleCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	// кнопки уведомлений сами отвечают на нажатие
+	if b.Actions != nil && b.Actions.Handles(callback.Data) {
+		b.Actions.HandleCallback(ctx, b.BaseBot, callback)
+		return
+	}
+
 	chatID := callback.Message.Chat.ID
 	data := callback.Data
 
@@ -148,17 +232,17 @@ func (b *Bot) handleCallback(ctx context.Context, callback *tgbotapi.CallbackQue
 
 	switch {
 	case b.isCallbackWithPrefix(data, CallbackConfirmOperation):
-		if state, exists := b.states[chatID]; exists {
+		if state, exists := b.GetChatState(ctx, chatID); exists {
 			switch state {
 			case StateWaitingConfirmDeleteCompany:
 				b.HandleConfirmDeleteCompanyCallback(ctx, callback)
-				b.ClearChatState(chatID)
+				b.ClearChatState(ctx, chatID)
 			case StateWaitingConfirmDeleteUser:
 				b.HandleConfirmDeleteUserCallback(ctx, callback)
-				b.ClearChatState(chatID)
+				b.ClearChatState(ctx, chatID)
 			case StateWaitingConfirmDeleteApp:
 				b.HandleConfirmDeleteAppCallback(ctx, callback)
-				b.ClearChatState(chatID)
+				b.ClearChatState(ctx, chatID)
 			default:
 				b.AnswerCallback(callback, "Неизвестное действие.")
 			}
@@ -166,75 +250,140 @@ func (b *Bot) handleCallback(ctx context.Context, callback *tgbotapi.CallbackQue
 			b.AnswerCallback(callback, "Неизвестное действие.")
 		}
 	case b.isCallbackWithPrefix(data, CallbackMainMenu):
-		b.ClearChatState(chatID)
+		b.ClearChatState(ctx, chatID)
 		b.HandleMainMenuCallback(ctx, callback)
 
 	case b.isCallbackWithPrefix(data, CallbackClearState):
-		b.ClearChatState(chatID)
+		b.ClearChatState(ctx, chatID)
 
 	case b.isCallbackWithPrefix(data, CallbackDeleteMessage):
-		b.HandleDeleteMessageCallback(callback)
+		b.HandleDeleteMessageCallback(ctx, callback)
 
 	case b.isCallbackWithPrefix(data, CallbackListCompany):
-		b.ClearChatState(chatID)
+		b.ClearChatState(ctx, chatID)
 		b.HandleListCompaniesCallback(ctx, callback)
 	case b.isCallbackWithPrefix(data, CallbackDetailCompany):
-		b.ClearChatState(chatID)
+		b.ClearChatState(ctx, chatID)
 		b.HandleDetailCompanyCallback(ctx, callback)
 	case b.isCallbackWithPrefix(data, CallbackCreateCompany):
-		b.ClearChatState(chatID)
-		b.HandleCreateCompanyCallback(callback)
+		b.ClearChatState(ctx, chatID)
+		b.HandleCreateCompanyCallback(ctx, callback)
 	case b.isCallbackWithPrefix(data, CallbackUpdateCompany):
-		b.ClearChatState(chatID)
-		b.HandleUpdateCompanyCallback(callback)
+		b.ClearChatState(ctx, chatID)
+		b.HandleUpdateCompanyCallback(ctx, callback)
 	case b.isCallbackWithPrefix(data, CallbackDeleteCompany):
-		b.ClearChatState(chatID)
-		b.HandleDeleteCompanyCallback(callback)
+		b.ClearChatState(ctx, chatID)
+		b.HandleDeleteCompanyCallback(ctx, callback)
 	case b.isCallbackWithPrefix(data, CallbackBackToDetailCompany):
-		b.ClearChatState(chatID)
+		b.ClearChatState(ctx, chatID)
 		b.HandleBackToDetailCompanyCallback(ctx, callback)
 
 	case b.isCallbackWithPrefix(data, CallbackCompanyIntegrations):
-		b.ClearChatState(chatID)
+		b.ClearChatState(ctx, chatID)
 		b.HandleCompanyIntegrationsCallback(ctx, callback)
 	case b.isCallbackWithPrefix(data, CallbackCreateJwtToken):
-		b.ClearChatState(chatID)
-		b.HandleCreateJwtToken(callback)
+		b.ClearChatState(ctx, chatID)
+		b.HandleCreateJwtToken(ctx, callback)
 	case b.isCallbackWithPrefix(data, CallbackUpdateCompanyIntegrations):
-		b.ClearChatState(chatID)
+		b.ClearChatState(ctx, chatID)
 		b.HandleUpdateCompanyIntegrationCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackEditIntegrationField):
+		b.ClearChatState(ctx, chatID)
+		b.HandleEditIntegrationFieldCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackClearIntegrationField):
+		b.ClearChatState(ctx, chatID)
+		b.HandleClearIntegrationFieldCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackListDelivery):
+		b.ClearChatState(ctx, chatID)
+		b.HandleListDeliveryCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackDetailDelivery):
+		b.ClearChatState(ctx, chatID)
+		b.HandleDetailDeliveryCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackReplayDelivery):
+		b.ClearChatState(ctx, chatID)
+		b.HandleReplayDeliveryCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackListRoutingRule):
+		b.ClearChatState(ctx, chatID)
+		b.HandleListRoutingRuleCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackCreateRoutingRule):
+		b.ClearChatState(ctx, chatID)
+		b.HandleCreateRoutingRuleCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackDeleteRoutingRule):
+		b.ClearChatState(ctx, chatID)
+		b.HandleDeleteRoutingRuleCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackMoveRoutingRule):
+		b.ClearChatState(ctx, chatID)
+		b.HandleMoveRoutingRuleCallback(ctx, callback)
+
+	case b.isCallbackWithPrefix(data, CallbackListIdentity):
+		b.ClearChatState(ctx, chatID)
+		b.HandleListIdentityCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackLinkIdentity):
+		b.ClearChatState(ctx, chatID)
+		b.HandleLinkIdentityCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackUnlinkIdentity):
+		b.ClearChatState(ctx, chatID)
+		b.HandleUnlinkIdentityCallback(ctx, callback)
 
 	case b.isCallbackWithPrefix(data, CallbackListUser):
-		b.ClearChatState(chatID)
+		b.ClearChatState(ctx, chatID)
 		b.HandleListUsersCallback(ctx, callback)
 	case b.isCallbackWithPrefix(data, CallbackInviteUser):
-		b.ClearChatState(chatID)
+		b.ClearChatState(ctx, chatID)
 		b.HandleInviteUserCallback(ctx, callback)
 	case b.isCallbackWithPrefix(data, CallbackDetailUser):
-		b.ClearChatState(chatID)
+		b.ClearChatState(ctx, chatID)
 		b.HandleDetailUserCallback(ctx, callback)
 	case b.isCallbackWithPrefix(data, CallbackDeleteUser):
-		b.ClearChatState(chatID)
-		b.HandleDeleteUserCallback(callback)
+		b.ClearChatState(ctx, chatID)
+		b.HandleDeleteUserCallback(ctx, callback)
 	case b.isCallbackWithPrefix(data, CallbackBackToDetailUser):
-		b.ClearChatState(chatID)
+		b.ClearChatState(ctx, chatID)
 		b.HandleBackToDetailUserCallback(ctx, callback)
 
 	case b.isCallbackWithPrefix(data, CallbackListApp):
-		b.ClearChatState(chatID)
+		b.ClearChatState(ctx, chatID)
 		b.HandleListAppsCallback(ctx, callback)
 	case b.isCallbackWithPrefix(data, CallbackCreateApp):
-		b.ClearChatState(chatID)
-		b.HandleCreateAppCallback(callback)
+		b.ClearChatState(ctx, chatID)
+		b.HandleCreateAppCallback(ctx, callback)
 	case b.isCallbackWithPrefix(data, CallbackUpdateApp):
-		b.ClearChatState(chatID)
-		b.HandleUpdateAppCallback(callback)
+		b.ClearChatState(ctx, chatID)
+		b.HandleUpdateAppCallback(ctx, callback)
 	case b.isCallbackWithPrefix(data, CallbackDetailApp):
-		b.ClearChatState(chatID)
+		b.ClearChatState(ctx, chatID)
 		b.HandleDetailAppCallback(ctx, callback)
 	case b.isCallbackWithPrefix(data, CallbackDeleteApp):
-		b.ClearChatState(chatID)
-		b.HandleDeleteAppCallback(callback)
+		b.ClearChatState(ctx, chatID)
+		b.HandleDeleteAppCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackBackToDetailApp):
+		b.ClearChatState(ctx, chatID)
+		b.HandleBackToDetailAppCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackAppIntegrations):
+		b.ClearChatState(ctx, chatID)
+		b.HandleAppIntegrationsCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackUpdateAppIntegrations):
+		b.ClearChatState(ctx, chatID)
+		b.HandleUpdateAppIntegrationCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackAppStores):
+		b.ClearChatState(ctx, chatID)
+		b.HandleAppStoresCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackUpdateAppStores):
+		b.ClearChatState(ctx, chatID)
+		b.HandleUpdateAppStoresCallback(ctx, callback)
+
+	case b.isCallbackWithPrefix(data, CallbackListBuild):
+		b.ClearChatState(ctx, chatID)
+		b.HandleListBuildCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackStartBuild):
+		b.ClearChatState(ctx, chatID)
+		b.HandleStartBuildCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackCancelBuild):
+		b.ClearChatState(ctx, chatID)
+		b.HandleCancelBuildCallback(ctx, callback)
+	case b.isCallbackWithPrefix(data, CallbackRebuildBuild):
+		b.ClearChatState(ctx, chatID)
+		b.HandleRebuildBuildCallback(ctx, callback)
 
 	default:
 		b.AnswerCallback(callback, "Неизвестное действие.")
@@ -0,0 +1,69 @@
+package victa_bot
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"victa/internal/domain"
+)
+
+// WebhookSecretHeader — заголовок, в котором Telegram присылает secret_token,
+// указанный при регистрации webhook.
+const WebhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// WithWebhook переключает бота с long polling на webhook: при запуске Run
+// зарегистрирует url в Telegram, а обновления будут приходить на WebhookHandler.
+// В отличие от getUpdates, webhook позволяет запускать несколько реплик.
+func (b *Bot) WithWebhook(url, secret string) *Bot {
+	b.webhookURL = url
+	b.webhookSecret = secret
+	return b
+}
+
+func (b *Bot) setWebhook() error {
+	params := tgbotapi.Params{
+		"url":          b.webhookURL,
+		"secret_token": b.webhookSecret,
+	}
+	if _, err := b.BotAPI.MakeRequest("setWebhook", params); err != nil {
+		return fmt.Errorf("set webhook: %w", err)
+	}
+	b.Logger.Info("Telegram webhook: %s", b.webhookURL)
+	return nil
+}
+
+// WebhookHandler принимает обновления от Telegram и передаёт их тому же
+// пулу воркеров, что и long polling. Запросы без верного секрета отклоняются.
+func (b *Bot) WebhookHandler(c *gin.Context) {
+	secret := c.GetHeader(WebhookSecretHeader)
+	if b.webhookSecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(b.webhookSecret)) != 1 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, domain.ApiResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "invalid secret token",
+		})
+		return
+	}
+
+	var upd tgbotapi.Update
+	if err := c.ShouldBindJSON(&upd); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, domain.ApiResponse{
+			Status:  http.StatusBadRequest,
+			Message: "invalid update",
+		})
+		return
+	}
+
+	// пока очередь занята, Telegram подождёт ответа и при ошибке повторит запрос
+	if err := b.pool.Push(c.Request.Context(), upd); err != nil {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, domain.ApiResponse{
+			Status:  http.StatusServiceUnavailable,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -21,8 +21,27 @@ type Config struct {
 	APIPort          string
 	CodemagicAPIHost string
 	ENV              string
+
+	// DebugAddr — внутренний адрес для /debug/vars (например 127.0.0.1:6060).
+	// Пустой — метрики не публикуются.
+	DebugAddr string
+
+	// IntegrationEncryptionKeys — мастер‑ключи для секретов интеграций
+	// в виде «id:base64,…»; первый шифрует, остальные только расшифровывают.
+	IntegrationEncryptionKeys string
+
+	// TelegramUpdatesMode — как victa_bot получает обновления:
+	// polling (по умолчанию, только одна реплика) или webhook.
+	TelegramUpdatesMode   string
+	TelegramWebhookURL    string
+	TelegramWebhookSecret string
 }
 
+const (
+	TelegramUpdatesPolling = "polling"
+	TelegramUpdatesWebhook = "webhook"
+)
+
 // Load читает переменные окружения и возвращает Config.
 // Если .env не найден или отсутствует обязательная переменная — ошибка поднимается наверх.
 func Load() (*Config, error) {
@@ -51,11 +70,29 @@ func Load() (*Config, error) {
 		APIPort:          mustEnv("API_PORT"),
 		CodemagicAPIHost: mustEnv("CODEMAGIC_API_HOST"),
 		ENV:              mustEnv("ENV"),
+
+		IntegrationEncryptionKeys: mustEnv("INTEGRATION_ENCRYPTION_KEYS"),
+
+		TelegramUpdatesMode: os.Getenv("TELEGRAM_UPDATES_MODE"),
+		DebugAddr:           os.Getenv("DEBUG_ADDR"),
+	}
+
+	switch cfg.TelegramUpdatesMode {
+	case "", TelegramUpdatesPolling:
+		cfg.TelegramUpdatesMode = TelegramUpdatesPolling
+	case TelegramUpdatesWebhook:
+		cfg.TelegramWebhookURL = mustEnv("TELEGRAM_WEBHOOK_URL")
+		cfg.TelegramWebhookSecret = mustEnv("TELEGRAM_WEBHOOK_SECRET")
+	default:
+		return nil, fmt.Errorf("unknown TELEGRAM_UPDATES_MODE %q", cfg.TelegramUpdatesMode)
 	}
 
 	if len(missing) > 0 {
 		return nil, errors.New("missing env vars: " + strings.Join(missing, ", "))
 	}
+	if cfg.TelegramUpdatesMode == TelegramUpdatesWebhook && !validWebhookSecret(cfg.TelegramWebhookSecret) {
+		return nil, errors.New("TELEGRAM_WEBHOOK_SECRET must be 1-256 characters of A-Z, a-z, 0-9, _ and -")
+	}
 	return cfg, nil
 }
 
@@ -66,3 +103,18 @@ func (c *Config) GetDbDSN() string {
 		c.DBUser, c.DBPassword, c.DBHost, c.DBPort, c.DBName,
 	)
 }
+
+// validWebhookSecret проверяет secret_token по правилам Telegram Bot API.
+func validWebhookSecret(s string) bool {
+	if len(s) == 0 || len(s) > 256 {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
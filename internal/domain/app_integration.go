@@ -0,0 +1,46 @@
+package domain
+
+// AppIntegration связывает приложение Victa с проектами во внешних сервисах
+// и задаёт чаты приложения. Пустой чат — уведомление уходит в чат компании.
+type AppIntegration struct {
+	AppID            int64   `json:"app_id"`
+	CodemagicAppID   *string `json:"codemagic_app_id"`
+	GitlabProjectID  *string `json:"gitlab_project_id"`
+	BugsnagProjectID *string `json:"bugsnag_project_id"`
+	GithubRepository *string `json:"github_repository"`
+	SentryProject    *string `json:"sentry_project"`
+
+	DeployNotificationChatID        *string `json:"deploy_notification_chat_id"`
+	IssuesNotificationChatID        *string `json:"issues_notification_chat_id"`
+	ErrorsNotificationChatID        *string `json:"errors_notification_chat_id"`
+	MergeRequestsNotificationChatID *string `json:"merge_requests_notification_chat_id"`
+	PipelinesNotificationChatID     *string `json:"pipelines_notification_chat_id"`
+	ReviewsNotificationChatID       *string `json:"reviews_notification_chat_id"`
+
+	// UploadArtifacts переопределяет настройку компании; nil — как у компании.
+	UploadArtifacts *bool `json:"upload_artifacts"`
+}
+
+// ForApp возвращает копию настроек компании, где чаты заменены
+// на чаты приложения там, где они заданы. app может быть nil.
+func (ci CompanyIntegration) ForApp(app *AppIntegration) *CompanyIntegration {
+	if app == nil {
+		return &ci
+	}
+
+	override := func(dst **string, src *string) {
+		if src != nil && *src != "" {
+			*dst = src
+		}
+	}
+	override(&ci.DeployNotificationChatID, app.DeployNotificationChatID)
+	override(&ci.IssuesNotificationChatID, app.IssuesNotificationChatID)
+	override(&ci.ErrorsNotificationChatID, app.ErrorsNotificationChatID)
+	override(&ci.MergeRequestsNotificationChatID, app.MergeRequestsNotificationChatID)
+	override(&ci.PipelinesNotificationChatID, app.PipelinesNotificationChatID)
+	override(&ci.ReviewsNotificationChatID, app.ReviewsNotificationChatID)
+	if app.UploadArtifacts != nil {
+		ci.UploadArtifacts = app.UploadArtifacts
+	}
+	return &ci
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// ArtifactFile — артефакт Codemagic, уже загруженный в Telegram.
+// По FileID бот пересылает файл повторно без скачивания и загрузки.
+type ArtifactFile struct {
+	CompanyID    int64     `json:"company_id"`
+	ArtifactPath string    `json:"artifact_path"`
+	FileID       string    `json:"file_id"`
+	FileName     string    `json:"file_name"`
+	Size         int64     `json:"size"`
+	CreatedAt    time.Time `json:"created_at"`
+}
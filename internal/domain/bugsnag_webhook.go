@@ -4,6 +4,7 @@ import "time"
 
 type BugsnagWebhook struct {
 	Project struct {
+		ID   string `json:"id"`
 		Name string `json:"name"`
 		URL  string `json:"url"`
 	} `json:"project"`
@@ -11,14 +12,18 @@ type BugsnagWebhook struct {
 		Type string `json:"type"`
 	} `json:"trigger"`
 	Error struct {
-		Message       string     `json:"message"`
-		URL           string     `json:"url"`
-		Status        string     `json:"status"`
-		Unhandled     bool       `json:"unhandled"`
-		Occurrences   int64      `json:"occurrences"`
-		FirstReceived *time.Time `json:"firstReceived"`
-		ReceivedAt    *time.Time `json:"receivedAt"`
-		UserID        string     `json:"userId"`
+		ID            string       `json:"id"`
+		ErrorID       string       `json:"errorId"`
+		Message       string       `json:"message"`
+		URL           string       `json:"url"`
+		Status        string       `json:"status"`
+		Severity      string       `json:"severity"`
+		Unhandled     bool         `json:"unhandled"`
+		Occurrences   int64        `json:"occurrences"`
+		FirstReceived *time.Time   `json:"firstReceived"`
+		ReceivedAt    *time.Time   `json:"receivedAt"`
+		UserID        string       `json:"userId"`
+		AssignedTo    *BugsnagUser `json:"assignedTo"`
 		App           struct {
 			ID          string `json:"id"`
 			Version     string `json:"version"`
@@ -51,3 +56,9 @@ type BugsnagWebhook struct {
 		} `json:"exceptions"`
 	} `json:"error"`
 }
+
+// BugsnagUser — участник проекта Bugsnag, например ответственный за ошибку.
+type BugsnagUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// BuildMessage связывает билд Codemagic с сообщением в Telegram,
+// которое редактируется по мере выполнения сборки.
+type BuildMessage struct {
+	BuildID   string    `json:"build_id"`
+	CompanyID int64     `json:"company_id"`
+	AppID     string    `json:"app_id"`
+	ChatID    string    `json:"chat_id"`
+	MessageID int       `json:"message_id"`
+	Status    string    `json:"status"`
+	Finished  bool      `json:"finished"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
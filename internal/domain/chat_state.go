@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// ChatState — незаконченный диалог с victa_bot: какой ввод ждёт бот,
+// что пользователь уже ввёл на прошлых шагах и какие подсказки
+// нужно удалить из чата, когда диалог закончится.
+type ChatState struct {
+	ChatID           int64     `json:"chat_id"`
+	State            *int      `json:"state,omitempty"`
+	MessageIDs       []int     `json:"message_ids"`
+	CompanyID        int64     `json:"company_id"`
+	AppID            int64     `json:"app_id"`
+	AppName          string    `json:"app_name"`
+	AppSlug          string    `json:"app_slug"`
+	Provider         string    `json:"provider"`
+	IntegrationField string    `json:"integration_field"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
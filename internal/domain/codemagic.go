@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // CodemagicApplication описывает часть "application" ответа.
 type CodemagicApplication struct {
@@ -13,12 +16,17 @@ type CodemagicApplication struct {
 
 type CodemagicBuild struct {
 	ID         string    `json:"_id"`
+	AppID      string    `json:"appId"`
+	WorkflowID string    `json:"workflowId"`
+	Branch     string    `json:"branch"`
+	Index      int       `json:"index"`
 	Status     string    `json:"status"`
 	Version    string    `json:"version"`
 	StartedAt  time.Time `json:"startedAt"`
 	FinishedAt time.Time `json:"finishedAt"`
 	Commit     struct {
 		AuthorName    string `json:"authorName"`
+		AuthorEmail   string `json:"authorEmail"`
 		CommitMessage string `json:"commitMessage"`
 		Branch        string `json:"branch"`
 	} `json:"commit"`
@@ -33,12 +41,125 @@ type CodemagicBuild struct {
 		Name   string `json:"name"`
 		Status string `json:"status"`
 	} `json:"buildActions"`
-	Message   string `json:"message"`
-	Artefacts []struct {
-		Type      string `json:"type"`
-		Path      string `json:"path"`
-		PublicURL string `json:"public_url"`
-	} `json:"artefacts"`
+	Message   string              `json:"message"`
+	Artefacts []CodemagicArtefact `json:"artefacts"`
+}
+
+// CodemagicArtefact — файл, собранный билдом.
+// В ответе API есть path, в нативном вебхуке — только url.
+type CodemagicArtefact struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Path        string `json:"path"`
+	URL         string `json:"url"`
+	Size        int64  `json:"size"`
+	VersionName string `json:"versionName"`
+	PublicURL   string `json:"public_url"`
+}
+
+// ArtifactPath возвращает путь артефакта для запроса публичной ссылки.
+func (a CodemagicArtefact) ArtifactPath() string {
+	if a.Path != "" {
+		return a.Path
+	}
+	if i := strings.Index(a.URL, "/artifacts/"); i >= 0 {
+		return a.URL[i+len("/artifacts/"):]
+	}
+	return ""
+}
+
+// Виды артефактов Codemagic, которыми можно делиться в чатах.
+const (
+	ArtefactKindAPK  = "apk"
+	ArtefactKindAAB  = "aab"
+	ArtefactKindIPA  = "ipa"
+	ArtefactKindDSYM = "dsym"
+	ArtefactKindApp  = "app"
+	ArtefactKindWeb  = "web"
+	ArtefactKindTest = "test"
+)
+
+// Платформы для группировки артефактов в уведомлении, в порядке вывода.
+const (
+	ArtefactPlatformAndroid = "Android"
+	ArtefactPlatformIOS     = "iOS"
+	ArtefactPlatformMacOS   = "macOS"
+	ArtefactPlatformWeb     = "Web"
+	ArtefactPlatformTests   = "Тесты"
+	ArtefactPlatformOther   = "Другое"
+)
+
+// ArtefactPlatforms — порядок групп в уведомлении.
+var ArtefactPlatforms = []string{
+	ArtefactPlatformAndroid,
+	ArtefactPlatformIOS,
+	ArtefactPlatformMacOS,
+	ArtefactPlatformWeb,
+	ArtefactPlatformTests,
+	ArtefactPlatformOther,
+}
+
+var artefactPlatformByKind = map[string]string{
+	ArtefactKindAPK:  ArtefactPlatformAndroid,
+	ArtefactKindAAB:  ArtefactPlatformAndroid,
+	ArtefactKindIPA:  ArtefactPlatformIOS,
+	ArtefactKindDSYM: ArtefactPlatformIOS,
+	ArtefactKindApp:  ArtefactPlatformMacOS,
+	"pkg":            ArtefactPlatformMacOS,
+	"dmg":            ArtefactPlatformMacOS,
+	ArtefactKindWeb:  ArtefactPlatformWeb,
+	ArtefactKindTest: ArtefactPlatformTests,
+}
+
+// Kind возвращает вид артефакта в нижнем регистре. Codemagic не всегда
+// заполняет type, а dSYM, веб‑сборки и отчёты тестов приходят zip/xml,
+// поэтому вид уточняется по имени файла.
+func (a CodemagicArtefact) Kind() string {
+	name := strings.ToLower(a.Name)
+	kind := strings.ToLower(a.Type)
+	if kind == "" {
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			kind = name[i+1:]
+		}
+	}
+
+	switch {
+	case strings.Contains(name, ".dsym"):
+		return ArtefactKindDSYM
+	case kind == "xml", kind == "zip" && strings.Contains(name, "test"):
+		return ArtefactKindTest
+	case kind == "zip" && strings.Contains(name, "web"):
+		return ArtefactKindWeb
+	}
+	return kind
+}
+
+// Platform возвращает группу артефакта для уведомления.
+func (a CodemagicArtefact) Platform() string {
+	if p, ok := artefactPlatformByKind[a.Kind()]; ok {
+		return p
+	}
+	return ArtefactPlatformOther
+}
+
+// IsFinished — билд в конечном статусе, дальше изменений не будет.
+func (b CodemagicBuild) IsFinished() bool {
+	switch strings.ToLower(b.Status) {
+	case "finished", "success", "failed", "canceled", "cancel", "timeout", "skipped":
+		return true
+	default:
+		return false
+	}
+}
+
+// CodemagicWebhook — тело запроса на /webhook/codemagic. Поддерживает
+// нативный вебхук Codemagic ({"app", "build"}), формат ответа API
+// ({"application", "build"}) и старый вариант {"build_id"} из post‑build скрипта.
+type CodemagicWebhook struct {
+	BuildID     string                `json:"build_id"`
+	Build       *CodemagicBuild       `json:"build"`
+	App         *CodemagicApplication `json:"app"`
+	Application *CodemagicApplication `json:"application"`
 }
 
 // CodemagicBuildResponse объединяет application + build
@@ -46,3 +167,11 @@ type CodemagicBuildResponse struct {
 	Application CodemagicApplication `json:"application"`
 	Build       CodemagicBuild       `json:"build"`
 }
+
+// CodemagicBuildRequest — параметры запуска билда через API.
+type CodemagicBuildRequest struct {
+	AppID      string            `json:"app_id"`
+	WorkflowID string            `json:"workflow_id"`
+	Branch     string            `json:"branch"`
+	Variables  map[string]string `json:"variables,omitempty"`
+}
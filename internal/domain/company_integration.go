@@ -1,10 +1,57 @@
 package domain
 
+import "strings"
+
+// CompanyIntegration — настройки интеграций компании. Поля с тегом
+// secret хранятся в БД зашифрованными и не показываются в чате целиком.
 type CompanyIntegration struct {
 	CompanyID                int64   `json:"company_id"`
-	CodemagicAPIKey          *string `json:"codemagic_api_key"`
-	NotificationBotToken     *string `json:"notification_bot_token"`
+	CodemagicAPIKey          *string `json:"codemagic_api_key" secret:"true"`
+	NotificationBotToken     *string `json:"notification_bot_token" secret:"true"`
 	DeployNotificationChatID *string `json:"deploy_notification_chat_id"`
 	IssuesNotificationChatID *string `json:"issues_notification_chat_id"`
 	ErrorsNotificationChatID *string `json:"errors_notification_chat_id"`
+
+	MergeRequestsNotificationChatID *string `json:"merge_requests_notification_chat_id"`
+	PipelinesNotificationChatID     *string `json:"pipelines_notification_chat_id"`
+	ReviewsNotificationChatID       *string `json:"reviews_notification_chat_id"`
+	GitlabAPIToken                  *string `json:"gitlab_api_token" secret:"true"`
+	GithubWebhookSecret             *string `json:"github_webhook_secret" secret:"true"`
+	SentryClientSecret              *string `json:"sentry_client_secret" secret:"true"`
+	GitlabWebhookToken              *string `json:"gitlab_webhook_token" secret:"true"`
+	BugsnagWebhookSecret            *string `json:"bugsnag_webhook_secret" secret:"true"`
+	BugsnagAPIToken                 *string `json:"bugsnag_api_token" secret:"true"`
+	GitlabBaseURL                   *string `json:"gitlab_base_url"`
+	UploadArtifacts                 *bool   `json:"upload_artifacts"`
+	ArtifactTypes                   *string `json:"artifact_types"`
+	ArtifactQRCodes                 *bool   `json:"artifact_qr_codes"`
+}
+
+// SendsArtifactQRCodes — к ссылкам на артефакты прикладываются QR‑коды.
+func (ci CompanyIntegration) SendsArtifactQRCodes() bool {
+	return ci.ArtifactQRCodes != nil && *ci.ArtifactQRCodes
+}
+
+// defaultArtifactTypes — что показываем, если компания не выбрала сама.
+var defaultArtifactTypes = []string{ArtefactKindAPK}
+
+// SharesArtifact — артефакт вида kind публикуется в чатах. Виды задаются
+// через запятую в artifact_types («apk,ipa,web»), «*» — все виды.
+func (ci CompanyIntegration) SharesArtifact(kind string) bool {
+	types := defaultArtifactTypes
+	if ci.ArtifactTypes != nil && strings.TrimSpace(*ci.ArtifactTypes) != "" {
+		types = strings.Split(*ci.ArtifactTypes, ",")
+	}
+	for _, t := range types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "*" || t == strings.ToLower(kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// UploadsArtifacts — APK/AAB отправляются в чат файлом, а не только ссылкой.
+func (ci CompanyIntegration) UploadsArtifacts() bool {
+	return ci.UploadArtifacts != nil && *ci.UploadArtifacts
 }
@@ -0,0 +1,100 @@
+package domain
+
+import "time"
+
+// GithubWebhook объединяет поля событий issues, issue_comment, pull_request,
+// workflow_run и release. Тип события приходит в заголовке X-GitHub-Event.
+type GithubWebhook struct {
+	Action      string             `json:"action"`
+	Issue       *GithubIssue       `json:"issue"`
+	Comment     *GithubComment     `json:"comment"`
+	PullRequest *GithubPullRequest `json:"pull_request"`
+	WorkflowRun *GithubWorkflowRun `json:"workflow_run"`
+	Release     *GithubRelease     `json:"release"`
+	Repository  struct {
+		ID       int64  `json:"id"`
+		Name     string `json:"name"`
+		FullName string `json:"full_name"`
+		HTMLURL  string `json:"html_url"`
+	} `json:"repository"`
+	Sender GithubUser `json:"sender"`
+}
+
+type GithubUser struct {
+	Login   string `json:"login"`
+	HTMLURL string `json:"html_url"`
+}
+
+type GithubIssue struct {
+	Number      int           `json:"number"`
+	Title       string        `json:"title"`
+	Body        string        `json:"body"`
+	State       string        `json:"state"`
+	HTMLURL     string        `json:"html_url"`
+	Assignees   []GithubUser  `json:"assignees"`
+	Labels      []GithubLabel `json:"labels"`
+	PullRequest *struct {
+		HTMLURL string `json:"html_url"`
+	} `json:"pull_request"`
+}
+
+type GithubLabel struct {
+	Name string `json:"name"`
+}
+
+type GithubComment struct {
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+type GithubPullRequest struct {
+	Number             int          `json:"number"`
+	Title              string       `json:"title"`
+	Body               string       `json:"body"`
+	State              string       `json:"state"`
+	HTMLURL            string       `json:"html_url"`
+	Draft              bool         `json:"draft"`
+	Merged             bool         `json:"merged"`
+	MergeableState     string       `json:"mergeable_state"`
+	Assignees          []GithubUser `json:"assignees"`
+	RequestedReviewers []GithubUser `json:"requested_reviewers"`
+	Head               struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+type GithubWorkflowRun struct {
+	ID           int64      `json:"id"`
+	RunNumber    int64      `json:"run_number"`
+	Name         string     `json:"name"`
+	HeadBranch   string     `json:"head_branch"`
+	HeadSHA      string     `json:"head_sha"`
+	Event        string     `json:"event"`
+	Status       string     `json:"status"`
+	Conclusion   string     `json:"conclusion"`
+	HTMLURL      string     `json:"html_url"`
+	RunStartedAt time.Time  `json:"run_started_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	Actor        GithubUser `json:"actor"`
+	HeadCommit   struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"author"`
+	} `json:"head_commit"`
+}
+
+type GithubRelease struct {
+	Name    string `json:"name"`
+	TagName string `json:"tag_name"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+	Assets  []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
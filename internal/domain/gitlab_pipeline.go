@@ -0,0 +1,78 @@
+package domain
+
+// GitlabPipelineWebhook описывает событие object_kind = "pipeline".
+type GitlabPipelineWebhook struct {
+	ObjectKind       string `json:"object_kind"`
+	ObjectAttributes struct {
+		ID             int64    `json:"id"`
+		IID            int64    `json:"iid"`
+		Ref            string   `json:"ref"`
+		Tag            bool     `json:"tag"`
+		SHA            string   `json:"sha"`
+		Source         string   `json:"source"`
+		Status         string   `json:"status"`
+		DetailedStatus string   `json:"detailed_status"`
+		Stages         []string `json:"stages"`
+		Duration       *float64 `json:"duration"`
+		URL            string   `json:"url"`
+	} `json:"object_attributes"`
+	User    GitlabUser `json:"user"`
+	Project struct {
+		ID                int64  `json:"id"`
+		Name              string `json:"name"`
+		WebURL            string `json:"web_url"`
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	Commit struct {
+		ID      string `json:"id"`
+		Title   string `json:"title"`
+		Message string `json:"message"`
+		URL     string `json:"url"`
+		Author  struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"author"`
+	} `json:"commit"`
+	Builds []GitlabPipelineJob `json:"builds"`
+}
+
+// GitlabPipelineJob — задача пайплайна из массива builds.
+type GitlabPipelineJob struct {
+	ID            int64    `json:"id"`
+	Stage         string   `json:"stage"`
+	Name          string   `json:"name"`
+	Status        string   `json:"status"`
+	Duration      *float64 `json:"duration"`
+	FailureReason *string  `json:"failure_reason"`
+	AllowFailure  bool     `json:"allow_failure"`
+	Manual        bool     `json:"manual"`
+}
+
+// GitlabJobWebhook описывает событие object_kind = "build" (CI‑задача).
+type GitlabJobWebhook struct {
+	ObjectKind         string     `json:"object_kind"`
+	Ref                string     `json:"ref"`
+	Tag                bool       `json:"tag"`
+	SHA                string     `json:"sha"`
+	BuildID            int64      `json:"build_id"`
+	BuildName          string     `json:"build_name"`
+	BuildStage         string     `json:"build_stage"`
+	BuildStatus        string     `json:"build_status"`
+	BuildDuration      *float64   `json:"build_duration"`
+	BuildAllowFailure  bool       `json:"build_allow_failure"`
+	BuildFailureReason string     `json:"build_failure_reason"`
+	PipelineID         int64      `json:"pipeline_id"`
+	ProjectID          int64      `json:"project_id"`
+	ProjectName        string     `json:"project_name"`
+	User               GitlabUser `json:"user"`
+	Commit             struct {
+		SHA         string `json:"sha"`
+		Message     string `json:"message"`
+		AuthorName  string `json:"author_name"`
+		AuthorEmail string `json:"author_email"`
+	} `json:"commit"`
+	Repository struct {
+		Name     string `json:"name"`
+		Homepage string `json:"homepage"`
+	} `json:"repository"`
+}
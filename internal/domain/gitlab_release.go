@@ -0,0 +1,31 @@
+package domain
+
+// GitlabReleaseWebhook описывает событие object_kind = "release".
+type GitlabReleaseWebhook struct {
+	ObjectKind  string `json:"object_kind"`
+	Action      string `json:"action"`
+	Name        string `json:"name"`
+	Tag         string `json:"tag"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	Project     struct {
+		ID     int64  `json:"id"`
+		Name   string `json:"name"`
+		WebURL string `json:"web_url"`
+	} `json:"project"`
+	Assets struct {
+		Links []GitlabReleaseLink `json:"links"`
+	} `json:"assets"`
+	Commit struct {
+		Title  string `json:"title"`
+		Author struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+// GitlabReleaseLink — ссылка на файл релиза.
+type GitlabReleaseLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
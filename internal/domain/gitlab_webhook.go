@@ -8,16 +8,16 @@ import (
 )
 
 type GitlabWebhook struct {
-	ObjectKind string `json:"object_kind"`
-	User       struct {
-		Name string `json:"name"`
-	} `json:"user"`
-	Project struct {
+	ObjectKind string     `json:"object_kind"`
+	User       GitlabUser `json:"user"`
+	Project    struct {
+		ID        int64  `json:"id"`
 		Name      string `json:"name"`
 		Namespace string `json:"namespace"`
 		Homepage  string `json:"homepage"`
 	} `json:"project"`
-	ObjectAttributes Attributes `json:"object_attributes"`
+	ObjectAttributes Attributes    `json:"object_attributes"`
+	Labels           []GitlabLabel `json:"labels"`
 	Changes          struct {
 		CreatedAt   *DateTimeChange `json:"created_at"`
 		UpdatedAt   *DateTimeChange `json:"updated_at"`
@@ -27,7 +27,16 @@ type GitlabWebhook struct {
 			Current  string `json:"current"`
 		} `json:"description"`
 	} `json:"changes"`
-	Issue Attributes `json:"issue"`
+	Issue        Attributes   `json:"issue"`
+	MergeRequest Attributes   `json:"merge_request"`
+	Assignees    []GitlabUser `json:"assignees"`
+	Reviewers    []GitlabUser `json:"reviewers"`
+}
+
+// GitlabUser — автор события, исполнитель или ревьюер.
+type GitlabUser struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
 }
 
 type Attributes struct {
@@ -38,6 +47,22 @@ type Attributes struct {
 	URL         string `json:"url"`
 	Action      string `json:"action"`
 	State       string `json:"state"`
+
+	// note
+	NoteableType string `json:"noteable_type"`
+
+	// merge_request
+	SourceBranch        string `json:"source_branch"`
+	TargetBranch        string `json:"target_branch"`
+	MergeStatus         string `json:"merge_status"`
+	DetailedMergeStatus string `json:"detailed_merge_status"`
+	Draft               bool   `json:"draft"`
+	HeadPipelineID      *int64 `json:"head_pipeline_id"`
+}
+
+// GitlabLabel — метка задачи или MR.
+type GitlabLabel struct {
+	Title string `json:"title"`
 }
 
 // DateTimeChange описывает {previous, current} с GitLab-овским форматом.
@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// Notification — отрендеренное уведомление в очереди на отправку (outbox).
+type Notification struct {
+	ID            int64                  `json:"id"`
+	CompanyID     int64                  `json:"company_id"`
+	ChatID        string                 `json:"chat_id"`
+	Text          string                 `json:"text"`
+	Build         *NotificationBuild     `json:"build,omitempty"`
+	Buttons       [][]NotificationButton `json:"buttons,omitempty"`
+	Documents     []NotificationDocument `json:"documents,omitempty"`
+	QRCodes       []NotificationQRCode   `json:"qr_codes,omitempty"`
+	JobTrace      *NotificationJobTrace  `json:"job_trace,omitempty"`
+	Attempts      int                    `json:"attempts"`
+	NextAttemptAt time.Time              `json:"next_attempt_at"`
+	LastError     *string                `json:"last_error"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+// NotificationBuild помечает уведомление о билде Codemagic: такие сообщения
+// не отправляются заново, а редактируют уже отправленное (см. BuildMessage).
+type NotificationBuild struct {
+	BuildID  string `json:"build_id"`
+	AppID    string `json:"app_id"`
+	Status   string `json:"status"`
+	Finished bool   `json:"finished"`
+}
+
+// NotificationButton — inline‑кнопка под уведомлением. Нажатия разбирает
+// бот уведомлений компании.
+type NotificationButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// NotificationDocument — артефакт Codemagic, который нужно отправить файлом
+// в ответ на сообщение о билде. Файл скачивается при доставке.
+type NotificationDocument struct {
+	ArtifactPath string `json:"artifact_path"`
+	Name         string `json:"name"`
+	Size         int64  `json:"size"`
+}
+
+// NotificationQRCode — QR‑код со ссылкой на артефакт, который уходит фото
+// в ответ на сообщение о билде: с ним удобно ставить сборку на телефон.
+type NotificationQRCode struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// NotificationJobTrace — упавшая задача GitLab CI, хвост лога которой воркер
+// доставки скачивает и дописывает в карточку перед отправкой.
+type NotificationJobTrace struct {
+	ProjectID int64 `json:"project_id"`
+	JobID     int64 `json:"job_id"`
+}
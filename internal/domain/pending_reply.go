@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// PendingReply — запрос комментария (сообщение с ForceReply) под
+// уведомлением, на который бот ждёт ответ. Action — callback_data кнопки,
+// по которой запрос создан.
+type PendingReply struct {
+	ChatID    int64     `json:"chat_id"`
+	MessageID int       `json:"message_id"`
+	Action    string    `json:"action"`
+	BaseURL   string    `json:"base_url"`
+	TgUserID  int64     `json:"tg_user_id"` // ответ принимаем только от нажавшего
+	ExpiresAt time.Time `json:"expires_at"`
+}
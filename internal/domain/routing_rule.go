@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// Источники событий для правил маршрутизации. События GitHub приводятся
+// к моделям GitLab и маршрутизируются как gitlab.
+const (
+	RoutingSourceCodemagic = "codemagic"
+	RoutingSourceGitlab    = "gitlab"
+	RoutingSourceBugsnag   = "bugsnag"
+	RoutingSourceSentry    = "sentry"
+)
+
+// Действия правила: разослать в чаты или заглушить событие.
+const (
+	RoutingActionSend = "send"
+	RoutingActionMute = "mute"
+)
+
+// RoutingRule — правило маршрутизации уведомлений компании. Правила
+// проверяются по возрастанию Position, срабатывает первое подходящее.
+// Пустое условие (nil) подходит под любое событие.
+type RoutingRule struct {
+	ID          int64    `json:"id"`
+	CompanyID   int64    `json:"company_id"`
+	Position    int      `json:"position"`
+	Source      *string  `json:"source"`
+	AppID       *int64   `json:"app_id"`
+	Branch      *string  `json:"branch"`
+	Status      *string  `json:"status"`
+	TriggerType *string  `json:"trigger_type"`
+	Severity    *string  `json:"severity"`
+	Label       *string  `json:"label"`
+	Action      string   `json:"action"`
+	ChatIDs     []string `json:"chat_ids"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RoutingEvent — признаки входящего события, по которым сверяются правила.
+// Пустые поля означают, что у события такого признака нет.
+type RoutingEvent struct {
+	Source      string
+	AppID       int64
+	Branch      string
+	Status      string
+	TriggerType string
+	Severity    string
+	Labels      []string
+}
+
+// Matches проверяет, подходит ли событие под все условия правила.
+// Ветка сравнивается шаблоном (release/*), остальное — без учёта регистра.
+func (r RoutingRule) Matches(ev RoutingEvent) bool {
+	if r.AppID != nil && *r.AppID != ev.AppID {
+		return false
+	}
+	if r.Branch != nil {
+		if ok, _ := path.Match(*r.Branch, ev.Branch); !ok {
+			return false
+		}
+	}
+	if !matchFold(r.Source, ev.Source) ||
+		!matchFold(r.Status, ev.Status) ||
+		!matchFold(r.TriggerType, ev.TriggerType) ||
+		!matchFold(r.Severity, ev.Severity) {
+		return false
+	}
+	if r.Label != nil {
+		for _, l := range ev.Labels {
+			if strings.EqualFold(l, *r.Label) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchFold(cond *string, value string) bool {
+	return cond == nil || strings.EqualFold(*cond, value)
+}
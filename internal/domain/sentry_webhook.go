@@ -0,0 +1,92 @@
+package domain
+
+import "time"
+
+// SentryWebhook описывает вебхук интеграции Sentry. Тип ресурса приходит
+// в заголовке Sentry-Hook-Resource: event_alert (алерт по ошибке)
+// или metric_alert (алерт по метрике).
+type SentryWebhook struct {
+	Action string `json:"action"`
+	Data   struct {
+		Event         *SentryEvent       `json:"event"`
+		TriggeredRule string             `json:"triggered_rule"`
+		MetricAlert   *SentryMetricAlert `json:"metric_alert"`
+
+		DescriptionTitle string `json:"description_title"`
+		DescriptionText  string `json:"description_text"`
+		WebURL           string `json:"web_url"`
+	} `json:"data"`
+}
+
+// SentryEvent — событие из алерта event_alert.
+type SentryEvent struct {
+	EventID     string     `json:"event_id"`
+	IssueID     string     `json:"issue_id"`
+	Project     int64      `json:"project"`
+	Title       string     `json:"title"`
+	Message     string     `json:"message"`
+	Culprit     string     `json:"culprit"`
+	Level       string     `json:"level"`
+	Platform    string     `json:"platform"`
+	Release     string     `json:"release"`
+	Environment string     `json:"environment"`
+	WebURL      string     `json:"web_url"`
+	IssueURL    string     `json:"issue_url"`
+	Datetime    *time.Time `json:"datetime"`
+	User        *struct {
+		ID    string `json:"id"`
+		Email string `json:"email"`
+	} `json:"user"`
+	Contexts struct {
+		Device struct {
+			Manufacturer string  `json:"manufacturer"`
+			Model        string  `json:"model"`
+			Family       string  `json:"family"`
+			Orientation  string  `json:"orientation"`
+			Simulator    bool    `json:"simulator"`
+			MemorySize   int64   `json:"memory_size"`
+			FreeMemory   int64   `json:"free_memory"`
+			FreeStorage  int64   `json:"free_storage"`
+			BatteryLevel float64 `json:"battery_level"`
+			Charging     bool    `json:"charging"`
+		} `json:"device"`
+		OS struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+			Rooted  bool   `json:"rooted"`
+		} `json:"os"`
+		App struct {
+			AppVersion string `json:"app_version"`
+			AppBuild   string `json:"app_build"`
+		} `json:"app"`
+	} `json:"contexts"`
+	Exception struct {
+		Values []struct {
+			Type       string `json:"type"`
+			Value      string `json:"value"`
+			Stacktrace *struct {
+				Frames []struct {
+					Filename string `json:"filename"`
+					Function string `json:"function"`
+					Module   string `json:"module"`
+					LineNo   int    `json:"lineno"`
+					InApp    bool   `json:"in_app"`
+				} `json:"frames"`
+			} `json:"stacktrace"`
+		} `json:"values"`
+	} `json:"exception"`
+}
+
+// SentryMetricAlert — алерт по метрике (metric_alert).
+type SentryMetricAlert struct {
+	ID           string     `json:"id"`
+	Status       int        `json:"status"`
+	DateDetected *time.Time `json:"date_detected"`
+	AlertRule    struct {
+		Name       string   `json:"name"`
+		Aggregate  string   `json:"aggregate"`
+		Query      string   `json:"query"`
+		TimeWindow int      `json:"time_window"`
+		Projects   []string `json:"projects"`
+	} `json:"alert_rule"`
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// Слаги магазинов приложений из таблицы stores.
+const (
+	StoreSlugPlayStore  = "play_store"
+	StoreSlugAppStore   = "app_store"
+	StoreSlugRuStore    = "ru_store"
+	StoreSlugAppGallery = "app_gallery"
+)
+
+// StoreSlugs — магазины в порядке вывода в боте.
+var StoreSlugs = []string{StoreSlugPlayStore, StoreSlugAppStore, StoreSlugRuStore, StoreSlugAppGallery}
+
+// AppStoreState — приложение в магазине и последнее, что о нём видел
+// монитор: опубликованная версия и самый свежий отзыв. CheckedAt пуст,
+// пока монитор ни разу не опросил магазин.
+type AppStoreState struct {
+	AppID        int64      `json:"app_id"`
+	StoreID      int64      `json:"store_id"`
+	StoreSlug    string     `json:"store_slug"`
+	StoreName    string     `json:"store_name"`
+	StoreAppID   string     `json:"store_app_id"`
+	LastVersion  *string    `json:"last_version"`
+	LastReviewID *string    `json:"last_review_id"`
+	CheckedAt    *time.Time `json:"checked_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	CompanyID int64  `json:"company_id"`
+	AppName   string `json:"app_name"`
+}
+
+// StoreReview — отзыв о приложении в магазине.
+type StoreReview struct {
+	ID        string    `json:"id"`
+	Author    string    `json:"author"`
+	Rating    int       `json:"rating"`
+	Title     string    `json:"title"`
+	Text      string    `json:"text"`
+	Version   string    `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
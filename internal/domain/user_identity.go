@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Провайдеры учётных записей, которые пользователь связывает с Victa.
+const (
+	IdentityProviderGitlab   = "gitlab"    // логин GitLab
+	IdentityProviderGitEmail = "git_email" // email автора коммитов
+	IdentityProviderBugsnag  = "bugsnag"   // email в Bugsnag
+)
+
+// UserIdentity — учётная запись пользователя во внешнем сервисе.
+// По ней уведомления упоминают пользователя в Telegram.
+type UserIdentity struct {
+	UserID     int64     `json:"user_id"`
+	Provider   string    `json:"provider"`
+	ExternalID string    `json:"external_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// NormalizeIdentity приводит учётную запись к виду, в котором она хранится:
+// без пробелов по краям, ведущего «@» у логина и без учёта регистра.
+func NormalizeIdentity(provider, externalID string) string {
+	externalID = strings.ToLower(strings.TrimSpace(externalID))
+	if provider == IdentityProviderGitlab {
+		externalID = strings.TrimPrefix(externalID, "@")
+	}
+	return externalID
+}
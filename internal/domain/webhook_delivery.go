@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"net/http"
+	"time"
+)
+
+// Итог обработки входящего вебхука.
+const (
+	DeliveryOutcomeOK        = "ok"
+	DeliveryOutcomeIgnored   = "ignored"
+	DeliveryOutcomeDuplicate = "duplicate"
+	DeliveryOutcomeFailed    = "failed"
+)
+
+// WebhookDelivery — сохранённый входящий вебхук: сырое тело и заголовки
+// для отладки рендера и повторного прогона.
+type WebhookDelivery struct {
+	ID        int64             `json:"id"`
+	CompanyID int64             `json:"company_id"`
+	Provider  string            `json:"provider"`
+	Headers   map[string]string `json:"headers"`
+	Body      string            `json:"body"`
+	Status    int               `json:"status"`
+	Outcome   string            `json:"outcome"`
+	Error     *string           `json:"error"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// secretWebhookHeaders несут учётные данные: в архив они не сохраняются
+// и в чате не показываются. Подписи (X-Hub-Signature-256 и т.п.) не секретны
+// и остаются для отладки.
+var secretWebhookHeaders = map[string]bool{
+	"Authorization":  true,
+	"Cookie":         true,
+	"X-Gitlab-Token": true,
+}
+
+// IsSecretWebhookHeader — заголовок name содержит секрет.
+func IsSecretWebhookHeader(name string) bool {
+	return secretWebhookHeaders[http.CanonicalHeaderKey(name)]
+}
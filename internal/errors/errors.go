@@ -3,11 +3,19 @@ package errors
 import "errors"
 
 var (
-	ErrCompanyNotFound     = errors.New("company not found")
-	ErrRelationNotFound    = errors.New("user–company relation not found")
-	ErrRoleNotFound        = errors.New("role slug not found")
-	ErrAppNotFound         = errors.New("app not found")
-	ErrIntegrationNotFound = errors.New("company integration not found")
-	ErrUserCompanyNotFound = errors.New("user-company relation not found")
-	ErrUserNotFound        = errors.New("user not found")
+	ErrCompanyNotFound         = errors.New("company not found")
+	ErrRelationNotFound        = errors.New("user–company relation not found")
+	ErrRoleNotFound            = errors.New("role slug not found")
+	ErrAppNotFound             = errors.New("app not found")
+	ErrIntegrationNotFound     = errors.New("company integration not found")
+	ErrUserCompanyNotFound     = errors.New("user-company relation not found")
+	ErrUserNotFound            = errors.New("user not found")
+	ErrBuildMessageNotFound    = errors.New("build message not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrAppIntegrationNotFound  = errors.New("app integration not found")
+	ErrRoutingRuleNotFound     = errors.New("routing rule not found")
+	ErrIdentityTaken           = errors.New("identity is already linked to another user")
+	ErrArtifactFileNotFound    = errors.New("artifact file not found")
+	ErrChatStateNotFound       = errors.New("chat state not found")
+	ErrPendingReplyNotFound    = errors.New("pending reply not found")
 )
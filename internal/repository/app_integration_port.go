@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"victa/internal/domain"
+)
+
+type AppIntegrationRepository interface {
+	GetByAppID(ctx context.Context, appID int64) (*domain.AppIntegration, error)
+	GetByCodemagicAppID(ctx context.Context, companyID int64, codemagicAppID string) (*domain.AppIntegration, error)
+	GetByGitlabProject(ctx context.Context, companyID int64, projectID, projectPath string) (*domain.AppIntegration, error)
+	GetByBugsnagProjectID(ctx context.Context, companyID int64, projectID string) (*domain.AppIntegration, error)
+	GetByGithubRepository(ctx context.Context, companyID int64, repoID, fullName string) (*domain.AppIntegration, error)
+	GetBySentryProject(ctx context.Context, companyID int64, projectID, projectSlug string) (*domain.AppIntegration, error)
+	CreateOrUpdate(ctx context.Context, ai *domain.AppIntegration) (*domain.AppIntegration, error)
+}
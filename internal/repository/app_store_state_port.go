@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"victa/internal/domain"
+)
+
+type AppStoreStateRepository interface {
+	GetAll(ctx context.Context) ([]domain.AppStoreState, error)
+	GetByAppID(ctx context.Context, appID int64) ([]domain.AppStoreState, error)
+	Upsert(ctx context.Context, appID int64, storeSlug, storeAppID string) error
+	Delete(ctx context.Context, appID int64, storeSlug string) error
+	SaveProgress(ctx context.Context, st *domain.AppStoreState) error
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"victa/internal/domain"
+)
+
+type ArtifactFileRepository interface {
+	GetByPath(ctx context.Context, companyID int64, artifactPath string) (*domain.ArtifactFile, error)
+	Save(ctx context.Context, f *domain.ArtifactFile) (*domain.ArtifactFile, error)
+	Delete(ctx context.Context, companyID int64, artifactPath string) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"victa/internal/domain"
+)
+
+type BuildMessageRepository interface {
+	GetByBuildID(ctx context.Context, buildID, chatID string) (*domain.BuildMessage, error)
+	GetUnfinished(ctx context.Context, updatedAfter time.Time) ([]domain.BuildMessage, error)
+	Save(ctx context.Context, msg *domain.BuildMessage) (*domain.BuildMessage, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"victa/internal/domain"
+)
+
+type ChatStateRepository interface {
+	GetByChatID(ctx context.Context, chatID int64) (*domain.ChatState, error)
+	Save(ctx context.Context, st *domain.ChatState) error
+	Delete(ctx context.Context, chatID int64) (*domain.ChatState, error)
+	DeleteExpired(ctx context.Context, now time.Time) ([]domain.ChatState, error)
+}
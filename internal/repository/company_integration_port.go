@@ -8,4 +8,5 @@ import (
 type CompanyIntegrationRepository interface {
 	GetByID(ctx context.Context, companyID int64) (*domain.CompanyIntegration, error)
 	CreateOrUpdate(ctx context.Context, ci *domain.CompanyIntegration) (*domain.CompanyIntegration, error)
+	GetNotificationBotTokens(ctx context.Context) ([]string, error)
 }
@@ -0,0 +1,17 @@
+package repository
+
+import "context"
+
+// LeaderLock — удерживаемая блокировка. Она живёт, пока жива сессия БД,
+// в которой её взяли.
+type LeaderLock interface {
+	// Ping проверяет, что сессия с блокировкой ещё жива.
+	Ping(ctx context.Context) error
+	// Release отпускает блокировку и сессию.
+	Release() error
+}
+
+type LeaderLockRepository interface {
+	// TryAcquire берёт блокировку key без ожидания; nil — её держит другая реплика.
+	TryAcquire(ctx context.Context, key int64) (LeaderLock, error)
+}
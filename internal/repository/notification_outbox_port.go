@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"victa/internal/domain"
+)
+
+type NotificationOutboxRepository interface {
+	Enqueue(ctx context.Context, n *domain.Notification) (*domain.Notification, error)
+	EnqueueAll(ctx context.Context, ns []*domain.Notification) error
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.Notification, error)
+	Extend(ctx context.Context, ids []int64, lease time.Duration) error
+	Delete(ctx context.Context, id int64) error
+	Reschedule(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+	MoveToDeadLetter(ctx context.Context, id int64, lastError string) error
+}
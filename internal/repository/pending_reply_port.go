@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"victa/internal/domain"
+)
+
+type PendingReplyRepository interface {
+	Save(ctx context.Context, r *domain.PendingReply) error
+	Get(ctx context.Context, chatID int64, messageID int, now time.Time) (*domain.PendingReply, error)
+	Take(ctx context.Context, chatID int64, messageID int, now time.Time) (*domain.PendingReply, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
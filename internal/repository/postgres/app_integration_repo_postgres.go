@@ -0,0 +1,317 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	appErr "victa/internal/errors"
+
+	"victa/internal/domain"
+)
+
+// AppIntegrationRepo реализует AppIntegrationRepository через prepared‑statements.
+type AppIntegrationRepo struct {
+	db                      *sql.DB
+	stGetByAppID            *sql.Stmt
+	stGetByCodemagicAppID   *sql.Stmt
+	stGetByGitlabProject    *sql.Stmt
+	stGetByBugsnagProjectID *sql.Stmt
+	stGetByGithubRepository *sql.Stmt
+	stGetBySentryProject    *sql.Stmt
+	stCreateOrUpdate        *sql.Stmt
+}
+
+// NewAppIntegrationRepo подготавливает выражения; при ошибке сразу вернёт её.
+func NewAppIntegrationRepo(db *sql.DB) (*AppIntegrationRepo, error) {
+	r := &AppIntegrationRepo{db: db}
+	var err error
+
+	if r.stGetByAppID, err = db.Prepare(`
+		SELECT app_id,
+		       codemagic_app_id,
+		       gitlab_project_id,
+		       bugsnag_project_id,
+		       deploy_notification_chat_id,
+		       issues_notification_chat_id,
+		       errors_notification_chat_id,
+		       merge_requests_notification_chat_id,
+		       pipelines_notification_chat_id,
+		       upload_artifacts,
+		       reviews_notification_chat_id,
+		       github_repository,
+		       sentry_project
+		  FROM app_integrations
+		 WHERE app_id = $1`); err != nil {
+		return nil, fmt.Errorf("prepare getByAppID: %w", err)
+	}
+
+	if r.stGetByCodemagicAppID, err = db.Prepare(`
+		SELECT ai.app_id,
+		       ai.codemagic_app_id,
+		       ai.gitlab_project_id,
+		       ai.bugsnag_project_id,
+		       ai.deploy_notification_chat_id,
+		       ai.issues_notification_chat_id,
+		       ai.errors_notification_chat_id,
+		       ai.merge_requests_notification_chat_id,
+		       ai.pipelines_notification_chat_id,
+		       ai.upload_artifacts,
+		       ai.reviews_notification_chat_id,
+		       ai.github_repository,
+		       ai.sentry_project
+		  FROM app_integrations ai
+		  JOIN apps a ON a.id = ai.app_id
+		 WHERE a.company_id = $1
+		   AND ai.codemagic_app_id = $2
+		 LIMIT 1`); err != nil {
+		return nil, fmt.Errorf("prepare getByCodemagicAppID: %w", err)
+	}
+
+	// проект GitLab можно указать и числовым ID, и путём group/project
+	if r.stGetByGitlabProject, err = db.Prepare(`
+		SELECT ai.app_id,
+		       ai.codemagic_app_id,
+		       ai.gitlab_project_id,
+		       ai.bugsnag_project_id,
+		       ai.deploy_notification_chat_id,
+		       ai.issues_notification_chat_id,
+		       ai.errors_notification_chat_id,
+		       ai.merge_requests_notification_chat_id,
+		       ai.pipelines_notification_chat_id,
+		       ai.upload_artifacts,
+		       ai.reviews_notification_chat_id,
+		       ai.github_repository,
+		       ai.sentry_project
+		  FROM app_integrations ai
+		  JOIN apps a ON a.id = ai.app_id
+		 WHERE a.company_id = $1
+		   AND ai.gitlab_project_id IN ($2, $3)
+		 LIMIT 1`); err != nil {
+		return nil, fmt.Errorf("prepare getByGitlabProject: %w", err)
+	}
+
+	if r.stGetByBugsnagProjectID, err = db.Prepare(`
+		SELECT ai.app_id,
+		       ai.codemagic_app_id,
+		       ai.gitlab_project_id,
+		       ai.bugsnag_project_id,
+		       ai.deploy_notification_chat_id,
+		       ai.issues_notification_chat_id,
+		       ai.errors_notification_chat_id,
+		       ai.merge_requests_notification_chat_id,
+		       ai.pipelines_notification_chat_id,
+		       ai.upload_artifacts,
+		       ai.reviews_notification_chat_id,
+		       ai.github_repository,
+		       ai.sentry_project
+		  FROM app_integrations ai
+		  JOIN apps a ON a.id = ai.app_id
+		 WHERE a.company_id = $1
+		   AND ai.bugsnag_project_id = $2
+		 LIMIT 1`); err != nil {
+		return nil, fmt.Errorf("prepare getByBugsnagProjectID: %w", err)
+	}
+
+	// репозиторий GitHub можно указать и числовым ID, и как owner/repo
+	if r.stGetByGithubRepository, err = db.Prepare(`
+		SELECT ai.app_id,
+		       ai.codemagic_app_id,
+		       ai.gitlab_project_id,
+		       ai.bugsnag_project_id,
+		       ai.deploy_notification_chat_id,
+		       ai.issues_notification_chat_id,
+		       ai.errors_notification_chat_id,
+		       ai.merge_requests_notification_chat_id,
+		       ai.pipelines_notification_chat_id,
+		       ai.upload_artifacts,
+		       ai.reviews_notification_chat_id,
+		       ai.github_repository,
+		       ai.sentry_project
+		  FROM app_integrations ai
+		  JOIN apps a ON a.id = ai.app_id
+		 WHERE a.company_id = $1
+		   AND ai.github_repository IN ($2, $3)
+		 LIMIT 1`); err != nil {
+		return nil, fmt.Errorf("prepare getByGithubRepository: %w", err)
+	}
+
+	// проект Sentry можно указать и числовым ID, и slug'ом
+	if r.stGetBySentryProject, err = db.Prepare(`
+		SELECT ai.app_id,
+		       ai.codemagic_app_id,
+		       ai.gitlab_project_id,
+		       ai.bugsnag_project_id,
+		       ai.deploy_notification_chat_id,
+		       ai.issues_notification_chat_id,
+		       ai.errors_notification_chat_id,
+		       ai.merge_requests_notification_chat_id,
+		       ai.pipelines_notification_chat_id,
+		       ai.upload_artifacts,
+		       ai.reviews_notification_chat_id,
+		       ai.github_repository,
+		       ai.sentry_project
+		  FROM app_integrations ai
+		  JOIN apps a ON a.id = ai.app_id
+		 WHERE a.company_id = $1
+		   AND ai.sentry_project IN ($2, $3)
+		 LIMIT 1`); err != nil {
+		return nil, fmt.Errorf("prepare getBySentryProject: %w", err)
+	}
+
+	if r.stCreateOrUpdate, err = db.Prepare(`
+		INSERT INTO app_integrations (app_id,
+		                              codemagic_app_id,
+		                              gitlab_project_id,
+		                              bugsnag_project_id,
+		                              deploy_notification_chat_id,
+		                              issues_notification_chat_id,
+		                              errors_notification_chat_id,
+		                              merge_requests_notification_chat_id,
+		                              pipelines_notification_chat_id,
+		                              upload_artifacts,
+		                              reviews_notification_chat_id,
+		                              github_repository,
+		                              sentry_project)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (app_id) DO UPDATE
+		   SET codemagic_app_id                    = EXCLUDED.codemagic_app_id,
+		       gitlab_project_id                   = EXCLUDED.gitlab_project_id,
+		       bugsnag_project_id                  = EXCLUDED.bugsnag_project_id,
+		       deploy_notification_chat_id         = EXCLUDED.deploy_notification_chat_id,
+		       issues_notification_chat_id         = EXCLUDED.issues_notification_chat_id,
+		       errors_notification_chat_id         = EXCLUDED.errors_notification_chat_id,
+		       merge_requests_notification_chat_id = EXCLUDED.merge_requests_notification_chat_id,
+		       pipelines_notification_chat_id      = EXCLUDED.pipelines_notification_chat_id,
+		       upload_artifacts                    = EXCLUDED.upload_artifacts,
+		       reviews_notification_chat_id        = EXCLUDED.reviews_notification_chat_id,
+		       github_repository                   = EXCLUDED.github_repository,
+		       sentry_project                      = EXCLUDED.sentry_project
+		RETURNING app_id,
+		          codemagic_app_id,
+		          gitlab_project_id,
+		          bugsnag_project_id,
+		          deploy_notification_chat_id,
+		          issues_notification_chat_id,
+		          errors_notification_chat_id,
+		          merge_requests_notification_chat_id,
+		          pipelines_notification_chat_id,
+		          upload_artifacts,
+		          reviews_notification_chat_id,
+		          github_repository,
+		          sentry_project`); err != nil {
+		return nil, fmt.Errorf("prepare createOrUpdate: %w", err)
+	}
+
+	return r, nil
+}
+
+// Close освобождает prepared‑statements.
+func (r *AppIntegrationRepo) Close() error {
+	for _, st := range []*sql.Stmt{
+		r.stGetByAppID, r.stGetByCodemagicAppID, r.stGetByGitlabProject,
+		r.stGetByBugsnagProjectID, r.stGetByGithubRepository, r.stGetBySentryProject,
+		r.stCreateOrUpdate,
+	} {
+		if st != nil {
+			if err := st.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetByAppID возвращает интеграции приложения или ErrAppIntegrationNotFound.
+func (r *AppIntegrationRepo) GetByAppID(ctx context.Context, appID int64) (*domain.AppIntegration, error) {
+	return r.get(r.stGetByAppID.QueryRowContext(ctx, appID))
+}
+
+// GetByCodemagicAppID ищет приложение компании по ID приложения Codemagic.
+func (r *AppIntegrationRepo) GetByCodemagicAppID(ctx context.Context, companyID int64, codemagicAppID string) (*domain.AppIntegration, error) {
+	return r.get(r.stGetByCodemagicAppID.QueryRowContext(ctx, companyID, codemagicAppID))
+}
+
+// GetByGitlabProject ищет приложение компании по ID или пути проекта GitLab.
+func (r *AppIntegrationRepo) GetByGitlabProject(ctx context.Context, companyID int64, projectID, projectPath string) (*domain.AppIntegration, error) {
+	return r.get(r.stGetByGitlabProject.QueryRowContext(ctx, companyID, projectID, projectPath))
+}
+
+// GetByBugsnagProjectID ищет приложение компании по ID проекта Bugsnag.
+func (r *AppIntegrationRepo) GetByBugsnagProjectID(ctx context.Context, companyID int64, projectID string) (*domain.AppIntegration, error) {
+	return r.get(r.stGetByBugsnagProjectID.QueryRowContext(ctx, companyID, projectID))
+}
+
+// GetByGithubRepository ищет приложение компании по ID или имени owner/repo репозитория GitHub.
+func (r *AppIntegrationRepo) GetByGithubRepository(ctx context.Context, companyID int64, repoID, fullName string) (*domain.AppIntegration, error) {
+	return r.get(r.stGetByGithubRepository.QueryRowContext(ctx, companyID, repoID, fullName))
+}
+
+// GetBySentryProject ищет приложение компании по ID или slug'у проекта Sentry.
+func (r *AppIntegrationRepo) GetBySentryProject(ctx context.Context, companyID int64, projectID, projectSlug string) (*domain.AppIntegration, error) {
+	return r.get(r.stGetBySentryProject.QueryRowContext(ctx, companyID, projectID, projectSlug))
+}
+
+// CreateOrUpdate сохраняет интеграции приложения.
+func (r *AppIntegrationRepo) CreateOrUpdate(ctx context.Context, ai *domain.AppIntegration) (*domain.AppIntegration, error) {
+	var updated domain.AppIntegration
+	err := r.stCreateOrUpdate.QueryRowContext(ctx,
+		ai.AppID,
+		ai.CodemagicAppID,
+		ai.GitlabProjectID,
+		ai.BugsnagProjectID,
+		ai.DeployNotificationChatID,
+		ai.IssuesNotificationChatID,
+		ai.ErrorsNotificationChatID,
+		ai.MergeRequestsNotificationChatID,
+		ai.PipelinesNotificationChatID,
+		ai.UploadArtifacts,
+		ai.ReviewsNotificationChatID,
+		ai.GithubRepository,
+		ai.SentryProject,
+	).Scan(
+		&updated.AppID,
+		&updated.CodemagicAppID,
+		&updated.GitlabProjectID,
+		&updated.BugsnagProjectID,
+		&updated.DeployNotificationChatID,
+		&updated.IssuesNotificationChatID,
+		&updated.ErrorsNotificationChatID,
+		&updated.MergeRequestsNotificationChatID,
+		&updated.PipelinesNotificationChatID,
+		&updated.UploadArtifacts,
+		&updated.ReviewsNotificationChatID,
+		&updated.GithubRepository,
+		&updated.SentryProject,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("upsert app integration: %w", err)
+	}
+	return &updated, nil
+}
+
+func (r *AppIntegrationRepo) get(row *sql.Row) (*domain.AppIntegration, error) {
+	var ai domain.AppIntegration
+	err := row.Scan(
+		&ai.AppID,
+		&ai.CodemagicAppID,
+		&ai.GitlabProjectID,
+		&ai.BugsnagProjectID,
+		&ai.DeployNotificationChatID,
+		&ai.IssuesNotificationChatID,
+		&ai.ErrorsNotificationChatID,
+		&ai.MergeRequestsNotificationChatID,
+		&ai.PipelinesNotificationChatID,
+		&ai.UploadArtifacts,
+		&ai.ReviewsNotificationChatID,
+		&ai.GithubRepository,
+		&ai.SentryProject,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, appErr.ErrAppIntegrationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get app integration: %w", err)
+	}
+	return &ai, nil
+}
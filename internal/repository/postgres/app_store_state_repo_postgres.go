@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"victa/internal/domain"
+)
+
+// AppStoreStateRepo реализует AppStoreStateRepository через prepared‑statements.
+type AppStoreStateRepo struct {
+	db             *sql.DB
+	stGetAll       *sql.Stmt
+	stGetByAppID   *sql.Stmt
+	stUpsert       *sql.Stmt
+	stDelete       *sql.Stmt
+	stSaveProgress *sql.Stmt
+}
+
+const appStoreStateColumns = `
+		       s.app_id, s.store_id, st.slug, st.name, s.store_app_id,
+		       s.last_version, s.last_review_id, s.checked_at, s.updated_at,
+		       a.company_id, a.name
+		  FROM app_store_states s
+		  JOIN stores st ON st.id = s.store_id
+		  JOIN apps a ON a.id = s.app_id`
+
+// NewAppStoreStateRepo подготавливает выражения; при ошибке сразу вернёт её.
+func NewAppStoreStateRepo(db *sql.DB) (*AppStoreStateRepo, error) {
+	r := &AppStoreStateRepo{db: db}
+	var err error
+
+	if r.stGetAll, err = db.Prepare(`
+		SELECT` + appStoreStateColumns + `
+		 WHERE s.store_app_id <> ''
+		 ORDER BY s.app_id, s.store_id`); err != nil {
+		return nil, fmt.Errorf("prepare getAll: %w", err)
+	}
+
+	if r.stGetByAppID, err = db.Prepare(`
+		SELECT` + appStoreStateColumns + `
+		 WHERE s.app_id = $1
+		 ORDER BY s.store_id`); err != nil {
+		return nil, fmt.Errorf("prepare getByAppID: %w", err)
+	}
+
+	// при смене ID в магазине прошлые версия и отзыв больше не относятся к приложению
+	if r.stUpsert, err = db.Prepare(`
+		INSERT INTO app_store_states (app_id, store_id, store_app_id, updated_at)
+		SELECT $1, id, $3, $4
+		  FROM stores
+		 WHERE slug = $2
+		ON CONFLICT (app_id, store_id) DO UPDATE
+		   SET last_version   = CASE WHEN app_store_states.store_app_id = EXCLUDED.store_app_id
+		                             THEN app_store_states.last_version END,
+		       last_review_id = CASE WHEN app_store_states.store_app_id = EXCLUDED.store_app_id
+		                             THEN app_store_states.last_review_id END,
+		       checked_at     = CASE WHEN app_store_states.store_app_id = EXCLUDED.store_app_id
+		                             THEN app_store_states.checked_at END,
+		       store_app_id   = EXCLUDED.store_app_id,
+		       updated_at     = EXCLUDED.updated_at`); err != nil {
+		return nil, fmt.Errorf("prepare upsert: %w", err)
+	}
+
+	if r.stDelete, err = db.Prepare(`
+		DELETE FROM app_store_states
+		 WHERE app_id = $1
+		   AND store_id = (SELECT id FROM stores WHERE slug = $2)`); err != nil {
+		return nil, fmt.Errorf("prepare delete: %w", err)
+	}
+
+	if r.stSaveProgress, err = db.Prepare(`
+		UPDATE app_store_states
+		   SET last_version   = $1,
+		       last_review_id = $2,
+		       checked_at     = $3,
+		       updated_at     = $3
+		 WHERE app_id = $4
+		   AND store_id = $5`); err != nil {
+		return nil, fmt.Errorf("prepare saveProgress: %w", err)
+	}
+
+	return r, nil
+}
+
+// Close освобождает prepared‑statements.
+func (r *AppStoreStateRepo) Close() error {
+	for _, st := range []*sql.Stmt{r.stGetAll, r.stGetByAppID, r.stUpsert, r.stDelete, r.stSaveProgress} {
+		if st != nil {
+			if err := st.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetAll возвращает все приложения, у которых задан ID в магазине.
+func (r *AppStoreStateRepo) GetAll(ctx context.Context) ([]domain.AppStoreState, error) {
+	return r.list(r.stGetAll.QueryContext(ctx))
+}
+
+// GetByAppID возвращает магазины приложения.
+func (r *AppStoreStateRepo) GetByAppID(ctx context.Context, appID int64) ([]domain.AppStoreState, error) {
+	return r.list(r.stGetByAppID.QueryContext(ctx, appID))
+}
+
+// Upsert задаёт ID приложения в магазине storeSlug.
+func (r *AppStoreStateRepo) Upsert(ctx context.Context, appID int64, storeSlug, storeAppID string) error {
+	if _, err := r.stUpsert.ExecContext(ctx, appID, storeSlug, storeAppID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("upsert app store state: %w", err)
+	}
+	return nil
+}
+
+// Delete отвязывает приложение от магазина storeSlug.
+func (r *AppStoreStateRepo) Delete(ctx context.Context, appID int64, storeSlug string) error {
+	if _, err := r.stDelete.ExecContext(ctx, appID, storeSlug); err != nil {
+		return fmt.Errorf("delete app store state: %w", err)
+	}
+	return nil
+}
+
+// SaveProgress запоминает последнюю версию и последний отзыв и отмечает опрос.
+func (r *AppStoreStateRepo) SaveProgress(ctx context.Context, st *domain.AppStoreState) error {
+	if _, err := r.stSaveProgress.ExecContext(ctx,
+		st.LastVersion, st.LastReviewID, time.Now().UTC(), st.AppID, st.StoreID); err != nil {
+		return fmt.Errorf("save app store state: %w", err)
+	}
+	return nil
+}
+
+func (r *AppStoreStateRepo) list(rows *sql.Rows, err error) ([]domain.AppStoreState, error) {
+	if err != nil {
+		return nil, fmt.Errorf("query app store states: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	list := make([]domain.AppStoreState, 0, 8)
+	for rows.Next() {
+		var s domain.AppStoreState
+		if err := rows.Scan(&s.AppID, &s.StoreID, &s.StoreSlug, &s.StoreName, &s.StoreAppID,
+			&s.LastVersion, &s.LastReviewID, &s.CheckedAt, &s.UpdatedAt, &s.CompanyID, &s.AppName); err != nil {
+			return nil, fmt.Errorf("scan app store state: %w", err)
+		}
+		list = append(list, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return list, nil
+}
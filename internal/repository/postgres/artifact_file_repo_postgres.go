@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	appErr "victa/internal/errors"
+
+	"victa/internal/domain"
+)
+
+// ArtifactFileRepo реализует ArtifactFileRepository через prepared‑statements.
+type ArtifactFileRepo struct {
+	db          *sql.DB
+	stGetByPath *sql.Stmt
+	stSave      *sql.Stmt
+	stDelete    *sql.Stmt
+}
+
+// NewArtifactFileRepo подготавливает выражения; при ошибке сразу вернёт её.
+func NewArtifactFileRepo(db *sql.DB) (*ArtifactFileRepo, error) {
+	r := &ArtifactFileRepo{db: db}
+	var err error
+
+	if r.stGetByPath, err = db.Prepare(`
+		SELECT company_id, artifact_path, file_id, file_name, size, created_at
+		  FROM artifact_files
+		 WHERE company_id = $1
+		   AND artifact_path = $2`); err != nil {
+		return nil, fmt.Errorf("prepare getByPath: %w", err)
+	}
+
+	if r.stSave, err = db.Prepare(`
+		INSERT INTO artifact_files (company_id, artifact_path, file_id, file_name, size, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (company_id, artifact_path) DO UPDATE
+		   SET file_id    = EXCLUDED.file_id,
+		       file_name  = EXCLUDED.file_name,
+		       size       = EXCLUDED.size,
+		       created_at = EXCLUDED.created_at
+		RETURNING company_id, artifact_path, file_id, file_name, size, created_at`); err != nil {
+		return nil, fmt.Errorf("prepare save: %w", err)
+	}
+
+	if r.stDelete, err = db.Prepare(`
+		DELETE FROM artifact_files
+		 WHERE company_id = $1
+		   AND artifact_path = $2`); err != nil {
+		return nil, fmt.Errorf("prepare delete: %w", err)
+	}
+
+	return r, nil
+}
+
+// Close освобождает prepared‑statements.
+func (r *ArtifactFileRepo) Close() error {
+	for _, st := range []*sql.Stmt{r.stGetByPath, r.stSave, r.stDelete} {
+		if st != nil {
+			if err := st.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetByPath возвращает загруженный артефакт или ErrArtifactFileNotFound.
+func (r *ArtifactFileRepo) GetByPath(ctx context.Context, companyID int64, artifactPath string) (*domain.ArtifactFile, error) {
+	var f domain.ArtifactFile
+	err := r.stGetByPath.QueryRowContext(ctx, companyID, artifactPath).Scan(
+		&f.CompanyID, &f.ArtifactPath, &f.FileID, &f.FileName, &f.Size, &f.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, appErr.ErrArtifactFileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get artifact file: %w", err)
+	}
+	return &f, nil
+}
+
+// Save запоминает file_id артефакта.
+func (r *ArtifactFileRepo) Save(ctx context.Context, f *domain.ArtifactFile) (*domain.ArtifactFile, error) {
+	var saved domain.ArtifactFile
+	err := r.stSave.QueryRowContext(ctx,
+		f.CompanyID, f.ArtifactPath, f.FileID, f.FileName, f.Size, time.Now().UTC(),
+	).Scan(&saved.CompanyID, &saved.ArtifactPath, &saved.FileID, &saved.FileName, &saved.Size, &saved.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("save artifact file: %w", err)
+	}
+	return &saved, nil
+}
+
+// Delete забывает file_id артефакта.
+func (r *ArtifactFileRepo) Delete(ctx context.Context, companyID int64, artifactPath string) error {
+	if _, err := r.stDelete.ExecContext(ctx, companyID, artifactPath); err != nil {
+		return fmt.Errorf("delete artifact file: %w", err)
+	}
+	return nil
+}
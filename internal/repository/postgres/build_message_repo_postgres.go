@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	appErr "victa/internal/errors"
+
+	"victa/internal/domain"
+)
+
+// BuildMessageRepo реализует BuildMessageRepository через prepared‑statements.
+type BuildMessageRepo struct {
+	db              *sql.DB
+	stGetByBuildID  *sql.Stmt
+	stGetUnfinished *sql.Stmt
+	stSave          *sql.Stmt
+}
+
+// NewBuildMessageRepo подготавливает выражения; при ошибке сразу вернёт её.
+func NewBuildMessageRepo(db *sql.DB) (*BuildMessageRepo, error) {
+	r := &BuildMessageRepo{db: db}
+	var err error
+
+	if r.stGetByBuildID, err = db.Prepare(`
+		SELECT build_id, company_id, app_id, chat_id, message_id, status, finished, created_at, updated_at
+		  FROM build_messages
+		 WHERE build_id = $1
+		   AND chat_id = $2`); err != nil {
+		return nil, fmt.Errorf("prepare getByBuildID: %w", err)
+	}
+
+	if r.stGetUnfinished, err = db.Prepare(`
+		SELECT build_id, company_id, app_id, chat_id, message_id, status, finished, created_at, updated_at
+		  FROM build_messages
+		 WHERE NOT finished
+		   AND updated_at > $1
+		 ORDER BY updated_at`); err != nil {
+		return nil, fmt.Errorf("prepare getUnfinished: %w", err)
+	}
+
+	if r.stSave, err = db.Prepare(`
+		INSERT INTO build_messages (build_id, company_id, app_id, chat_id, message_id, status, finished, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT (build_id, chat_id) DO UPDATE
+		   SET message_id = EXCLUDED.message_id,
+		       status     = EXCLUDED.status,
+		       finished   = EXCLUDED.finished,
+		       updated_at = EXCLUDED.updated_at
+		RETURNING build_id, company_id, app_id, chat_id, message_id, status, finished, created_at, updated_at`); err != nil {
+		return nil, fmt.Errorf("prepare save: %w", err)
+	}
+
+	return r, nil
+}
+
+// Close освобождает prepared‑statements.
+func (r *BuildMessageRepo) Close() error {
+	for _, st := range []*sql.Stmt{r.stGetByBuildID, r.stGetUnfinished, r.stSave} {
+		if st != nil {
+			if err := st.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetByBuildID возвращает сообщение билда в чате или ErrBuildMessageNotFound.
+func (r *BuildMessageRepo) GetByBuildID(ctx context.Context, buildID, chatID string) (*domain.BuildMessage, error) {
+	m, err := r.scan(r.stGetByBuildID.QueryRowContext(ctx, buildID, chatID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, appErr.ErrBuildMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get build message: %w", err)
+	}
+	return m, nil
+}
+
+// GetUnfinished возвращает незавершённые билды, обновлявшиеся после updatedAfter.
+func (r *BuildMessageRepo) GetUnfinished(ctx context.Context, updatedAfter time.Time) ([]domain.BuildMessage, error) {
+	rows, err := r.stGetUnfinished.QueryContext(ctx, updatedAfter)
+	if err != nil {
+		return nil, fmt.Errorf("query build messages: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	list := make([]domain.BuildMessage, 0, 8)
+	for rows.Next() {
+		m, err := r.scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan build message: %w", err)
+		}
+		list = append(list, *m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return list, nil
+}
+
+// Save создаёт или обновляет привязку билда к сообщению.
+func (r *BuildMessageRepo) Save(ctx context.Context, msg *domain.BuildMessage) (*domain.BuildMessage, error) {
+	now := time.Now().UTC()
+	m, err := r.scan(r.stSave.QueryRowContext(ctx,
+		msg.BuildID, msg.CompanyID, msg.AppID, msg.ChatID, msg.MessageID, msg.Status, msg.Finished, now))
+	if err != nil {
+		return nil, fmt.Errorf("save build message: %w", err)
+	}
+	return m, nil
+}
+
+func (r *BuildMessageRepo) scan(row interface{ Scan(...any) error }) (*domain.BuildMessage, error) {
+	var m domain.BuildMessage
+	err := row.Scan(&m.BuildID, &m.CompanyID, &m.AppID, &m.ChatID, &m.MessageID,
+		&m.Status, &m.Finished, &m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	appErr "victa/internal/errors"
+
+	"victa/internal/domain"
+)
+
+// ChatStateRepo реализует ChatStateRepository через prepared‑statements.
+type ChatStateRepo struct {
+	db              *sql.DB
+	stGetByChatID   *sql.Stmt
+	stSave          *sql.Stmt
+	stDelete        *sql.Stmt
+	stDeleteExpired *sql.Stmt
+}
+
+const chatStateColumns = `chat_id, state, message_ids, company_id, app_id, app_name, app_slug, provider, integration_field, expires_at, updated_at`
+
+// NewChatStateRepo подготавливает выражения; при ошибке сразу вернёт её.
+func NewChatStateRepo(db *sql.DB) (*ChatStateRepo, error) {
+	r := &ChatStateRepo{db: db}
+	var err error
+
+	if r.stGetByChatID, err = db.Prepare(`
+		SELECT ` + chatStateColumns + `
+		  FROM chat_states
+		 WHERE chat_id = $1`); err != nil {
+		return nil, fmt.Errorf("prepare getByChatID: %w", err)
+	}
+
+	if r.stSave, err = db.Prepare(`
+		INSERT INTO chat_states (chat_id, state, message_ids, company_id, app_id, app_name, app_slug, provider, integration_field, expires_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (chat_id) DO UPDATE
+		   SET state             = EXCLUDED.state,
+		       message_ids       = EXCLUDED.message_ids,
+		       company_id        = EXCLUDED.company_id,
+		       app_id            = EXCLUDED.app_id,
+		       app_name          = EXCLUDED.app_name,
+		       app_slug          = EXCLUDED.app_slug,
+		       provider          = EXCLUDED.provider,
+		       integration_field = EXCLUDED.integration_field,
+		       expires_at        = EXCLUDED.expires_at,
+		       updated_at        = EXCLUDED.updated_at`); err != nil {
+		return nil, fmt.Errorf("prepare save: %w", err)
+	}
+
+	if r.stDelete, err = db.Prepare(`
+		DELETE FROM chat_states
+		 WHERE chat_id = $1
+		RETURNING ` + chatStateColumns); err != nil {
+		return nil, fmt.Errorf("prepare delete: %w", err)
+	}
+
+	if r.stDeleteExpired, err = db.Prepare(`
+		DELETE FROM chat_states
+		 WHERE expires_at <= $1
+		RETURNING ` + chatStateColumns); err != nil {
+		return nil, fmt.Errorf("prepare deleteExpired: %w", err)
+	}
+
+	return r, nil
+}
+
+// Close освобождает prepared‑statements.
+func (r *ChatStateRepo) Close() error {
+	for _, st := range []*sql.Stmt{r.stGetByChatID, r.stSave, r.stDelete, r.stDeleteExpired} {
+		if st != nil {
+			if err := st.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetByChatID возвращает состояние чата или ErrChatStateNotFound.
+func (r *ChatStateRepo) GetByChatID(ctx context.Context, chatID int64) (*domain.ChatState, error) {
+	st, err := r.scan(r.stGetByChatID.QueryRowContext(ctx, chatID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, appErr.ErrChatStateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get chat state: %w", err)
+	}
+	return st, nil
+}
+
+// Save создаёт или целиком перезаписывает состояние чата.
+func (r *ChatStateRepo) Save(ctx context.Context, st *domain.ChatState) error {
+	ids := st.MessageIDs
+	if ids == nil {
+		ids = []int{}
+	}
+	messageIDs, err := json.Marshal(ids)
+	if err != nil {
+		return fmt.Errorf("marshal message ids: %w", err)
+	}
+
+	if _, err := r.stSave.ExecContext(ctx,
+		st.ChatID, st.State, messageIDs, st.CompanyID, st.AppID, st.AppName, st.AppSlug, st.Provider, st.IntegrationField,
+		st.ExpiresAt, time.Now().UTC()); err != nil {
+		return fmt.Errorf("save chat state: %w", err)
+	}
+	return nil
+}
+
+// Delete удаляет состояние чата, в том числе истёкшее, и возвращает его,
+// чтобы вызывающий мог убрать подсказки. Если состояния нет — ErrChatStateNotFound.
+func (r *ChatStateRepo) Delete(ctx context.Context, chatID int64) (*domain.ChatState, error) {
+	st, err := r.scan(r.stDelete.QueryRowContext(ctx, chatID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, appErr.ErrChatStateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("delete chat state: %w", err)
+	}
+	return st, nil
+}
+
+// DeleteExpired удаляет состояния, истёкшие к now, и возвращает их.
+// Каждая строка достаётся ровно одной реплике.
+func (r *ChatStateRepo) DeleteExpired(ctx context.Context, now time.Time) ([]domain.ChatState, error) {
+	rows, err := r.stDeleteExpired.QueryContext(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("delete expired chat states: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	list := make([]domain.ChatState, 0, 8)
+	for rows.Next() {
+		st, err := r.scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan chat state: %w", err)
+		}
+		list = append(list, *st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return list, nil
+}
+
+func (r *ChatStateRepo) scan(row interface{ Scan(...any) error }) (*domain.ChatState, error) {
+	var (
+		st         domain.ChatState
+		state      sql.NullInt64
+		messageIDs []byte
+	)
+	err := row.Scan(&st.ChatID, &state, &messageIDs, &st.CompanyID, &st.AppID,
+		&st.AppName, &st.AppSlug, &st.Provider, &st.IntegrationField, &st.ExpiresAt, &st.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.Valid {
+		v := int(state.Int64)
+		st.State = &v
+	}
+	if err := json.Unmarshal(messageIDs, &st.MessageIDs); err != nil {
+		return nil, fmt.Errorf("unmarshal message ids: %w", err)
+	}
+	return &st, nil
+}
@@ -7,20 +7,26 @@ import (
 	"fmt"
 
 	"victa/internal/domain"
+	"victa/internal/secret"
 
 	appErr "victa/internal/errors"
 )
 
-// CompanyIntegrationRepo хранит prepared‑statements.
+// CompanyIntegrationRepo хранит prepared‑statements. Токены и секреты
+// интеграций (см. secretFields) лежат в БД зашифрованными keys.
 type CompanyIntegrationRepo struct {
-	db        *sql.DB
-	stGetByID *sql.Stmt
-	stUpsert  *sql.Stmt
+	db           *sql.DB
+	keys         *secret.Keyring
+	stGetByID    *sql.Stmt
+	stUpsert     *sql.Stmt
+	stTokens     *sql.Stmt
+	stGetSecrets *sql.Stmt
+	stReencrypt  *sql.Stmt
 }
 
 // NewCompanyIntegrationRepo инициализирует репозиторий.
-func NewCompanyIntegrationRepo(db *sql.DB) (*CompanyIntegrationRepo, error) {
-	r := &CompanyIntegrationRepo{db: db}
+func NewCompanyIntegrationRepo(db *sql.DB, keys *secret.Keyring) (*CompanyIntegrationRepo, error) {
+	r := &CompanyIntegrationRepo{db: db, keys: keys}
 
 	var err error
 	if r.stGetByID, err = db.Prepare(`
@@ -29,7 +35,20 @@ func NewCompanyIntegrationRepo(db *sql.DB) (*CompanyIntegrationRepo, error) {
 		       notification_bot_token,
 		       deploy_notification_chat_id,
 		       issues_notification_chat_id,
-		       errors_notification_chat_id
+		       errors_notification_chat_id,
+		       merge_requests_notification_chat_id,
+		       pipelines_notification_chat_id,
+		       gitlab_api_token,
+		       github_webhook_secret,
+		       sentry_client_secret,
+		       gitlab_webhook_token,
+		       bugsnag_webhook_secret,
+		       bugsnag_api_token,
+		       gitlab_base_url,
+		       upload_artifacts,
+		       artifact_types,
+		       artifact_qr_codes,
+		       reviews_notification_chat_id
 		  FROM company_integrations
 		 WHERE company_id = $1`); err != nil {
 		return nil, fmt.Errorf("prepare getByID: %w", err)
@@ -42,23 +61,109 @@ func NewCompanyIntegrationRepo(db *sql.DB) (*CompanyIntegrationRepo, error) {
 		      notification_bot_token,
 		      deploy_notification_chat_id,
 		      issues_notification_chat_id,
-		      errors_notification_chat_id)
-		VALUES ($1,$2,$3,$4,$5,$6)
+		      errors_notification_chat_id,
+		      merge_requests_notification_chat_id,
+		      pipelines_notification_chat_id,
+		      gitlab_api_token,
+		      github_webhook_secret,
+		      sentry_client_secret,
+		      gitlab_webhook_token,
+		      bugsnag_webhook_secret,
+		      bugsnag_api_token,
+		      gitlab_base_url,
+		      upload_artifacts,
+		      artifact_types,
+		      artifact_qr_codes,
+		      reviews_notification_chat_id)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19)
 		ON CONFLICT (company_id) DO UPDATE
 		    SET codemagic_api_key           = EXCLUDED.codemagic_api_key,
 		        notification_bot_token      = EXCLUDED.notification_bot_token,
 		        deploy_notification_chat_id = EXCLUDED.deploy_notification_chat_id,
 		        issues_notification_chat_id = EXCLUDED.issues_notification_chat_id,
-		        errors_notification_chat_id = EXCLUDED.errors_notification_chat_id
+		        errors_notification_chat_id = EXCLUDED.errors_notification_chat_id,
+		        merge_requests_notification_chat_id = EXCLUDED.merge_requests_notification_chat_id,
+		        pipelines_notification_chat_id = EXCLUDED.pipelines_notification_chat_id,
+		        gitlab_api_token = EXCLUDED.gitlab_api_token,
+		        github_webhook_secret = EXCLUDED.github_webhook_secret,
+		        sentry_client_secret = EXCLUDED.sentry_client_secret,
+		        gitlab_webhook_token = EXCLUDED.gitlab_webhook_token,
+		        bugsnag_webhook_secret = EXCLUDED.bugsnag_webhook_secret,
+		        bugsnag_api_token = EXCLUDED.bugsnag_api_token,
+		        gitlab_base_url = EXCLUDED.gitlab_base_url,
+		        upload_artifacts = EXCLUDED.upload_artifacts,
+		        artifact_types = EXCLUDED.artifact_types,
+		        artifact_qr_codes = EXCLUDED.artifact_qr_codes,
+		        reviews_notification_chat_id = EXCLUDED.reviews_notification_chat_id
 		RETURNING company_id,
 		          codemagic_api_key,
 		          notification_bot_token,
 		          deploy_notification_chat_id,
 		          issues_notification_chat_id,
-		          errors_notification_chat_id`); err != nil {
+		          errors_notification_chat_id,
+		          merge_requests_notification_chat_id,
+		          pipelines_notification_chat_id,
+		          gitlab_api_token,
+		          github_webhook_secret,
+		          sentry_client_secret,
+		          gitlab_webhook_token,
+		          bugsnag_webhook_secret,
+		          bugsnag_api_token,
+		          gitlab_base_url,
+		          upload_artifacts,
+		          artifact_types,
+		          artifact_qr_codes,
+		          reviews_notification_chat_id`); err != nil {
 		return nil, fmt.Errorf("prepare upsert: %w", err)
 	}
 
+	// шифротексты одного токена различаются, повторы убираются после расшифровки
+	if r.stTokens, err = db.Prepare(`
+		SELECT company_id, notification_bot_token
+		  FROM company_integrations
+		 WHERE notification_bot_token IS NOT NULL
+		   AND notification_bot_token <> ''`); err != nil {
+		return nil, fmt.Errorf("prepare getNotificationBotTokens: %w", err)
+	}
+
+	if r.stGetSecrets, err = db.Prepare(`
+		SELECT company_id,
+		       codemagic_api_key,
+		       notification_bot_token,
+		       gitlab_api_token,
+		       github_webhook_secret,
+		       sentry_client_secret,
+		       gitlab_webhook_token,
+		       bugsnag_webhook_secret,
+		       bugsnag_api_token
+		  FROM company_integrations
+		 ORDER BY company_id`); err != nil {
+		return nil, fmt.Errorf("prepare getSecrets: %w", err)
+	}
+
+	// обновляем, только если секреты не поменяли, пока мы их перешифровывали
+	if r.stReencrypt, err = db.Prepare(`
+		UPDATE company_integrations
+		   SET codemagic_api_key      = $2,
+		       notification_bot_token = $3,
+		       gitlab_api_token       = $4,
+		       github_webhook_secret  = $5,
+		       sentry_client_secret   = $6,
+		       gitlab_webhook_token   = $7,
+		       bugsnag_webhook_secret = $8,
+		       bugsnag_api_token      = $9
+		 WHERE company_id = $1
+		   AND codemagic_api_key      IS NOT DISTINCT FROM $10
+		   AND notification_bot_token IS NOT DISTINCT FROM $11
+		   AND gitlab_api_token       IS NOT DISTINCT FROM $12
+		   AND github_webhook_secret  IS NOT DISTINCT FROM $13
+		   AND sentry_client_secret   IS NOT DISTINCT FROM $14
+		   AND gitlab_webhook_token   IS NOT DISTINCT FROM $15
+		   AND bugsnag_webhook_secret IS NOT DISTINCT FROM $16
+		   AND bugsnag_api_token      IS NOT DISTINCT FROM $17`); err != nil {
+		return nil, fmt.Errorf("prepare reencrypt: %w", err)
+	}
+
 	return r, nil
 }
 
@@ -70,6 +175,15 @@ func (r *CompanyIntegrationRepo) Close() error {
 	if err := r.stGetByID.Close(); err != nil {
 		return err
 	}
+	if err := r.stTokens.Close(); err != nil {
+		return err
+	}
+	if err := r.stGetSecrets.Close(); err != nil {
+		return err
+	}
+	if err := r.stReencrypt.Close(); err != nil {
+		return err
+	}
 	return r.stUpsert.Close()
 }
 
@@ -83,6 +197,19 @@ func (r *CompanyIntegrationRepo) GetByID(ctx context.Context, companyID int64) (
 		&ci.DeployNotificationChatID,
 		&ci.IssuesNotificationChatID,
 		&ci.ErrorsNotificationChatID,
+		&ci.MergeRequestsNotificationChatID,
+		&ci.PipelinesNotificationChatID,
+		&ci.GitlabAPIToken,
+		&ci.GithubWebhookSecret,
+		&ci.SentryClientSecret,
+		&ci.GitlabWebhookToken,
+		&ci.BugsnagWebhookSecret,
+		&ci.BugsnagAPIToken,
+		&ci.GitlabBaseURL,
+		&ci.UploadArtifacts,
+		&ci.ArtifactTypes,
+		&ci.ArtifactQRCodes,
+		&ci.ReviewsNotificationChatID,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -91,11 +218,19 @@ func (r *CompanyIntegrationRepo) GetByID(ctx context.Context, companyID int64) (
 	if err != nil {
 		return nil, fmt.Errorf("get integration: %w", err)
 	}
+	if err := r.decrypt(&ci); err != nil {
+		return nil, fmt.Errorf("get integration: %w", err)
+	}
 	return &ci, nil
 }
 
 // CreateOrUpdate делает upsert и возвращает актуальные данные.
-func (r *CompanyIntegrationRepo) CreateOrUpdate(ctx context.Context, ci *domain.CompanyIntegration) (*domain.CompanyIntegration, error) {
+func (r *CompanyIntegrationRepo) CreateOrUpdate(ctx context.Context, plain *domain.CompanyIntegration) (*domain.CompanyIntegration, error) {
+	ci, err := r.encrypt(plain)
+	if err != nil {
+		return nil, fmt.Errorf("upsert integration: %w", err)
+	}
+
 	row := r.stUpsert.QueryRowContext(ctx,
 		ci.CompanyID,
 		ci.CodemagicAPIKey,
@@ -103,6 +238,19 @@ func (r *CompanyIntegrationRepo) CreateOrUpdate(ctx context.Context, ci *domain.
 		ci.DeployNotificationChatID,
 		ci.IssuesNotificationChatID,
 		ci.ErrorsNotificationChatID,
+		ci.MergeRequestsNotificationChatID,
+		ci.PipelinesNotificationChatID,
+		ci.GitlabAPIToken,
+		ci.GithubWebhookSecret,
+		ci.SentryClientSecret,
+		ci.GitlabWebhookToken,
+		ci.BugsnagWebhookSecret,
+		ci.BugsnagAPIToken,
+		ci.GitlabBaseURL,
+		ci.UploadArtifacts,
+		ci.ArtifactTypes,
+		ci.ArtifactQRCodes,
+		ci.ReviewsNotificationChatID,
 	)
 
 	var updated domain.CompanyIntegration
@@ -113,8 +261,188 @@ func (r *CompanyIntegrationRepo) CreateOrUpdate(ctx context.Context, ci *domain.
 		&updated.DeployNotificationChatID,
 		&updated.IssuesNotificationChatID,
 		&updated.ErrorsNotificationChatID,
+		&updated.MergeRequestsNotificationChatID,
+		&updated.PipelinesNotificationChatID,
+		&updated.GitlabAPIToken,
+		&updated.GithubWebhookSecret,
+		&updated.SentryClientSecret,
+		&updated.GitlabWebhookToken,
+		&updated.BugsnagWebhookSecret,
+		&updated.BugsnagAPIToken,
+		&updated.GitlabBaseURL,
+		&updated.UploadArtifacts,
+		&updated.ArtifactTypes,
+		&updated.ArtifactQRCodes,
+		&updated.ReviewsNotificationChatID,
 	); err != nil {
 		return nil, fmt.Errorf("upsert integration: %w", err)
 	}
+	if err := r.decrypt(&updated); err != nil {
+		return nil, fmt.Errorf("upsert integration: %w", err)
+	}
 	return &updated, nil
 }
+
+// GetNotificationBotTokens возвращает токены всех ботов уведомлений без повторов.
+func (r *CompanyIntegrationRepo) GetNotificationBotTokens(ctx context.Context) ([]string, error) {
+	rows, err := r.stTokens.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get notification bot tokens: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var tokens []string
+	seen := make(map[string]struct{})
+	for rows.Next() {
+		var (
+			companyID int64
+			token     string
+		)
+		if err := rows.Scan(&companyID, &token); err != nil {
+			return nil, fmt.Errorf("scan token: %w", err)
+		}
+		if token, err = r.keys.Decrypt(token, secretAAD(companyID, "notification_bot_token")); err != nil {
+			return nil, fmt.Errorf("decrypt token: %w", err)
+		}
+		if _, ok := seen[token]; ok {
+			continue
+		}
+		seen[token] = struct{}{}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return tokens, nil
+}
+
+// Reencrypt перешифровывает активным ключом секреты, записанные открытым
+// текстом, старым ключом или в формате v1 без привязки к компании и колонке,
+// и возвращает число обновлённых компаний.
+// После него старый ключ можно убрать из кольца.
+func (r *CompanyIntegrationRepo) Reencrypt(ctx context.Context) (int, error) {
+	rows, err := r.stGetSecrets.QueryContext(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get secrets: %w", err)
+	}
+
+	var stale []domain.CompanyIntegration
+	for rows.Next() {
+		var ci domain.CompanyIntegration
+		if err := rows.Scan(
+			&ci.CompanyID,
+			&ci.CodemagicAPIKey,
+			&ci.NotificationBotToken,
+			&ci.GitlabAPIToken,
+			&ci.GithubWebhookSecret,
+			&ci.SentryClientSecret,
+			&ci.GitlabWebhookToken,
+			&ci.BugsnagWebhookSecret,
+			&ci.BugsnagAPIToken,
+		); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("scan secrets: %w", err)
+		}
+		for _, f := range secretFields(&ci) {
+			if *f.value != nil && r.keys.NeedsRotation(**f.value) {
+				stale = append(stale, ci)
+				break
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return 0, fmt.Errorf("rows error: %w", err)
+	}
+	_ = rows.Close()
+
+	updated := 0
+	for _, old := range stale {
+		plain := old
+		if err := r.decrypt(&plain); err != nil {
+			return updated, fmt.Errorf("company %d: %w", old.CompanyID, err)
+		}
+		ci, err := r.encrypt(&plain)
+		if err != nil {
+			return updated, fmt.Errorf("company %d: %w", old.CompanyID, err)
+		}
+
+		args := []any{old.CompanyID}
+		for _, f := range secretFields(ci) {
+			args = append(args, *f.value)
+		}
+		for _, f := range secretFields(&old) {
+			args = append(args, *f.value)
+		}
+
+		res, err := r.stReencrypt.ExecContext(ctx, args...)
+		if err != nil {
+			return updated, fmt.Errorf("reencrypt company %d: %w", old.CompanyID, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// secretField — зашифрованное поле интеграции и его колонка в БД.
+type secretField struct {
+	column string
+	value  **string
+}
+
+// secretFields перечисляет поля интеграции, которые хранятся зашифрованными,
+// в порядке колонок stGetSecrets.
+func secretFields(ci *domain.CompanyIntegration) []secretField {
+	return []secretField{
+		{"codemagic_api_key", &ci.CodemagicAPIKey},
+		{"notification_bot_token", &ci.NotificationBotToken},
+		{"gitlab_api_token", &ci.GitlabAPIToken},
+		{"github_webhook_secret", &ci.GithubWebhookSecret},
+		{"sentry_client_secret", &ci.SentryClientSecret},
+		{"gitlab_webhook_token", &ci.GitlabWebhookToken},
+		{"bugsnag_webhook_secret", &ci.BugsnagWebhookSecret},
+		{"bugsnag_api_token", &ci.BugsnagAPIToken},
+	}
+}
+
+// secretAAD привязывает шифротекст к компании и колонке: секрет,
+// переставленный в чужую строку или колонку, не расшифруется.
+func secretAAD(companyID int64, column string) string {
+	return fmt.Sprintf("%d:%s", companyID, column)
+}
+
+// encrypt возвращает копию ci с зашифрованными секретами.
+func (r *CompanyIntegrationRepo) encrypt(ci *domain.CompanyIntegration) (*domain.CompanyIntegration, error) {
+	enc := *ci
+	for _, f := range secretFields(&enc) {
+		if *f.value == nil {
+			continue
+		}
+		v, err := r.keys.Encrypt(**f.value, secretAAD(enc.CompanyID, f.column))
+		if err != nil {
+			return nil, err
+		}
+		*f.value = &v
+	}
+	return &enc, nil
+}
+
+// decrypt расшифровывает секреты ci на месте.
+func (r *CompanyIntegrationRepo) decrypt(ci *domain.CompanyIntegration) error {
+	for _, f := range secretFields(ci) {
+		if *f.value == nil {
+			continue
+		}
+		v, err := r.keys.Decrypt(**f.value, secretAAD(ci.CompanyID, f.column))
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.column, err)
+		}
+		*f.value = &v
+	}
+	return nil
+}
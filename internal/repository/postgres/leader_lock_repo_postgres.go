@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"victa/internal/repository"
+)
+
+// releaseTimeout — сколько ждём pg_advisory_unlock при остановке.
+const releaseTimeout = 5 * time.Second
+
+// LeaderLockRepo реализует LeaderLockRepository на advisory‑блокировках
+// Postgres. Блокировка сессионная, поэтому под каждую берётся отдельное
+// соединение из пула и держится до Release.
+type LeaderLockRepo struct {
+	db *sql.DB
+}
+
+// NewLeaderLockRepo создаёт репозиторий блокировок.
+func NewLeaderLockRepo(db *sql.DB) (*LeaderLockRepo, error) {
+	return &LeaderLockRepo{db: db}, nil
+}
+
+// TryAcquire берёт pg_try_advisory_lock(key); nil — блокировку держит другая сессия.
+func (r *LeaderLockRepo) TryAcquire(ctx context.Context, key int64) (repository.LeaderLock, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("leader lock conn: %w", err)
+	}
+
+	var ok bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&ok); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("try advisory lock: %w", err)
+	}
+	if !ok {
+		_ = conn.Close()
+		return nil, nil
+	}
+	return &advisoryLock{conn: conn, key: key}, nil
+}
+
+type advisoryLock struct {
+	conn *sql.Conn
+	key  int64
+}
+
+func (l *advisoryLock) Ping(ctx context.Context) error {
+	return l.conn.PingContext(ctx)
+}
+
+// Release снимает блокировку. Если снять не удалось, соединение
+// закрывается, а не возвращается в пул: иначе блокировка осталась бы
+// висеть на чужом запросе.
+func (l *advisoryLock) Release() error {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+
+	if _, err := l.conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, l.key); err != nil {
+		_ = l.conn.Raw(func(any) error { return driver.ErrBadConn })
+		_ = l.conn.Close()
+		return fmt.Errorf("advisory unlock: %w", err)
+	}
+	return l.conn.Close()
+}
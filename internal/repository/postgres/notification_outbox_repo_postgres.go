@@ -0,0 +1,319 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"victa/internal/domain"
+)
+
+// NotificationOutboxRepo реализует NotificationOutboxRepository через prepared‑statements.
+type NotificationOutboxRepo struct {
+	db                 *sql.DB
+	stEnqueue          *sql.Stmt
+	stClaim            *sql.Stmt
+	stExtend           *sql.Stmt
+	stDelete           *sql.Stmt
+	stReschedule       *sql.Stmt
+	stMoveToDeadLetter *sql.Stmt
+}
+
+// NewNotificationOutboxRepo подготавливает выражения; при ошибке сразу вернёт её.
+func NewNotificationOutboxRepo(db *sql.DB) (*NotificationOutboxRepo, error) {
+	r := &NotificationOutboxRepo{db: db}
+	var err error
+
+	if r.stEnqueue, err = db.Prepare(`
+		INSERT INTO notification_outbox (company_id, chat_id, text, build, buttons, documents, qr_codes, job_trace, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+		RETURNING id, company_id, chat_id, text, build, buttons, documents, qr_codes, job_trace, attempts, next_attempt_at, last_error, created_at`); err != nil {
+		return nil, fmt.Errorf("prepare enqueue: %w", err)
+	}
+
+	// Захватываем пачку на время lease: параллельные воркеры пропустят
+	// заблокированные строки, а упавший воркер отпустит их по таймауту.
+	// Уведомления об одном билде в одном чате редактируют общее сообщение,
+	// поэтому их разбираем строго по одному: строку не берём, пока по тому же
+	// билду и чату есть захваченная строка или более ранняя готовая к отправке.
+	if r.stClaim, err = db.Prepare(`
+		UPDATE notification_outbox
+		   SET locked_until = $1 + $3 * INTERVAL '1 millisecond',
+		       attempts     = attempts + 1
+		 WHERE id IN (
+		       SELECT o.id
+		         FROM notification_outbox o
+		        WHERE o.next_attempt_at <= $1
+		          AND (o.locked_until IS NULL OR o.locked_until <= $1)
+		          AND (o.build IS NULL OR NOT EXISTS (
+		               SELECT 1
+		                 FROM notification_outbox p
+		                WHERE p.id <> o.id
+		                  AND p.chat_id = o.chat_id
+		                  AND p.build->>'build_id' = o.build->>'build_id'
+		                  AND (p.locked_until > $1
+		                       OR (p.id < o.id AND p.next_attempt_at <= $1))))
+		        ORDER BY o.id
+		        LIMIT $2
+		          FOR UPDATE SKIP LOCKED)
+		RETURNING id, company_id, chat_id, text, build, buttons, documents, qr_codes, job_trace, attempts, next_attempt_at, last_error, created_at`); err != nil {
+		return nil, fmt.Errorf("prepare claim: %w", err)
+	}
+
+	// строки, которые уже отложены (locked_until = NULL), не трогаем
+	if r.stExtend, err = db.Prepare(`
+		UPDATE notification_outbox
+		   SET locked_until = $1 + $3 * INTERVAL '1 millisecond'
+		 WHERE id = ANY($2)
+		   AND locked_until IS NOT NULL`); err != nil {
+		return nil, fmt.Errorf("prepare extend: %w", err)
+	}
+
+	if r.stDelete, err = db.Prepare(`DELETE FROM notification_outbox WHERE id = $1`); err != nil {
+		return nil, fmt.Errorf("prepare delete: %w", err)
+	}
+
+	if r.stReschedule, err = db.Prepare(`
+		UPDATE notification_outbox
+		   SET next_attempt_at = $1,
+		       last_error      = $2,
+		       locked_until    = NULL
+		 WHERE id = $3`); err != nil {
+		return nil, fmt.Errorf("prepare reschedule: %w", err)
+	}
+
+	if r.stMoveToDeadLetter, err = db.Prepare(`
+		WITH moved AS (
+		    DELETE FROM notification_outbox
+		     WHERE id = $1
+		    RETURNING id, company_id, chat_id, text, build, buttons, documents, qr_codes, job_trace, attempts, created_at)
+		INSERT INTO notification_dead_letters (id, company_id, chat_id, text, build, buttons, documents, qr_codes, job_trace, attempts, last_error, created_at, failed_at)
+		SELECT id, company_id, chat_id, text, build, buttons, documents, qr_codes, job_trace, attempts, $2, created_at, $3
+		  FROM moved`); err != nil {
+		return nil, fmt.Errorf("prepare moveToDeadLetter: %w", err)
+	}
+
+	return r, nil
+}
+
+// Close освобождает prepared‑statements.
+func (r *NotificationOutboxRepo) Close() error {
+	for _, st := range []*sql.Stmt{
+		r.stEnqueue, r.stClaim, r.stExtend, r.stDelete, r.stReschedule, r.stMoveToDeadLetter,
+	} {
+		if st != nil {
+			if err := st.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Enqueue кладёт уведомление в очередь на немедленную отправку.
+func (r *NotificationOutboxRepo) Enqueue(ctx context.Context, n *domain.Notification) (*domain.Notification, error) {
+	args, err := r.enqueueArgs(n)
+	if err != nil {
+		return nil, err
+	}
+	out, err := r.scan(r.stEnqueue.QueryRowContext(ctx, args...))
+	if err != nil {
+		return nil, fmt.Errorf("enqueue notification: %w", err)
+	}
+	return out, nil
+}
+
+// EnqueueAll кладёт уведомления в очередь одной транзакцией: в очередь
+// попадают либо все, либо ни одно.
+func (r *NotificationOutboxRepo) EnqueueAll(ctx context.Context, ns []*domain.Notification) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	st := tx.StmtContext(ctx, r.stEnqueue)
+	for _, n := range ns {
+		args, err := r.enqueueArgs(n)
+		if err != nil {
+			return err
+		}
+		if _, err := r.scan(st.QueryRowContext(ctx, args...)); err != nil {
+			return fmt.Errorf("enqueue notification: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// enqueueArgs готовит параметры stEnqueue.
+func (r *NotificationOutboxRepo) enqueueArgs(n *domain.Notification) ([]any, error) {
+	var build []byte
+	if n.Build != nil {
+		var err error
+		if build, err = json.Marshal(n.Build); err != nil {
+			return nil, fmt.Errorf("marshal build: %w", err)
+		}
+	}
+	var buttons []byte
+	if len(n.Buttons) > 0 {
+		var err error
+		if buttons, err = json.Marshal(n.Buttons); err != nil {
+			return nil, fmt.Errorf("marshal buttons: %w", err)
+		}
+	}
+	var documents []byte
+	if len(n.Documents) > 0 {
+		var err error
+		if documents, err = json.Marshal(n.Documents); err != nil {
+			return nil, fmt.Errorf("marshal documents: %w", err)
+		}
+	}
+	var qrCodes []byte
+	if len(n.QRCodes) > 0 {
+		var err error
+		if qrCodes, err = json.Marshal(n.QRCodes); err != nil {
+			return nil, fmt.Errorf("marshal qr codes: %w", err)
+		}
+	}
+	var jobTrace []byte
+	if n.JobTrace != nil {
+		var err error
+		if jobTrace, err = json.Marshal(n.JobTrace); err != nil {
+			return nil, fmt.Errorf("marshal job trace: %w", err)
+		}
+	}
+	return []any{n.CompanyID, n.ChatID, n.Text, build, buttons, documents, qrCodes, jobTrace, time.Now().UTC()}, nil
+}
+
+// Claim захватывает до limit готовых к отправке уведомлений и
+// увеличивает им счётчик попыток.
+func (r *NotificationOutboxRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.Notification, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// Захваты с разных реплик идут по очереди: иначе две реплики могут
+	// одновременно не увидеть чужой незакоммиченный захват строки того же
+	// билда и взять две его строки сразу.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('notification_outbox_claim'))`); err != nil {
+		return nil, fmt.Errorf("lock outbox claim: %w", err)
+	}
+
+	rows, err := tx.StmtContext(ctx, r.stClaim).QueryContext(ctx, time.Now().UTC(), limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim notifications: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	list := make([]domain.Notification, 0, limit)
+	for rows.Next() {
+		n, err := r.scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan notification: %w", err)
+		}
+		list = append(list, *n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return list, nil
+}
+
+// Extend продлевает захват строк ids ещё на lease, пока воркер их обрабатывает.
+func (r *NotificationOutboxRepo) Extend(ctx context.Context, ids []int64, lease time.Duration) error {
+	if _, err := r.stExtend.ExecContext(ctx, time.Now().UTC(), pq.Array(ids), lease.Milliseconds()); err != nil {
+		return fmt.Errorf("extend outbox lease: %w", err)
+	}
+	return nil
+}
+
+// Delete удаляет доставленное уведомление.
+func (r *NotificationOutboxRepo) Delete(ctx context.Context, id int64) error {
+	if _, err := r.stDelete.ExecContext(ctx, id); err != nil {
+		return fmt.Errorf("delete notification: %w", err)
+	}
+	return nil
+}
+
+// Reschedule откладывает следующую попытку отправки.
+func (r *NotificationOutboxRepo) Reschedule(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	if _, err := r.stReschedule.ExecContext(ctx, nextAttemptAt.UTC(), lastError, id); err != nil {
+		return fmt.Errorf("reschedule notification: %w", err)
+	}
+	return nil
+}
+
+// MoveToDeadLetter переносит уведомление в notification_dead_letters.
+func (r *NotificationOutboxRepo) MoveToDeadLetter(ctx context.Context, id int64, lastError string) error {
+	if _, err := r.stMoveToDeadLetter.ExecContext(ctx, id, lastError, time.Now().UTC()); err != nil {
+		return fmt.Errorf("move notification to dead letters: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationOutboxRepo) scan(row interface{ Scan(...any) error }) (*domain.Notification, error) {
+	var (
+		n         domain.Notification
+		build     []byte
+		buttons   []byte
+		documents []byte
+		qrCodes   []byte
+		jobTrace  []byte
+	)
+	err := row.Scan(&n.ID, &n.CompanyID, &n.ChatID, &n.Text, &build, &buttons, &documents, &qrCodes, &jobTrace,
+		&n.Attempts, &n.NextAttemptAt, &n.LastError, &n.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if len(build) > 0 {
+		n.Build = new(domain.NotificationBuild)
+		if err := json.Unmarshal(build, n.Build); err != nil {
+			return nil, fmt.Errorf("unmarshal build: %w", err)
+		}
+	}
+	if len(buttons) > 0 {
+		if err := json.Unmarshal(buttons, &n.Buttons); err != nil {
+			return nil, fmt.Errorf("unmarshal buttons: %w", err)
+		}
+	}
+	if len(documents) > 0 {
+		if err := json.Unmarshal(documents, &n.Documents); err != nil {
+			return nil, fmt.Errorf("unmarshal documents: %w", err)
+		}
+	}
+	if len(qrCodes) > 0 {
+		if err := json.Unmarshal(qrCodes, &n.QRCodes); err != nil {
+			return nil, fmt.Errorf("unmarshal qr codes: %w", err)
+		}
+	}
+	if len(jobTrace) > 0 {
+		n.JobTrace = new(domain.NotificationJobTrace)
+		if err := json.Unmarshal(jobTrace, n.JobTrace); err != nil {
+			return nil, fmt.Errorf("unmarshal job trace: %w", err)
+		}
+	}
+	return &n, nil
+}
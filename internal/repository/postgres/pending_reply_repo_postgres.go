@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	appErr "victa/internal/errors"
+
+	"victa/internal/domain"
+)
+
+// PendingReplyRepo реализует PendingReplyRepository через prepared‑statements.
+type PendingReplyRepo struct {
+	db              *sql.DB
+	stSave          *sql.Stmt
+	stGet           *sql.Stmt
+	stTake          *sql.Stmt
+	stDeleteExpired *sql.Stmt
+}
+
+const pendingReplyColumns = `chat_id, message_id, action, base_url, tg_user_id, expires_at`
+
+// NewPendingReplyRepo подготавливает выражения; при ошибке сразу вернёт её.
+func NewPendingReplyRepo(db *sql.DB) (*PendingReplyRepo, error) {
+	r := &PendingReplyRepo{db: db}
+	var err error
+
+	if r.stSave, err = db.Prepare(`
+		INSERT INTO pending_replies (` + pendingReplyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (chat_id, message_id) DO UPDATE
+		   SET action     = EXCLUDED.action,
+		       base_url   = EXCLUDED.base_url,
+		       tg_user_id = EXCLUDED.tg_user_id,
+		       expires_at = EXCLUDED.expires_at`); err != nil {
+		return nil, fmt.Errorf("prepare save: %w", err)
+	}
+
+	if r.stGet, err = db.Prepare(`
+		SELECT ` + pendingReplyColumns + `
+		  FROM pending_replies
+		 WHERE chat_id = $1
+		   AND message_id = $2
+		   AND expires_at > $3`); err != nil {
+		return nil, fmt.Errorf("prepare get: %w", err)
+	}
+
+	if r.stTake, err = db.Prepare(`
+		DELETE FROM pending_replies
+		 WHERE chat_id = $1
+		   AND message_id = $2
+		   AND expires_at > $3
+		RETURNING ` + pendingReplyColumns); err != nil {
+		return nil, fmt.Errorf("prepare take: %w", err)
+	}
+
+	if r.stDeleteExpired, err = db.Prepare(`
+		DELETE FROM pending_replies WHERE expires_at <= $1`); err != nil {
+		return nil, fmt.Errorf("prepare deleteExpired: %w", err)
+	}
+
+	return r, nil
+}
+
+// Close освобождает prepared‑statements.
+func (r *PendingReplyRepo) Close() error {
+	for _, st := range []*sql.Stmt{r.stSave, r.stGet, r.stTake, r.stDeleteExpired} {
+		if st != nil {
+			if err := st.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Save создаёт или перезаписывает ожидание ответа.
+func (r *PendingReplyRepo) Save(ctx context.Context, p *domain.PendingReply) error {
+	if _, err := r.stSave.ExecContext(ctx,
+		p.ChatID, p.MessageID, p.Action, p.BaseURL, p.TgUserID, p.ExpiresAt); err != nil {
+		return fmt.Errorf("save pending reply: %w", err)
+	}
+	return nil
+}
+
+// Get возвращает неистёкшее ожидание ответа или ErrPendingReplyNotFound.
+func (r *PendingReplyRepo) Get(ctx context.Context, chatID int64, messageID int, now time.Time) (*domain.PendingReply, error) {
+	p, err := r.scan(r.stGet.QueryRowContext(ctx, chatID, messageID, now))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, appErr.ErrPendingReplyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get pending reply: %w", err)
+	}
+	return p, nil
+}
+
+// Take удаляет неистёкшее ожидание ответа и возвращает его. Строка
+// достаётся ровно одной реплике; остальные получат ErrPendingReplyNotFound.
+func (r *PendingReplyRepo) Take(ctx context.Context, chatID int64, messageID int, now time.Time) (*domain.PendingReply, error) {
+	p, err := r.scan(r.stTake.QueryRowContext(ctx, chatID, messageID, now))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, appErr.ErrPendingReplyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("take pending reply: %w", err)
+	}
+	return p, nil
+}
+
+// DeleteExpired удаляет ожидания, истёкшие к now.
+func (r *PendingReplyRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res, err := r.stDeleteExpired.ExecContext(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired pending replies: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (r *PendingReplyRepo) scan(row interface{ Scan(...any) error }) (*domain.PendingReply, error) {
+	var p domain.PendingReply
+	if err := row.Scan(&p.ChatID, &p.MessageID, &p.Action, &p.BaseURL, &p.TgUserID, &p.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"time"
+	appErr "victa/internal/errors"
+
+	"victa/internal/domain"
+)
+
+// RoutingRuleRepo реализует RoutingRuleRepository через prepared‑statements.
+type RoutingRuleRepo struct {
+	db                  *sql.DB
+	stGetAllByCompanyID *sql.Stmt
+	stGetByID           *sql.Stmt
+	stCreate            *sql.Stmt
+	stDelete            *sql.Stmt
+	stMoveUp            *sql.Stmt
+}
+
+const routingRuleColumns = `id, company_id, position, source, app_id, branch, status,
+		       trigger_type, severity, label, action, chat_ids, created_at, updated_at`
+
+// NewRoutingRuleRepo подготавливает выражения; при ошибке сразу вернёт её.
+func NewRoutingRuleRepo(db *sql.DB) (*RoutingRuleRepo, error) {
+	r := &RoutingRuleRepo{db: db}
+	var err error
+
+	if r.stGetAllByCompanyID, err = db.Prepare(`
+		SELECT ` + routingRuleColumns + `
+		  FROM routing_rules
+		 WHERE company_id = $1
+		 ORDER BY position, id`); err != nil {
+		return nil, fmt.Errorf("prepare getAllByCompanyID: %w", err)
+	}
+
+	if r.stGetByID, err = db.Prepare(`
+		SELECT ` + routingRuleColumns + `
+		  FROM routing_rules
+		 WHERE id = $1`); err != nil {
+		return nil, fmt.Errorf("prepare getByID: %w", err)
+	}
+
+	// новое правило встаёт в конец списка компании
+	if r.stCreate, err = db.Prepare(`
+		INSERT INTO routing_rules (company_id, position, source, app_id, branch, status,
+		                           trigger_type, severity, label, action, chat_ids, created_at, updated_at)
+		SELECT $1, COALESCE(MAX(position), 0) + 1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $11
+		  FROM routing_rules
+		 WHERE company_id = $1
+		RETURNING ` + routingRuleColumns); err != nil {
+		return nil, fmt.Errorf("prepare create: %w", err)
+	}
+
+	if r.stDelete, err = db.Prepare(`DELETE FROM routing_rules WHERE id = $1`); err != nil {
+		return nil, fmt.Errorf("prepare delete: %w", err)
+	}
+
+	// меняет правило местами с предыдущим одним запросом
+	if r.stMoveUp, err = db.Prepare(`
+		WITH cur AS (SELECT id, company_id, position
+		               FROM routing_rules
+		              WHERE id = $1),
+		     prev AS (SELECT r.id, r.position
+		                FROM routing_rules r
+		                JOIN cur ON r.company_id = cur.company_id
+		               WHERE r.position < cur.position
+		               ORDER BY r.position DESC
+		               LIMIT 1)
+		UPDATE routing_rules r
+		   SET position   = CASE WHEN r.id = cur.id THEN prev.position ELSE cur.position END,
+		       updated_at = $2
+		  FROM cur, prev
+		 WHERE r.id IN (cur.id, prev.id)`); err != nil {
+		return nil, fmt.Errorf("prepare moveUp: %w", err)
+	}
+
+	return r, nil
+}
+
+// Close освобождает prepared‑statements.
+func (r *RoutingRuleRepo) Close() error {
+	for _, st := range []*sql.Stmt{r.stGetAllByCompanyID, r.stGetByID, r.stCreate, r.stDelete, r.stMoveUp} {
+		if st != nil {
+			if err := st.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetAllByCompanyID возвращает правила компании в порядке проверки.
+func (r *RoutingRuleRepo) GetAllByCompanyID(ctx context.Context, companyID int64) ([]domain.RoutingRule, error) {
+	rows, err := r.stGetAllByCompanyID.QueryContext(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("query routing rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []domain.RoutingRule
+	for rows.Next() {
+		rule, err := r.scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan routing rule: %w", err)
+		}
+		rules = append(rules, *rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate routing rules: %w", err)
+	}
+	return rules, nil
+}
+
+// GetByID возвращает правило или ErrRoutingRuleNotFound.
+func (r *RoutingRuleRepo) GetByID(ctx context.Context, ruleID int64) (*domain.RoutingRule, error) {
+	rule, err := r.scan(r.stGetByID.QueryRowContext(ctx, ruleID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, appErr.ErrRoutingRuleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get routing rule: %w", err)
+	}
+	return rule, nil
+}
+
+// Create добавляет правило в конец списка компании.
+func (r *RoutingRuleRepo) Create(ctx context.Context, rule *domain.RoutingRule) (*domain.RoutingRule, error) {
+	created, err := r.scan(r.stCreate.QueryRowContext(ctx,
+		rule.CompanyID,
+		rule.Source,
+		rule.AppID,
+		rule.Branch,
+		rule.Status,
+		rule.TriggerType,
+		rule.Severity,
+		rule.Label,
+		rule.Action,
+		pq.Array(rule.ChatIDs),
+		time.Now().UTC(),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("insert routing rule: %w", err)
+	}
+	return created, nil
+}
+
+// Delete удаляет правило.
+func (r *RoutingRuleRepo) Delete(ctx context.Context, ruleID int64) error {
+	res, err := r.stDelete.ExecContext(ctx, ruleID)
+	if err != nil {
+		return fmt.Errorf("delete routing rule: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return appErr.ErrRoutingRuleNotFound
+	}
+	return nil
+}
+
+// MoveUp поднимает правило на одну позицию; первое остаётся на месте.
+func (r *RoutingRuleRepo) MoveUp(ctx context.Context, ruleID int64) error {
+	if _, err := r.stMoveUp.ExecContext(ctx, ruleID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("move routing rule: %w", err)
+	}
+	return nil
+}
+
+func (r *RoutingRuleRepo) scan(row interface{ Scan(...any) error }) (*domain.RoutingRule, error) {
+	var rule domain.RoutingRule
+	err := row.Scan(
+		&rule.ID,
+		&rule.CompanyID,
+		&rule.Position,
+		&rule.Source,
+		&rule.AppID,
+		&rule.Branch,
+		&rule.Status,
+		&rule.TriggerType,
+		&rule.Severity,
+		&rule.Label,
+		&rule.Action,
+		pq.Array(&rule.ChatIDs),
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"time"
+	appErr "victa/internal/errors"
+
+	"victa/internal/domain"
+)
+
+// UserIdentityRepo реализует UserIdentityRepository через prepared‑statements.
+type UserIdentityRepo struct {
+	db               *sql.DB
+	stGetAllByUserID *sql.Stmt
+	stSet            *sql.Stmt
+	stDelete         *sql.Stmt
+	stGetTgIDs       *sql.Stmt
+}
+
+// NewUserIdentityRepo подготавливает выражения; при ошибке сразу вернёт её.
+func NewUserIdentityRepo(db *sql.DB) (*UserIdentityRepo, error) {
+	r := &UserIdentityRepo{db: db}
+	var err error
+
+	if r.stGetAllByUserID, err = db.Prepare(`
+		SELECT user_id, provider, external_id, created_at, updated_at
+		  FROM user_identities
+		 WHERE user_id = $1
+		 ORDER BY provider`); err != nil {
+		return nil, fmt.Errorf("prepare getAllByUserID: %w", err)
+	}
+
+	if r.stSet, err = db.Prepare(`
+		INSERT INTO user_identities (user_id, provider, external_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (user_id, provider) DO UPDATE
+		   SET external_id = EXCLUDED.external_id,
+		       updated_at  = EXCLUDED.updated_at
+		RETURNING user_id, provider, external_id, created_at, updated_at`); err != nil {
+		return nil, fmt.Errorf("prepare set: %w", err)
+	}
+
+	if r.stDelete, err = db.Prepare(`
+		DELETE FROM user_identities
+		 WHERE user_id = $1
+		   AND provider = $2`); err != nil {
+		return nil, fmt.Errorf("prepare delete: %w", err)
+	}
+
+	if r.stGetTgIDs, err = db.Prepare(`
+		SELECT ui.external_id, u.tg_id
+		  FROM user_identities ui
+		  JOIN users u ON u.id = ui.user_id
+		  JOIN user_companies uc ON uc.user_id = ui.user_id
+		 WHERE uc.company_id = $1
+		   AND ui.provider = $2
+		   AND ui.external_id = ANY ($3)
+		   AND u.tg_id IS NOT NULL`); err != nil {
+		return nil, fmt.Errorf("prepare getTgIDs: %w", err)
+	}
+
+	return r, nil
+}
+
+// Close освобождает prepared‑statements.
+func (r *UserIdentityRepo) Close() error {
+	for _, st := range []*sql.Stmt{r.stGetAllByUserID, r.stSet, r.stDelete, r.stGetTgIDs} {
+		if st != nil {
+			if err := st.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetAllByUserID возвращает связанные учётные записи пользователя.
+func (r *UserIdentityRepo) GetAllByUserID(ctx context.Context, userID int64) ([]domain.UserIdentity, error) {
+	rows, err := r.stGetAllByUserID.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query user identities: %w", err)
+	}
+	defer rows.Close()
+
+	var identities []domain.UserIdentity
+	for rows.Next() {
+		var ui domain.UserIdentity
+		if err := rows.Scan(&ui.UserID, &ui.Provider, &ui.ExternalID, &ui.CreatedAt, &ui.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan user identity: %w", err)
+		}
+		identities = append(identities, ui)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate user identities: %w", err)
+	}
+	return identities, nil
+}
+
+// Set связывает учётную запись провайдера с пользователем, заменяя прежнюю.
+// Если запись уже занята другим пользователем, вернёт ErrIdentityTaken.
+func (r *UserIdentityRepo) Set(ctx context.Context, identity *domain.UserIdentity) (*domain.UserIdentity, error) {
+	var ui domain.UserIdentity
+	err := r.stSet.QueryRowContext(ctx, identity.UserID, identity.Provider, identity.ExternalID, time.Now().UTC()).
+		Scan(&ui.UserID, &ui.Provider, &ui.ExternalID, &ui.CreatedAt, &ui.UpdatedAt)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return nil, appErr.ErrIdentityTaken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("set user identity: %w", err)
+	}
+	return &ui, nil
+}
+
+// Delete отвязывает учётную запись провайдера.
+func (r *UserIdentityRepo) Delete(ctx context.Context, userID int64, provider string) error {
+	if _, err := r.stDelete.ExecContext(ctx, userID, provider); err != nil {
+		return fmt.Errorf("delete user identity: %w", err)
+	}
+	return nil
+}
+
+// GetTgIDs возвращает external_id → tg_id для участников компании.
+func (r *UserIdentityRepo) GetTgIDs(ctx context.Context, companyID int64, provider string, externalIDs []string) (map[string]string, error) {
+	rows, err := r.stGetTgIDs.QueryContext(ctx, companyID, provider, pq.Array(externalIDs))
+	if err != nil {
+		return nil, fmt.Errorf("query tg ids: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]string, len(externalIDs))
+	for rows.Next() {
+		var externalID, tgID string
+		if err := rows.Scan(&externalID, &tgID); err != nil {
+			return nil, fmt.Errorf("scan tg id: %w", err)
+		}
+		out[externalID] = tgID
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate tg ids: %w", err)
+	}
+	return out, nil
+}
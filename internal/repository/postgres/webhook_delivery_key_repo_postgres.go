@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WebhookDeliveryKeyRepo реализует WebhookDeliveryKeyRepository через prepared‑statements.
+type WebhookDeliveryKeyRepo struct {
+	db              *sql.DB
+	stClaim         *sql.Stmt
+	stRelease       *sql.Stmt
+	stDeleteExpired *sql.Stmt
+}
+
+// NewWebhookDeliveryKeyRepo подготавливает выражения; при ошибке сразу вернёт её.
+func NewWebhookDeliveryKeyRepo(db *sql.DB) (*WebhookDeliveryKeyRepo, error) {
+	r := &WebhookDeliveryKeyRepo{db: db}
+	var err error
+
+	// Просроченный ключ перезаписывается, живой — оставляется как есть:
+	// RETURNING вернёт строку только в первом случае.
+	if r.stClaim, err = db.Prepare(`
+		INSERT INTO webhook_delivery_keys (company_id, provider, delivery_key, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (company_id, provider, delivery_key) DO UPDATE
+		   SET created_at = EXCLUDED.created_at,
+		       expires_at = EXCLUDED.expires_at
+		 WHERE webhook_delivery_keys.expires_at <= EXCLUDED.created_at
+		RETURNING delivery_key`); err != nil {
+		return nil, fmt.Errorf("prepare claim: %w", err)
+	}
+
+	if r.stRelease, err = db.Prepare(`
+		DELETE FROM webhook_delivery_keys
+		 WHERE company_id = $1
+		   AND provider = $2
+		   AND delivery_key = $3`); err != nil {
+		return nil, fmt.Errorf("prepare release: %w", err)
+	}
+
+	if r.stDeleteExpired, err = db.Prepare(`
+		DELETE FROM webhook_delivery_keys WHERE expires_at <= $1`); err != nil {
+		return nil, fmt.Errorf("prepare deleteExpired: %w", err)
+	}
+
+	return r, nil
+}
+
+// Close освобождает prepared‑statements.
+func (r *WebhookDeliveryKeyRepo) Close() error {
+	for _, st := range []*sql.Stmt{r.stClaim, r.stRelease, r.stDeleteExpired} {
+		if st != nil {
+			if err := st.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Claim записывает ключ доставки. false — такой ключ уже обработан
+// и ещё не истёк.
+func (r *WebhookDeliveryKeyRepo) Claim(ctx context.Context, companyID int64, provider, key string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+
+	var got string
+	err := r.stClaim.QueryRowContext(ctx, companyID, provider, key, now, now.Add(ttl)).Scan(&got)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("claim delivery key: %w", err)
+	}
+	return true, nil
+}
+
+// Release удаляет ключ, чтобы повтор доставки обработался заново.
+func (r *WebhookDeliveryKeyRepo) Release(ctx context.Context, companyID int64, provider, key string) error {
+	if _, err := r.stRelease.ExecContext(ctx, companyID, provider, key); err != nil {
+		return fmt.Errorf("release delivery key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired чистит просроченные ключи и возвращает их число.
+func (r *WebhookDeliveryKeyRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	res, err := r.stDeleteExpired.ExecContext(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("delete expired delivery keys: %w", err)
+	}
+	return res.RowsAffected()
+}
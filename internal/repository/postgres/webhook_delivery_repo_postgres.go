@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	appErr "victa/internal/errors"
+
+	"victa/internal/domain"
+)
+
+// WebhookDeliveryRepo реализует WebhookDeliveryRepository через prepared‑statements.
+type WebhookDeliveryRepo struct {
+	db                   *sql.DB
+	stCreate             *sql.Stmt
+	stGetByID            *sql.Stmt
+	stGetLastByCompanyID *sql.Stmt
+	stDeleteOlderThan    *sql.Stmt
+}
+
+// NewWebhookDeliveryRepo подготавливает выражения; при ошибке сразу вернёт её.
+func NewWebhookDeliveryRepo(db *sql.DB) (*WebhookDeliveryRepo, error) {
+	r := &WebhookDeliveryRepo{db: db}
+	var err error
+
+	if r.stCreate, err = db.Prepare(`
+		INSERT INTO webhook_deliveries (company_id, provider, headers, body, status, outcome, error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, company_id, provider, headers, body, status, outcome, error, created_at`); err != nil {
+		return nil, fmt.Errorf("prepare create: %w", err)
+	}
+
+	if r.stGetByID, err = db.Prepare(`
+		SELECT id, company_id, provider, headers, body, status, outcome, error, created_at
+		  FROM webhook_deliveries
+		 WHERE id = $1`); err != nil {
+		return nil, fmt.Errorf("prepare getByID: %w", err)
+	}
+
+	if r.stGetLastByCompanyID, err = db.Prepare(`
+		SELECT id, company_id, provider, headers, body, status, outcome, error, created_at
+		  FROM webhook_deliveries
+		 WHERE company_id = $1
+		 ORDER BY id DESC
+		 LIMIT $2`); err != nil {
+		return nil, fmt.Errorf("prepare getLastByCompanyID: %w", err)
+	}
+
+	if r.stDeleteOlderThan, err = db.Prepare(`
+		DELETE FROM webhook_deliveries WHERE created_at < $1`); err != nil {
+		return nil, fmt.Errorf("prepare deleteOlderThan: %w", err)
+	}
+
+	return r, nil
+}
+
+// Close освобождает prepared‑statements.
+func (r *WebhookDeliveryRepo) Close() error {
+	for _, st := range []*sql.Stmt{
+		r.stCreate, r.stGetByID, r.stGetLastByCompanyID, r.stDeleteOlderThan,
+	} {
+		if st != nil {
+			if err := st.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Create сохраняет входящий вебхук.
+func (r *WebhookDeliveryRepo) Create(ctx context.Context, d *domain.WebhookDelivery) (*domain.WebhookDelivery, error) {
+	headers, err := json.Marshal(d.Headers)
+	if err != nil {
+		return nil, fmt.Errorf("marshal headers: %w", err)
+	}
+
+	out, err := r.scan(r.stCreate.QueryRowContext(ctx,
+		d.CompanyID, d.Provider, headers, d.Body, d.Status, d.Outcome, d.Error, time.Now().UTC()))
+	if err != nil {
+		return nil, fmt.Errorf("create webhook delivery: %w", err)
+	}
+	return out, nil
+}
+
+// GetByID возвращает вебхук или ErrWebhookDeliveryNotFound.
+func (r *WebhookDeliveryRepo) GetByID(ctx context.Context, id int64) (*domain.WebhookDelivery, error) {
+	d, err := r.scan(r.stGetByID.QueryRowContext(ctx, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, appErr.ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get webhook delivery: %w", err)
+	}
+	return d, nil
+}
+
+// GetLastByCompanyID возвращает последние limit вебхуков компании.
+func (r *WebhookDeliveryRepo) GetLastByCompanyID(ctx context.Context, companyID int64, limit int) ([]domain.WebhookDelivery, error) {
+	rows, err := r.stGetLastByCompanyID.QueryContext(ctx, companyID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query webhook deliveries: %w", err)
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	list := make([]domain.WebhookDelivery, 0, limit)
+	for rows.Next() {
+		d, err := r.scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		list = append(list, *d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return list, nil
+}
+
+// DeleteOlderThan удаляет вебхуки старше t и возвращает их число.
+func (r *WebhookDeliveryRepo) DeleteOlderThan(ctx context.Context, t time.Time) (int64, error) {
+	res, err := r.stDeleteOlderThan.ExecContext(ctx, t.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("delete webhook deliveries: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (r *WebhookDeliveryRepo) scan(row interface{ Scan(...any) error }) (*domain.WebhookDelivery, error) {
+	var (
+		d       domain.WebhookDelivery
+		headers []byte
+	)
+	err := row.Scan(&d.ID, &d.CompanyID, &d.Provider, &headers, &d.Body,
+		&d.Status, &d.Outcome, &d.Error, &d.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(headers, &d.Headers); err != nil {
+		return nil, fmt.Errorf("unmarshal headers: %w", err)
+	}
+	return &d, nil
+}
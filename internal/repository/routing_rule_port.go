@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"victa/internal/domain"
+)
+
+type RoutingRuleRepository interface {
+	GetAllByCompanyID(ctx context.Context, companyID int64) ([]domain.RoutingRule, error)
+	GetByID(ctx context.Context, ruleID int64) (*domain.RoutingRule, error)
+	Create(ctx context.Context, rule *domain.RoutingRule) (*domain.RoutingRule, error)
+	Delete(ctx context.Context, ruleID int64) error
+	MoveUp(ctx context.Context, ruleID int64) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"victa/internal/domain"
+)
+
+type UserIdentityRepository interface {
+	GetAllByUserID(ctx context.Context, userID int64) ([]domain.UserIdentity, error)
+	Set(ctx context.Context, identity *domain.UserIdentity) (*domain.UserIdentity, error)
+	Delete(ctx context.Context, userID int64, provider string) error
+	// GetTgIDs возвращает tg_id участников компании по их учётным записям провайдера.
+	GetTgIDs(ctx context.Context, companyID int64, provider string, externalIDs []string) (map[string]string, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type WebhookDeliveryKeyRepository interface {
+	Claim(ctx context.Context, companyID int64, provider, key string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, companyID int64, provider, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"victa/internal/domain"
+)
+
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, d *domain.WebhookDelivery) (*domain.WebhookDelivery, error)
+	GetByID(ctx context.Context, id int64) (*domain.WebhookDelivery, error)
+	GetLastByCompanyID(ctx context.Context, companyID int64, limit int) ([]domain.WebhookDelivery, error)
+	DeleteOlderThan(ctx context.Context, t time.Time) (int64, error)
+}
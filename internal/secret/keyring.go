@@ -0,0 +1,212 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix отличает зашифрованное значение от открытого текста,
+// записанного до включения шифрования. В v2 шифротекст привязан к месту
+// хранения через AAD, v1 — без привязки, его только читаем.
+const (
+	prefix       = "enc:v2:"
+	legacyPrefix = "enc:v1:"
+)
+
+const keySize = 32 // AES‑256
+
+var (
+	// ErrUnknownKey — значение зашифровано ключом, которого нет в кольце.
+	ErrUnknownKey = errors.New("secret: unknown encryption key")
+	// ErrMalformed — значение с префиксом шифра повреждено.
+	ErrMalformed = errors.New("secret: malformed ciphertext")
+)
+
+// Keyring шифрует секреты конвертом: каждое значение шифруется своим
+// случайным ключом данных (AES‑256‑GCM), а ключ данных — мастер‑ключом
+// приложения. Мастер‑ключей может быть несколько: новые значения
+// шифруются активным (первым), а прочитать можно любым из кольца —
+// так старый ключ выводится из оборота без простоя.
+//
+// Формат значения: enc:v2:<id ключа>:<ключ данных>:<шифротекст>,
+// двоичные части — base64 без паддинга, nonce идёт перед шифротекстом.
+// aad (например, «компания:колонка») участвует в обоих шифрованиях:
+// значение, скопированное в чужую строку или колонку, не расшифруется.
+type Keyring struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// NewKeyring разбирает спецификацию «id1:base64,id2:base64», где каждый
+// ключ — 32 байта в base64. Первый ключ становится активным.
+func NewKeyring(spec string) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]cipher.AEAD)}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(part, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("secret: key %q must look like id:base64", part)
+		}
+		if _, dup := k.keys[id]; dup {
+			return nil, fmt.Errorf("secret: duplicate key id %q", id)
+		}
+
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("secret: key %q: %w", id, err)
+		}
+		if len(raw) != keySize {
+			return nil, fmt.Errorf("secret: key %q must be %d bytes, got %d", id, keySize, len(raw))
+		}
+		aead, err := newAEAD(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		k.keys[id] = aead
+		if k.active == "" {
+			k.active = id
+		}
+	}
+
+	if k.active == "" {
+		return nil, errors.New("secret: no encryption keys")
+	}
+	return k, nil
+}
+
+// Encrypt шифрует plain активным ключом и привязывает к aad.
+// Пустая строка не шифруется.
+func (k *Keyring) Encrypt(plain, aad string) (string, error) {
+	if plain == "" {
+		return "", nil
+	}
+
+	dek := make([]byte, keySize)
+	if _, err := rand.Read(dek); err != nil {
+		return "", fmt.Errorf("secret: data key: %w", err)
+	}
+	dataAEAD, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+
+	wrapped, err := seal(k.keys[k.active], dek, []byte(aad))
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(dataAEAD, []byte(plain), []byte(aad))
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + k.active + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt расшифровывает значение любым ключом кольца, сверяя aad.
+// Значение без префикса считается открытым текстом и возвращается как есть,
+// значение v1 расшифровывается без aad.
+func (k *Keyring) Decrypt(value, aad string) (string, error) {
+	var rest string
+	switch {
+	case strings.HasPrefix(value, prefix):
+		rest = strings.TrimPrefix(value, prefix)
+	case strings.HasPrefix(value, legacyPrefix):
+		rest, aad = strings.TrimPrefix(value, legacyPrefix), ""
+	default:
+		return value, nil
+	}
+
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 {
+		return "", ErrMalformed
+	}
+	kek, ok := k.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownKey, parts[0])
+	}
+
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	dek, err := open(kek, wrapped, []byte(aad))
+	if err != nil {
+		return "", err
+	}
+	dataAEAD, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+	plain, err := open(dataAEAD, ciphertext, []byte(aad))
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// NeedsRotation — значение надо перешифровать: оно открытое, записано
+// в формате v1 без aad или зашифровано не активным ключом.
+func (k *Keyring) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	if !strings.HasPrefix(value, prefix) {
+		return true
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	return id != k.active
+}
+
+// IsEncrypted — значение записано Keyring.Encrypt.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix) || strings.HasPrefix(value, legacyPrefix)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("secret: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("secret: %w", err)
+	}
+	return aead, nil
+}
+
+func seal(aead cipher.AEAD, plain, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("secret: nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plain, aad), nil
+}
+
+func open(aead cipher.AEAD, data, aad []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("secret: decrypt: %w", err)
+	}
+	return plain, nil
+}
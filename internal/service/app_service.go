@@ -2,10 +2,13 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 
 	"victa/internal/domain"
+	appErr "victa/internal/errors"
 	"victa/internal/repository"
 )
 
@@ -14,12 +17,16 @@ var ErrInvalidInput = errors.New("app name and slug must be non-empty")
 
 // AppService инкапсулирует бизнес‑логику для сущности App.
 type AppService struct {
-	repo repository.AppRepository
+	repo            repository.AppRepository
+	integrationRepo repository.AppIntegrationRepository
 }
 
 // NewAppService создаёт новый сервис для работы с приложениями.
-func NewAppService(repo repository.AppRepository) *AppService {
-	return &AppService{repo: repo}
+func NewAppService(
+	repo repository.AppRepository,
+	integrationRepo repository.AppIntegrationRepository,
+) *AppService {
+	return &AppService{repo: repo, integrationRepo: integrationRepo}
 }
 
 // GetByID возвращает приложение по ID.
@@ -64,3 +71,92 @@ func (s *AppService) Update(ctx context.Context, appID int64, name, slug string)
 func (s *AppService) Delete(ctx context.Context, appID int64) error {
 	return s.repo.Delete(ctx, appID)
 }
+
+// GetIntegration возвращает интеграции приложения (или ErrAppIntegrationNotFound).
+func (s *AppService) GetIntegration(ctx context.Context, appID int64) (*domain.AppIntegration, error) {
+	return s.integrationRepo.GetByAppID(ctx, appID)
+}
+
+// FindIntegration возвращает интеграции приложения или nil, если их нет.
+func (s *AppService) FindIntegration(ctx context.Context, appID int64) (*domain.AppIntegration, error) {
+	return orNil(s.integrationRepo.GetByAppID(ctx, appID))
+}
+
+// CreateOrUpdateIntegration принимает JSON‑payload и сохраняет
+// интеграции приложения. Пустые строки сохраняются как NULL.
+func (s *AppService) CreateOrUpdateIntegration(
+	ctx context.Context,
+	appID int64,
+	payload string,
+) (*domain.AppIntegration, error) {
+	var ai domain.AppIntegration
+	if err := json.Unmarshal([]byte(payload), &ai); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	ai.AppID = appID
+
+	for _, f := range []**string{
+		&ai.CodemagicAppID, &ai.GitlabProjectID, &ai.BugsnagProjectID,
+		&ai.GithubRepository, &ai.SentryProject,
+		&ai.DeployNotificationChatID, &ai.IssuesNotificationChatID,
+		&ai.ErrorsNotificationChatID, &ai.MergeRequestsNotificationChatID,
+		&ai.PipelinesNotificationChatID, &ai.ReviewsNotificationChatID,
+	} {
+		if *f != nil && strings.TrimSpace(**f) == "" {
+			*f = nil
+		}
+	}
+	return s.integrationRepo.CreateOrUpdate(ctx, &ai)
+}
+
+// FindByCodemagicAppID ищет приложение компании по ID приложения Codemagic.
+// Если привязки нет, возвращает nil без ошибки.
+func (s *AppService) FindByCodemagicAppID(ctx context.Context, companyID int64, codemagicAppID string) (*domain.AppIntegration, error) {
+	if codemagicAppID == "" {
+		return nil, nil
+	}
+	return orNil(s.integrationRepo.GetByCodemagicAppID(ctx, companyID, codemagicAppID))
+}
+
+// FindByGitlabProject ищет приложение компании по ID или пути проекта GitLab.
+// Если привязки нет, возвращает nil без ошибки.
+func (s *AppService) FindByGitlabProject(ctx context.Context, companyID int64, projectID, projectPath string) (*domain.AppIntegration, error) {
+	if projectID == "" && projectPath == "" {
+		return nil, nil
+	}
+	return orNil(s.integrationRepo.GetByGitlabProject(ctx, companyID, projectID, projectPath))
+}
+
+// FindByBugsnagProjectID ищет приложение компании по ID проекта Bugsnag.
+// Если привязки нет, возвращает nil без ошибки.
+func (s *AppService) FindByBugsnagProjectID(ctx context.Context, companyID int64, projectID string) (*domain.AppIntegration, error) {
+	if projectID == "" {
+		return nil, nil
+	}
+	return orNil(s.integrationRepo.GetByBugsnagProjectID(ctx, companyID, projectID))
+}
+
+// FindByGithubRepository ищет приложение компании по ID или имени owner/repo
+// репозитория GitHub. Если привязки нет, возвращает nil без ошибки.
+func (s *AppService) FindByGithubRepository(ctx context.Context, companyID int64, repoID, fullName string) (*domain.AppIntegration, error) {
+	if repoID == "" && fullName == "" {
+		return nil, nil
+	}
+	return orNil(s.integrationRepo.GetByGithubRepository(ctx, companyID, repoID, fullName))
+}
+
+// FindBySentryProject ищет приложение компании по ID или slug'у проекта Sentry.
+// Если привязки нет, возвращает nil без ошибки.
+func (s *AppService) FindBySentryProject(ctx context.Context, companyID int64, projectID, projectSlug string) (*domain.AppIntegration, error) {
+	if projectID == "" && projectSlug == "" {
+		return nil, nil
+	}
+	return orNil(s.integrationRepo.GetBySentryProject(ctx, companyID, projectID, projectSlug))
+}
+
+func orNil(ai *domain.AppIntegration, err error) (*domain.AppIntegration, error) {
+	if errors.Is(err, appErr.ErrAppIntegrationNotFound) {
+		return nil, nil
+	}
+	return ai, err
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+
+	"victa/internal/domain"
+	"victa/internal/repository"
+)
+
+// ArtifactFileService кэширует file_id артефактов, уже загруженных в Telegram.
+type ArtifactFileService struct {
+	repo repository.ArtifactFileRepository
+}
+
+// NewArtifactFileService создаёт сервис кэша артефактов.
+func NewArtifactFileService(repo repository.ArtifactFileRepository) *ArtifactFileService {
+	return &ArtifactFileService{repo: repo}
+}
+
+// GetByPath возвращает загруженный артефакт (или ErrArtifactFileNotFound из repo).
+func (s *ArtifactFileService) GetByPath(ctx context.Context, companyID int64, artifactPath string) (*domain.ArtifactFile, error) {
+	return s.repo.GetByPath(ctx, companyID, artifactPath)
+}
+
+// Save запоминает file_id загруженного артефакта.
+func (s *ArtifactFileService) Save(ctx context.Context, f *domain.ArtifactFile) (*domain.ArtifactFile, error) {
+	return s.repo.Save(ctx, f)
+}
+
+// Forget удаляет file_id, который Telegram больше не принимает
+// (например, после смены токена бота).
+func (s *ArtifactFileService) Forget(ctx context.Context, companyID int64, artifactPath string) error {
+	return s.repo.Delete(ctx, companyID, artifactPath)
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Операции над ошибкой в Bugsnag Data Access API.
+const (
+	BugsnagOperationFix    = "fix"
+	BugsnagOperationIgnore = "ignore"
+	BugsnagOperationSnooze = "snooze"
+	BugsnagOperationAssign = "assign"
+)
+
+// ErrBugsnagCollaboratorNotFound — в проекте нет участника с таким email.
+var ErrBugsnagCollaboratorNotFound = errors.New("bugsnag collaborator not found")
+
+// BugsnagService инкапсулирует работу с Bugsnag Data Access API.
+type BugsnagService struct {
+	client  HTTPDoer
+	baseURL string
+	snooze  time.Duration // на сколько откладывается ошибка по кнопке
+}
+
+// NewBugsnagService возвращает сервис с HTTP‑клиентом с таймаутом 10 s
+// и откладыванием ошибок на сутки.
+func NewBugsnagService() *BugsnagService {
+	return &BugsnagService{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		baseURL: "https://api.bugsnag.com",
+		snooze:  24 * time.Hour,
+	}
+}
+
+// WithHTTPClient позволяет подменить клиента (юнит‑тест либо кастомные опции).
+func (s *BugsnagService) WithHTTPClient(c HTTPDoer) *BugsnagService {
+	s.client = c
+	return s
+}
+
+// SnoozePeriod — на сколько SnoozeError откладывает ошибку.
+func (s *BugsnagService) SnoozePeriod() time.Duration { return s.snooze }
+
+// FixError помечает ошибку исправленной.
+func (s *BugsnagService) FixError(ctx context.Context, token, projectID, errorID string) error {
+	return s.updateError(ctx, token, projectID, errorID, map[string]any{
+		"operation": BugsnagOperationFix,
+	})
+}
+
+// IgnoreError игнорирует ошибку.
+func (s *BugsnagService) IgnoreError(ctx context.Context, token, projectID, errorID string) error {
+	return s.updateError(ctx, token, projectID, errorID, map[string]any{
+		"operation": BugsnagOperationIgnore,
+	})
+}
+
+// SnoozeError откладывает ошибку: Bugsnag откроет её снова,
+// если она повторится после SnoozePeriod.
+func (s *BugsnagService) SnoozeError(ctx context.Context, token, projectID, errorID string) error {
+	return s.updateError(ctx, token, projectID, errorID, map[string]any{
+		"operation": BugsnagOperationSnooze,
+		"reopen_rules": map[string]any{
+			"reopen_if": "occurs_after",
+			"seconds":   int64(s.snooze.Seconds()),
+		},
+	})
+}
+
+// AssignError назначает ошибку на участника проекта с указанным email.
+func (s *BugsnagService) AssignError(ctx context.Context, token, projectID, errorID, email string) error {
+	collaboratorID, err := s.findCollaborator(ctx, token, projectID, email)
+	if err != nil {
+		return err
+	}
+	return s.updateError(ctx, token, projectID, errorID, map[string]any{
+		"operation":                BugsnagOperationAssign,
+		"assigned_collaborator_id": collaboratorID,
+	})
+}
+
+// updateError делает PATCH /projects/{project_id}/errors/{error_id}.
+func (s *BugsnagService) updateError(ctx context.Context, token, projectID, errorID string, body map[string]any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/projects/%s/errors/%s",
+		s.baseURL, url.PathEscape(projectID), url.PathEscape(errorID))
+
+	resp, err := s.do(ctx, http.MethodPatch, endpoint, token, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("bugsnag %d: %s", resp.StatusCode, data)
+	}
+	return nil
+}
+
+// findCollaborator ищет ID участника проекта по email.
+func (s *BugsnagService) findCollaborator(ctx context.Context, token, projectID, email string) (string, error) {
+	endpoint := fmt.Sprintf("%s/projects/%s/collaborators?per_page=100",
+		s.baseURL, url.PathEscape(projectID))
+
+	resp, err := s.do(ctx, http.MethodGet, endpoint, token, nil)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("bugsnag %d: %s", resp.StatusCode, data)
+	}
+
+	var collaborators []struct {
+		ID    string `json:"id"`
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&collaborators); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+
+	for _, c := range collaborators {
+		if strings.EqualFold(c.Email, email) {
+			return c.ID, nil
+		}
+	}
+	return "", ErrBugsnagCollaboratorNotFound
+}
+
+func (s *BugsnagService) do(ctx context.Context, method, endpoint, token string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "token "+token)
+	req.Header.Set("X-Version", "2")
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"victa/internal/domain"
+	"victa/internal/repository"
+)
+
+// buildMessageTTL — сколько опрашивать билд без обновлений,
+// прежде чем считать его брошенным.
+const buildMessageTTL = 6 * time.Hour
+
+// BuildMessageService хранит соответствие билд → сообщение в Telegram.
+type BuildMessageService struct {
+	repo repository.BuildMessageRepository
+}
+
+// NewBuildMessageService создаёт сервис сообщений о билдах.
+func NewBuildMessageService(repo repository.BuildMessageRepository) *BuildMessageService {
+	return &BuildMessageService{repo: repo}
+}
+
+// GetByBuildID возвращает сообщение билда в чате (или ErrBuildMessageNotFound из repo).
+func (s *BuildMessageService) GetByBuildID(ctx context.Context, buildID, chatID string) (*domain.BuildMessage, error) {
+	return s.repo.GetByBuildID(ctx, buildID, chatID)
+}
+
+// GetActive возвращает билды, которые ещё идут и недавно обновлялись.
+func (s *BuildMessageService) GetActive(ctx context.Context) ([]domain.BuildMessage, error) {
+	return s.repo.GetUnfinished(ctx, time.Now().UTC().Add(-buildMessageTTL))
+}
+
+// Save сохраняет привязку билда к сообщению.
+func (s *BuildMessageService) Save(ctx context.Context, msg *domain.BuildMessage) (*domain.BuildMessage, error) {
+	return s.repo.Save(ctx, msg)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"victa/internal/domain"
+	appErr "victa/internal/errors"
+	"victa/internal/repository"
+)
+
+// chatStateTTL — сколько бот ждёт ответа пользователя. Telegram даёт боту
+// удалять свои сообщения только 48 часов, поэтому подсказки брошенного
+// диалога должны успеть удалиться раньше.
+const chatStateTTL = 24 * time.Hour
+
+// ChatStateService хранит незаконченные диалоги victa_bot в БД, чтобы они
+// переживали перезапуск и обрабатывались любой репликой.
+type ChatStateService struct {
+	repo repository.ChatStateRepository
+}
+
+// NewChatStateService создаёт сервис состояний чатов.
+func NewChatStateService(repo repository.ChatStateRepository) *ChatStateService {
+	return &ChatStateService{repo: repo}
+}
+
+// Get возвращает состояние чата; если диалога нет или он истёк — пустое.
+func (s *ChatStateService) Get(ctx context.Context, chatID int64) (*domain.ChatState, error) {
+	st, err := s.repo.GetByChatID(ctx, chatID)
+	if errors.Is(err, appErr.ErrChatStateNotFound) {
+		return &domain.ChatState{ChatID: chatID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// истёкший диалог начинается заново, но его подсказки ещё надо удалить
+	if !st.ExpiresAt.After(time.Now().UTC()) {
+		return &domain.ChatState{ChatID: chatID, MessageIDs: st.MessageIDs}, nil
+	}
+	return st, nil
+}
+
+// Update применяет fn к состоянию чата, сохраняет его и продлевает срок жизни.
+func (s *ChatStateService) Update(ctx context.Context, chatID int64, fn func(st *domain.ChatState)) error {
+	st, err := s.Get(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	fn(st)
+	st.ExpiresAt = time.Now().UTC().Add(chatStateTTL)
+
+	return s.repo.Save(ctx, st)
+}
+
+// Clear удаляет состояние чата и возвращает удалённое (nil, если его не было).
+func (s *ChatStateService) Clear(ctx context.Context, chatID int64) (*domain.ChatState, error) {
+	st, err := s.repo.Delete(ctx, chatID)
+	if errors.Is(err, appErr.ErrChatStateNotFound) {
+		return nil, nil
+	}
+	return st, err
+}
+
+// PurgeExpired удаляет брошенные диалоги и возвращает их.
+func (s *ChatStateService) PurgeExpired(ctx context.Context) ([]domain.ChatState, error) {
+	return s.repo.DeleteExpired(ctx, time.Now().UTC())
+}
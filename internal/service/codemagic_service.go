@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,22 +21,35 @@ type HTTPDoer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// ErrInvalidBuildRequest — для запуска билда нужны приложение, workflow и ветка.
+var ErrInvalidBuildRequest = errors.New("app_id, workflow_id and branch are required")
+
+// ErrArtifactTooLarge — артефакт больше допустимого размера загрузки.
+var ErrArtifactTooLarge = errors.New("artifact is too large")
+
+// ErrInvalidCodemagicAPIKey — Codemagic не принял API‑ключ.
+var ErrInvalidCodemagicAPIKey = errors.New("codemagic API key is invalid")
+
 // CodemagicService инкапсулирует работу с REST‑API Codemagic.
 type CodemagicService struct {
-	client  HTTPDoer // внедряем зависимость → легко подменить в тестах
-	baseURL string
-	ttl     time.Duration // срок жизни публичной ссылки на артефакт
+	client   HTTPDoer // внедряем зависимость → легко подменить в тестах
+	download HTTPDoer // скачивание артефактов: таймаут дольше, чем у API
+	baseURL  string
+	ttl      time.Duration // срок жизни публичной ссылки на артефакт
 }
 
 // NewCodemagicService возвращает сервис с:
 //   - базовым URL (без «/» в конце)
-//   - HTTP‑клиентом с таймаутом 10 s
+//   - HTTP‑клиентом с таймаутом 10 s (5 min для скачивания артефактов)
 //   - TTL публичной ссылки 7 дней
 func NewCodemagicService(baseURL string) *CodemagicService {
 	return &CodemagicService{
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		download: &http.Client{
+			Timeout: 5 * time.Minute,
+		},
 		baseURL: strings.TrimRight(baseURL, "/"),
 		ttl:     7 * 24 * time.Hour,
 	}
@@ -42,6 +58,7 @@ func NewCodemagicService(baseURL string) *CodemagicService {
 // WithHTTPClient позволяет подменить клиента (юнит‑тест либо кастомные опции).
 func (s *CodemagicService) WithHTTPClient(c HTTPDoer) *CodemagicService {
 	s.client = c
+	s.download = c
 	return s
 }
 
@@ -129,3 +146,244 @@ func (s *CodemagicService) GetArtifactPublicURL(
 	}
 	return out.URL, nil
 }
+
+// DownloadArtifact делает GET /artifacts/{path} и возвращает поток файла.
+// Если файл больше maxSize, возвращает ErrArtifactTooLarge. Поток
+// закрывает вызывающий.
+func (s *CodemagicService) DownloadArtifact(
+	ctx context.Context,
+	path, apiKey string,
+	maxSize int64,
+) (io.ReadCloser, error) {
+
+	url := fmt.Sprintf("%s/artifacts/%s", s.baseURL, strings.TrimPrefix(path, "/"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("x-auth-token", apiKey)
+
+	resp, err := s.download.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("codemagic %d: %s", resp.StatusCode, data)
+	}
+	if resp.ContentLength > maxSize {
+		_ = resp.Body.Close()
+		return nil, ErrArtifactTooLarge
+	}
+	// при chunked‑ответе длина неизвестна (-1): считаем байты по ходу чтения
+	return &limitedBody{
+		r:     io.LimitReader(resp.Body, maxSize+1),
+		body:  resp.Body,
+		limit: maxSize,
+	}, nil
+}
+
+// limitedBody отдаёт не больше limit байт тела ответа, а на лишнем байте
+// возвращает ErrArtifactTooLarge, чтобы загрузка в Telegram оборвалась.
+type limitedBody struct {
+	r     io.Reader
+	body  io.Closer
+	limit int64
+	read  int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		return n, ErrArtifactTooLarge
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
+
+// StartBuild делает POST /builds и возвращает ID запущенного билда.
+func (s *CodemagicService) StartBuild(
+	ctx context.Context,
+	apiKey string,
+	br domain.CodemagicBuildRequest,
+) (string, error) {
+
+	if br.AppID == "" || br.WorkflowID == "" || br.Branch == "" {
+		return "", ErrInvalidBuildRequest
+	}
+
+	body := map[string]any{
+		"appId":      br.AppID,
+		"workflowId": br.WorkflowID,
+		"branch":     br.Branch,
+	}
+	if len(br.Variables) > 0 {
+		body["environment"] = map[string]any{"variables": br.Variables}
+	}
+	payload, _ := json.Marshal(body)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/builds", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("x-auth-token", apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("execute request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		data, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("codemagic %d: %s", resp.StatusCode, data)
+	}
+
+	var out struct {
+		BuildID string `json:"buildId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	return out.BuildID, nil
+}
+
+// Rebuild запускает билд с тем же приложением, workflow и веткой,
+// что и buildID. Переменные окружения исходного запуска API не отдаёт.
+func (s *CodemagicService) Rebuild(ctx context.Context, buildID, apiKey string) (string, error) {
+	resp, err := s.GetBuildByID(ctx, buildID, apiKey)
+	if err != nil {
+		return "", err
+	}
+
+	build := resp.Build
+	branch := build.Branch
+	if branch == "" {
+		branch = build.Commit.Branch
+	}
+	appID := build.AppID
+	if appID == "" {
+		appID = resp.Application.ID
+	}
+
+	return s.StartBuild(ctx, apiKey, domain.CodemagicBuildRequest{
+		AppID:      appID,
+		WorkflowID: build.WorkflowID,
+		Branch:     branch,
+	})
+}
+
+// CancelBuild делает POST /builds/{id}/cancel.
+func (s *CodemagicService) CancelBuild(ctx context.Context, buildID, apiKey string) error {
+	url := fmt.Sprintf("%s/builds/%s/cancel", s.baseURL, buildID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("x-auth-token", apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	// 208 — билд уже завершён, отменять нечего
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAlreadyReported {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("codemagic %d: %s", resp.StatusCode, data)
+	}
+	return nil
+}
+
+// ListBuilds делает GET /builds?appId={id} и возвращает до limit
+// последних билдов приложения, новые первыми.
+func (s *CodemagicService) ListBuilds(
+	ctx context.Context,
+	appID, apiKey string,
+	limit int,
+) ([]domain.CodemagicBuild, error) {
+
+	query := url.Values{"appId": {appID}}
+	endpoint := fmt.Sprintf("%s/builds?%s", s.baseURL, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("x-auth-token", apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("codemagic %d: %s", resp.StatusCode, data)
+	}
+
+	var out struct {
+		Builds []domain.CodemagicBuild `json:"builds"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	sort.SliceStable(out.Builds, func(i, j int) bool {
+		return out.Builds[i].Index > out.Builds[j].Index
+	})
+	if len(out.Builds) > limit {
+		out.Builds = out.Builds[:limit]
+	}
+	return out.Builds, nil
+}
+
+// CheckAPIKey делает GET /apps, чтобы убедиться, что Codemagic принимает apiKey.
+func (s *CodemagicService) CheckAPIKey(ctx context.Context, apiKey string) error {
+	url := fmt.Sprintf("%s/apps", s.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("x-auth-token", apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrInvalidCodemagicAPIKey
+	default:
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("codemagic %d: %s", resp.StatusCode, data)
+	}
+}
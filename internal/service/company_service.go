@@ -2,9 +2,7 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
-	"fmt"
 
 	"victa/internal/domain"
 	"victa/internal/repository"
@@ -72,19 +70,15 @@ func (s *CompanyService) GetCompanyIntegrationByID(ctx context.Context, companyI
 	return s.integrationRepo.GetByID(ctx, companyID)
 }
 
-// CreateOrUpdateCompanyIntegration принимает JSON‑payload,
-// валидирует его и выполняет upsert настроек интеграций.
-func (s *CompanyService) CreateOrUpdateCompanyIntegration(
+// SaveCompanyIntegration выполняет upsert настроек интеграций компании.
+// Поля, которые не заданы в ci, очищаются: передавайте интеграцию целиком.
+func (s *CompanyService) SaveCompanyIntegration(
 	ctx context.Context,
 	companyID int64,
-	payload string,
+	ci *domain.CompanyIntegration,
 ) (*domain.CompanyIntegration, error) {
-	var ci domain.CompanyIntegration
-	if err := json.Unmarshal([]byte(payload), &ci); err != nil {
-		return nil, fmt.Errorf("invalid JSON: %w", err)
-	}
 	ci.CompanyID = companyID
-	return s.integrationRepo.CreateOrUpdate(ctx, &ci)
+	return s.integrationRepo.CreateOrUpdate(ctx, ci)
 }
 
 // CheckAdmin проверяет, что userID имеет роль admin в заданной компании.
@@ -99,3 +93,15 @@ func (s *CompanyService) CheckAdmin(ctx context.Context, userID, companyID int64
 	}
 	return nil
 }
+
+// CheckMember проверяет, что userID состоит в компании с любой ролью.
+// Возвращает ErrRelationNotFound, если не состоит.
+func (s *CompanyService) CheckMember(ctx context.Context, userID, companyID int64) error {
+	_, err := s.companyRepo.GetUserRole(ctx, userID, companyID)
+	return err
+}
+
+// GetNotificationBotTokens возвращает токены ботов уведомлений всех компаний.
+func (s *CompanyService) GetNotificationBotTokens(ctx context.Context) ([]string, error) {
+	return s.integrationRepo.GetNotificationBotTokens(ctx)
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"victa/internal/repository"
+)
+
+// deliveryKeyTTL — сколько помним обработанную доставку. GitLab и Bugsnag
+// повторяют запросы в течение нескольких часов, сутки — с запасом.
+const deliveryKeyTTL = 24 * time.Hour
+
+// DeliveryService отсекает повторные доставки вебхуков.
+type DeliveryService struct {
+	repo repository.WebhookDeliveryKeyRepository
+}
+
+// NewDeliveryService создаёт сервис дедупликации вебхуков.
+func NewDeliveryService(repo repository.WebhookDeliveryKeyRepository) *DeliveryService {
+	return &DeliveryService{repo: repo}
+}
+
+// Claim отмечает доставку как обработанную. false — это повтор.
+func (s *DeliveryService) Claim(ctx context.Context, companyID int64, provider, key string) (bool, error) {
+	return s.repo.Claim(ctx, companyID, provider, key, deliveryKeyTTL)
+}
+
+// Release забывает доставку, если её обработка не удалась.
+func (s *DeliveryService) Release(ctx context.Context, companyID int64, provider, key string) error {
+	return s.repo.Release(ctx, companyID, provider, key)
+}
+
+// PurgeExpired удаляет просроченные ключи.
+func (s *DeliveryService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx)
+}
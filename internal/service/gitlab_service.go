@@ -0,0 +1,240 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultGitlabBaseURL — адрес GitLab, если в интеграции не указан свой.
+const DefaultGitlabBaseURL = "https://gitlab.com"
+
+// ErrGitlabUserNotFound — на инстансе нет пользователя с таким логином.
+var ErrGitlabUserNotFound = errors.New("gitlab user not found")
+
+// GitlabService инкапсулирует работу с REST‑API GitLab (в т.ч. self‑hosted).
+type GitlabService struct {
+	client HTTPDoer
+}
+
+// NewGitlabService возвращает сервис с HTTP‑клиентом с таймаутом 10 s.
+func NewGitlabService() *GitlabService {
+	return &GitlabService{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// WithHTTPClient позволяет подменить клиента (юнит‑тест либо кастомные опции).
+func (s *GitlabService) WithHTTPClient(c HTTPDoer) *GitlabService {
+	s.client = c
+	return s
+}
+
+// ResolveBaseURL выбирает адрес инстанса: настроенный в интеграции, иначе
+// gitlab.com. Адрес из вебхука или карточки не используется: токен API
+// ушёл бы на любой хост, который подставит автор запроса.
+func (s *GitlabService) ResolveBaseURL(configured *string) string {
+	if configured != nil && strings.TrimSpace(*configured) != "" {
+		return strings.TrimRight(strings.TrimSpace(*configured), "/")
+	}
+	return DefaultGitlabBaseURL
+}
+
+// ansiEscape — ANSI‑раскраска и служебные маркеры секций GitLab Runner.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]|section_(start|end):[0-9]+:[A-Za-z0-9_.-]+\r?`)
+
+// GetJobTraceTail делает GET /projects/{id}/jobs/{job_id}/trace и возвращает
+// последние lines строк лога без ANSI‑последовательностей.
+func (s *GitlabService) GetJobTraceTail(
+	ctx context.Context,
+	baseURL, token string,
+	projectID, jobID int64,
+	lines int,
+) (string, error) {
+
+	reqURL := fmt.Sprintf("%s/api/v4/projects/%d/jobs/%d/trace",
+		strings.TrimRight(baseURL, "/"), projectID, jobID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("execute request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("gitlab %d: %s", resp.StatusCode, body)
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read trace: %w", err)
+	}
+
+	trace := ansiEscape.ReplaceAllString(string(raw), "")
+	all := strings.Split(strings.TrimRight(trace, "\r\n"), "\n")
+	if len(all) > lines {
+		all = all[len(all)-lines:]
+	}
+	for i, l := range all {
+		// прогресс‑бары перезаписывают строку через \r — оставляем последний кадр
+		if idx := strings.LastIndex(strings.TrimRight(l, "\r"), "\r"); idx >= 0 {
+			l = l[idx+1:]
+		}
+		all[i] = strings.TrimRight(l, "\r ")
+	}
+	return strings.Join(all, "\n"), nil
+}
+
+// UpdateIssueState делает PUT /projects/{id}/issues/{iid} со state_event
+// «close» или «reopen».
+func (s *GitlabService) UpdateIssueState(
+	ctx context.Context,
+	baseURL, token string,
+	projectID int64, issueIID int,
+	stateEvent string,
+) error {
+	return s.updateIssue(ctx, baseURL, token, projectID, issueIID, map[string]any{
+		"state_event": stateEvent,
+	})
+}
+
+// AssignIssue назначает задачу на пользователя GitLab с логином username.
+func (s *GitlabService) AssignIssue(
+	ctx context.Context,
+	baseURL, token string,
+	projectID int64, issueIID int,
+	username string,
+) error {
+	userID, err := s.findUserID(ctx, baseURL, token, username)
+	if err != nil {
+		return err
+	}
+	return s.updateIssue(ctx, baseURL, token, projectID, issueIID, map[string]any{
+		"assignee_ids": []int64{userID},
+	})
+}
+
+// CreateIssueNote делает POST /projects/{id}/issues/{iid}/notes.
+func (s *GitlabService) CreateIssueNote(
+	ctx context.Context,
+	baseURL, token string,
+	projectID int64, issueIID int,
+	body string,
+) error {
+	reqURL := fmt.Sprintf("%s/api/v4/projects/%d/issues/%d/notes",
+		strings.TrimRight(baseURL, "/"), projectID, issueIID)
+
+	resp, err := s.doJSON(ctx, http.MethodPost, reqURL, token, map[string]any{"body": body})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("gitlab %d: %s", resp.StatusCode, data)
+	}
+	return nil
+}
+
+func (s *GitlabService) updateIssue(
+	ctx context.Context,
+	baseURL, token string,
+	projectID int64, issueIID int,
+	body map[string]any,
+) error {
+	reqURL := fmt.Sprintf("%s/api/v4/projects/%d/issues/%d",
+		strings.TrimRight(baseURL, "/"), projectID, issueIID)
+
+	resp, err := s.doJSON(ctx, http.MethodPut, reqURL, token, body)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("gitlab %d: %s", resp.StatusCode, data)
+	}
+	return nil
+}
+
+// findUserID делает GET /users?username=… и возвращает ID пользователя.
+func (s *GitlabService) findUserID(ctx context.Context, baseURL, token, username string) (int64, error) {
+	reqURL := fmt.Sprintf("%s/api/v4/users?username=%s",
+		strings.TrimRight(baseURL, "/"), url.QueryEscape(username))
+
+	resp, err := s.doJSON(ctx, http.MethodGet, reqURL, token, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("gitlab %d: %s", resp.StatusCode, data)
+	}
+
+	var users []struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
+		return 0, fmt.Errorf("decode response: %w", err)
+	}
+	if len(users) == 0 {
+		return 0, ErrGitlabUserNotFound
+	}
+	return users[0].ID, nil
+}
+
+func (s *GitlabService) doJSON(ctx context.Context, method, reqURL, token string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reader)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+	return resp, nil
+}
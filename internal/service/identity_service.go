@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"victa/internal/domain"
+	"victa/internal/repository"
+)
+
+// ErrInvalidIdentity возвращается для пустой или некорректной учётной записи.
+var ErrInvalidIdentity = errors.New("invalid identity")
+
+// IdentityService связывает пользователей Victa с их учётными записями
+// в GitLab, git и Bugsnag, чтобы уведомления упоминали их в Telegram.
+type IdentityService struct {
+	repo repository.UserIdentityRepository
+}
+
+// NewIdentityService создаёт сервис учётных записей пользователей.
+func NewIdentityService(repo repository.UserIdentityRepository) *IdentityService {
+	return &IdentityService{repo: repo}
+}
+
+// GetAllByUserID возвращает связанные учётные записи пользователя.
+func (s *IdentityService) GetAllByUserID(ctx context.Context, userID int64) ([]domain.UserIdentity, error) {
+	return s.repo.GetAllByUserID(ctx, userID)
+}
+
+// Link проверяет и сохраняет учётную запись провайдера.
+func (s *IdentityService) Link(ctx context.Context, userID int64, provider, externalID string) (*domain.UserIdentity, error) {
+	externalID = domain.NormalizeIdentity(provider, externalID)
+	if externalID == "" {
+		return nil, ErrInvalidIdentity
+	}
+
+	switch provider {
+	case domain.IdentityProviderGitlab:
+		if strings.ContainsAny(externalID, " @") {
+			return nil, fmt.Errorf("%w: gitlab username expected", ErrInvalidIdentity)
+		}
+	case domain.IdentityProviderGitEmail, domain.IdentityProviderBugsnag:
+		if !strings.Contains(externalID, "@") || strings.Contains(externalID, " ") {
+			return nil, fmt.Errorf("%w: email expected", ErrInvalidIdentity)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown provider %q", ErrInvalidIdentity, provider)
+	}
+
+	return s.repo.Set(ctx, &domain.UserIdentity{UserID: userID, Provider: provider, ExternalID: externalID})
+}
+
+// Unlink отвязывает учётную запись провайдера.
+func (s *IdentityService) Unlink(ctx context.Context, userID int64, provider string) error {
+	return s.repo.Delete(ctx, userID, provider)
+}
+
+// ResolveTgIDs находит Telegram ID участников компании по учётным записям
+// провайдера. Ключи результата — нормализованные значения.
+func (s *IdentityService) ResolveTgIDs(
+	ctx context.Context,
+	companyID int64,
+	provider string,
+	externalIDs []string,
+) (map[string]string, error) {
+	ids := make([]string, 0, len(externalIDs))
+	for _, id := range externalIDs {
+		if id = domain.NormalizeIdentity(provider, id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return map[string]string{}, nil
+	}
+	return s.repo.GetTgIDs(ctx, companyID, provider, ids)
+}
+
+// GetExternalID возвращает учётную запись пользователя у провайдера
+// или пустую строку, если она не связана.
+func (s *IdentityService) GetExternalID(ctx context.Context, userID int64, provider string) (string, error) {
+	identities, err := s.repo.GetAllByUserID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	for _, ui := range identities {
+		if ui.Provider == provider {
+			return ui.ExternalID, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"hash/fnv"
+
+	"victa/internal/repository"
+)
+
+// LeaderService выбирает реплику, которая выполняет фоновую задачу в
+// одиночку (опрос Codemagic, магазинов, getUpdates ботов уведомлений).
+type LeaderService struct {
+	repo repository.LeaderLockRepository
+}
+
+// NewLeaderService создаёт сервис блокировок.
+func NewLeaderService(repo repository.LeaderLockRepository) *LeaderService {
+	return &LeaderService{repo: repo}
+}
+
+// TryAcquire берёт блокировку задачи name; nil — задачу ведёт другая реплика.
+func (s *LeaderService) TryAcquire(ctx context.Context, name string) (repository.LeaderLock, error) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("victa:" + name))
+	return s.repo.TryAcquire(ctx, int64(h.Sum64()))
+}
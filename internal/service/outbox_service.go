@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"victa/internal/domain"
+	"victa/internal/repository"
+)
+
+// OutboxService ставит уведомления в очередь и отдаёт их воркеру доставки.
+type OutboxService struct {
+	repo repository.NotificationOutboxRepository
+	wake chan struct{}
+}
+
+// NewOutboxService создаёт сервис очереди уведомлений.
+func NewOutboxService(repo repository.NotificationOutboxRepository) *OutboxService {
+	return &OutboxService{repo: repo, wake: make(chan struct{}, 1)}
+}
+
+// Enqueue сохраняет уведомление и будит воркер доставки.
+func (s *OutboxService) Enqueue(ctx context.Context, n *domain.Notification) error {
+	if _, err := s.repo.Enqueue(ctx, n); err != nil {
+		return err
+	}
+	s.signal()
+	return nil
+}
+
+// NewBatch создаёт пачку уведомлений, которая попадёт в очередь целиком.
+func (s *OutboxService) NewBatch() *OutboxBatch {
+	return &OutboxBatch{svc: s}
+}
+
+func (s *OutboxService) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// OutboxBatch копит уведомления одного события для нескольких чатов и
+// ставит их в очередь одной транзакцией в Commit. Так повтор вебхука после
+// сбоя не задвоит сообщение в чатах, куда оно уже ушло.
+type OutboxBatch struct {
+	svc   *OutboxService
+	items []*domain.Notification
+}
+
+// Enqueue добавляет уведомление в пачку; в очередь оно попадёт в Commit.
+func (b *OutboxBatch) Enqueue(_ context.Context, n *domain.Notification) error {
+	b.items = append(b.items, n)
+	return nil
+}
+
+// Commit ставит в очередь все накопленные уведомления или ни одного.
+func (b *OutboxBatch) Commit(ctx context.Context) error {
+	if len(b.items) == 0 {
+		return nil
+	}
+	if err := b.svc.repo.EnqueueAll(ctx, b.items); err != nil {
+		return err
+	}
+	b.items = nil
+	b.svc.signal()
+	return nil
+}
+
+// Wake сигналит о новых уведомлениях в очереди.
+func (s *OutboxService) Wake() <-chan struct{} {
+	return s.wake
+}
+
+// Claim захватывает пачку уведомлений, готовых к отправке.
+func (s *OutboxService) Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.Notification, error) {
+	return s.repo.Claim(ctx, limit, lease)
+}
+
+// Extend продлевает захват уведомлений, которые ещё обрабатываются.
+func (s *OutboxService) Extend(ctx context.Context, ids []int64, lease time.Duration) error {
+	return s.repo.Extend(ctx, ids, lease)
+}
+
+// Done удаляет доставленное уведомление из очереди.
+func (s *OutboxService) Done(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Retry откладывает уведомление до nextAttemptAt.
+func (s *OutboxService) Retry(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	return s.repo.Reschedule(ctx, id, nextAttemptAt, lastError)
+}
+
+// Fail переносит уведомление в dead‑letter.
+func (s *OutboxService) Fail(ctx context.Context, id int64, lastError string) error {
+	return s.repo.MoveToDeadLetter(ctx, id, lastError)
+}
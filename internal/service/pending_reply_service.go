@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"victa/internal/domain"
+	appErr "victa/internal/errors"
+	"victa/internal/repository"
+)
+
+// pendingReplyTTL — сколько ждём ответа на запрос комментария.
+const pendingReplyTTL = time.Hour
+
+// PendingReplyService хранит запросы комментариев под уведомлениями в БД,
+// чтобы ответ приняла любая реплика и после перезапуска.
+type PendingReplyService struct {
+	repo repository.PendingReplyRepository
+}
+
+// NewPendingReplyService создаёт сервис ожидания ответов.
+func NewPendingReplyService(repo repository.PendingReplyRepository) *PendingReplyService {
+	return &PendingReplyService{repo: repo}
+}
+
+// Add начинает ждать ответ на сообщение r.MessageID.
+func (s *PendingReplyService) Add(ctx context.Context, r *domain.PendingReply) error {
+	r.ExpiresAt = time.Now().UTC().Add(pendingReplyTTL)
+	return s.repo.Save(ctx, r)
+}
+
+// Get возвращает ожидание ответа на сообщение; nil — ответа не ждём.
+func (s *PendingReplyService) Get(ctx context.Context, chatID int64, messageID int) (*domain.PendingReply, error) {
+	r, err := s.repo.Get(ctx, chatID, messageID, time.Now().UTC())
+	if errors.Is(err, appErr.ErrPendingReplyNotFound) {
+		return nil, nil
+	}
+	return r, err
+}
+
+// Take забирает ожидание ответа; nil — его нет или его уже забрали.
+func (s *PendingReplyService) Take(ctx context.Context, chatID int64, messageID int) (*domain.PendingReply, error) {
+	r, err := s.repo.Take(ctx, chatID, messageID, time.Now().UTC())
+	if errors.Is(err, appErr.ErrPendingReplyNotFound) {
+		return nil, nil
+	}
+	return r, err
+}
+
+// PurgeExpired удаляет истёкшие ожидания.
+func (s *PendingReplyService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx, time.Now().UTC())
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"victa/internal/domain"
+	"victa/internal/repository"
+)
+
+// ErrInvalidRoutingRule возвращается, когда правило нельзя сохранить.
+var ErrInvalidRoutingRule = errors.New("invalid routing rule")
+
+// RoutingService хранит правила маршрутизации и выбирает по ним чаты.
+type RoutingService struct {
+	repo repository.RoutingRuleRepository
+}
+
+// NewRoutingService создаёт сервис правил маршрутизации.
+func NewRoutingService(repo repository.RoutingRuleRepository) *RoutingService {
+	return &RoutingService{repo: repo}
+}
+
+// GetAllByCompanyID возвращает правила компании в порядке проверки.
+func (s *RoutingService) GetAllByCompanyID(ctx context.Context, companyID int64) ([]domain.RoutingRule, error) {
+	return s.repo.GetAllByCompanyID(ctx, companyID)
+}
+
+// GetByID возвращает правило (или ErrRoutingRuleNotFound из repo).
+func (s *RoutingService) GetByID(ctx context.Context, ruleID int64) (*domain.RoutingRule, error) {
+	return s.repo.GetByID(ctx, ruleID)
+}
+
+// Create разбирает JSON‑payload, валидирует правило и добавляет его
+// в конец списка. Пустые строки в условиях означают «любое значение».
+func (s *RoutingService) Create(ctx context.Context, companyID int64, payload string) (*domain.RoutingRule, error) {
+	var rule domain.RoutingRule
+	if err := json.Unmarshal([]byte(payload), &rule); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	rule.CompanyID = companyID
+
+	for _, f := range []**string{
+		&rule.Source, &rule.Branch, &rule.Status, &rule.TriggerType, &rule.Severity, &rule.Label,
+	} {
+		if *f != nil && strings.TrimSpace(**f) == "" {
+			*f = nil
+		}
+	}
+	if rule.AppID != nil && *rule.AppID == 0 {
+		rule.AppID = nil
+	}
+
+	if err := s.validate(&rule); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, &rule)
+}
+
+// Delete удаляет правило.
+func (s *RoutingService) Delete(ctx context.Context, ruleID int64) error {
+	return s.repo.Delete(ctx, ruleID)
+}
+
+// MoveUp поднимает правило на одну позицию.
+func (s *RoutingService) MoveUp(ctx context.Context, ruleID int64) error {
+	return s.repo.MoveUp(ctx, ruleID)
+}
+
+// Resolve выбирает чаты для события по первому подходящему правилу.
+// Если ни одно правило не подошло, возвращается fallback (чат категории
+// из настроек компании). Пустой список без ошибки — событие заглушено.
+func (s *RoutingService) Resolve(
+	ctx context.Context,
+	companyID int64,
+	ev domain.RoutingEvent,
+	fallback *string,
+) ([]string, error) {
+	rules, err := s.repo.GetAllByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		if !rule.Matches(ev) {
+			continue
+		}
+		if rule.Action == domain.RoutingActionMute {
+			return []string{}, nil
+		}
+		return rule.ChatIDs, nil
+	}
+
+	if fallback == nil {
+		return nil, nil
+	}
+	return []string{*fallback}, nil
+}
+
+func (s *RoutingService) validate(rule *domain.RoutingRule) error {
+	if rule.Source != nil {
+		switch strings.ToLower(*rule.Source) {
+		case domain.RoutingSourceCodemagic, domain.RoutingSourceGitlab,
+			domain.RoutingSourceBugsnag, domain.RoutingSourceSentry:
+		default:
+			return fmt.Errorf("%w: unknown source %q", ErrInvalidRoutingRule, *rule.Source)
+		}
+	}
+	if rule.Branch != nil {
+		if _, err := path.Match(*rule.Branch, ""); err != nil {
+			return fmt.Errorf("%w: bad branch pattern: %v", ErrInvalidRoutingRule, err)
+		}
+	}
+
+	switch rule.Action {
+	case domain.RoutingActionMute:
+		rule.ChatIDs = []string{}
+	case domain.RoutingActionSend:
+		if len(rule.ChatIDs) == 0 {
+			return fmt.Errorf("%w: chat_ids is required for send", ErrInvalidRoutingRule)
+		}
+		for _, id := range rule.ChatIDs {
+			if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+				return fmt.Errorf("%w: bad chat id %q", ErrInvalidRoutingRule, id)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: action must be %q or %q", ErrInvalidRoutingRule, domain.RoutingActionSend, domain.RoutingActionMute)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"victa/internal/domain"
+)
+
+// ErrStoreAppNotFound — магазин не знает приложения с таким ID.
+var ErrStoreAppNotFound = errors.New("app not found in store")
+
+// AppStoreClient читает версию и отзывы через публичные iTunes Lookup
+// и RSS‑ленту отзывов. ID приложения — числовой, можно с регионом:
+// «ru/1234567890». Без региона берётся defaultCountry.
+type AppStoreClient struct {
+	client         HTTPDoer
+	baseURL        string
+	defaultCountry string
+}
+
+// NewAppStoreClient возвращает клиента с таймаутом 10 s и регионом ru.
+func NewAppStoreClient() *AppStoreClient {
+	return &AppStoreClient{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		baseURL:        "https://itunes.apple.com",
+		defaultCountry: "ru",
+	}
+}
+
+// WithHTTPClient позволяет подменить клиента (юнит‑тест либо кастомные опции).
+func (c *AppStoreClient) WithHTTPClient(d HTTPDoer) *AppStoreClient {
+	c.client = d
+	return c
+}
+
+// LatestVersion делает GET /lookup?id={id}.
+func (c *AppStoreClient) LatestVersion(ctx context.Context, storeAppID string) (string, error) {
+	country, id := c.split(storeAppID)
+
+	var out struct {
+		Results []struct {
+			Version string `json:"version"`
+		} `json:"results"`
+	}
+	url := fmt.Sprintf("%s/lookup?id=%s&country=%s", c.baseURL, id, country)
+	if err := c.get(ctx, url, &out); err != nil {
+		return "", err
+	}
+	if len(out.Results) == 0 {
+		return "", ErrStoreAppNotFound
+	}
+	return out.Results[0].Version, nil
+}
+
+// Reviews делает GET /{country}/rss/customerreviews/id={id}/sortby=mostrecent/json.
+func (c *AppStoreClient) Reviews(ctx context.Context, storeAppID string) ([]domain.StoreReview, error) {
+	country, id := c.split(storeAppID)
+
+	type label struct {
+		Label string `json:"label"`
+	}
+	type entry struct {
+		ID     label `json:"id"`
+		Author struct {
+			Name label `json:"name"`
+		} `json:"author"`
+		Rating  label `json:"im:rating"`
+		Version label `json:"im:version"`
+		Title   label `json:"title"`
+		Content label `json:"content"`
+		Updated label `json:"updated"`
+	}
+	var out struct {
+		Feed struct {
+			Entry json.RawMessage `json:"entry"`
+		} `json:"feed"`
+	}
+	url := fmt.Sprintf("%s/%s/rss/customerreviews/id=%s/sortby=mostrecent/json", c.baseURL, country, id)
+	if err := c.get(ctx, url, &out); err != nil {
+		return nil, err
+	}
+
+	// при одном отзыве лента отдаёт объект вместо массива
+	var entries []entry
+	if raw := out.Feed.Entry; len(raw) > 0 {
+		if raw[0] == '{' {
+			raw = append(append([]byte{'['}, raw...), ']')
+		}
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+	}
+
+	reviews := make([]domain.StoreReview, 0, len(entries))
+	for _, e := range entries {
+		rating, _ := strconv.Atoi(e.Rating.Label)
+		created, _ := time.Parse(time.RFC3339, e.Updated.Label)
+		reviews = append(reviews, domain.StoreReview{
+			ID:        e.ID.Label,
+			Author:    e.Author.Name.Label,
+			Rating:    rating,
+			Title:     e.Title.Label,
+			Text:      e.Content.Label,
+			Version:   e.Version.Label,
+			CreatedAt: created,
+		})
+	}
+	return reviews, nil
+}
+
+// split разбирает «ru/1234567890» на регион и ID.
+func (c *AppStoreClient) split(storeAppID string) (string, string) {
+	if country, id, ok := strings.Cut(storeAppID, "/"); ok {
+		return strings.ToLower(country), id
+	}
+	return c.defaultCountry, storeAppID
+}
+
+func (c *AppStoreClient) get(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("app store %d: %s", resp.StatusCode, data)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
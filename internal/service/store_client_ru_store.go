@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"victa/internal/domain"
+)
+
+// RuStoreClient читает версию и отзывы через публичный API витрины RuStore.
+// ID приложения — имя пакета (com.example.app).
+type RuStoreClient struct {
+	client  HTTPDoer
+	baseURL string
+	limit   int // сколько последних отзывов запрашивать
+}
+
+// NewRuStoreClient возвращает клиента с таймаутом 10 s.
+func NewRuStoreClient() *RuStoreClient {
+	return &RuStoreClient{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		baseURL: "https://backapi.rustore.ru",
+		limit:   20,
+	}
+}
+
+// WithHTTPClient позволяет подменить клиента (юнит‑тест либо кастомные опции).
+func (c *RuStoreClient) WithHTTPClient(d HTTPDoer) *RuStoreClient {
+	c.client = d
+	return c
+}
+
+type ruStoreAppInfo struct {
+	AppID       int64  `json:"appId"`
+	VersionName string `json:"versionName"`
+}
+
+// LatestVersion делает GET /applicationData/overallInfo/{package}.
+func (c *RuStoreClient) LatestVersion(ctx context.Context, storeAppID string) (string, error) {
+	info, err := c.appInfo(ctx, storeAppID)
+	if err != nil {
+		return "", err
+	}
+	return info.VersionName, nil
+}
+
+// Reviews делает GET /comment/comment?appId={id}. Отзывы привязаны
+// к внутреннему ID приложения, поэтому сначала запрашивается overallInfo.
+func (c *RuStoreClient) Reviews(ctx context.Context, storeAppID string) ([]domain.StoreReview, error) {
+	info, err := c.appInfo(ctx, storeAppID)
+	if err != nil {
+		return nil, err
+	}
+
+	var page struct {
+		Content []struct {
+			CommentID      int64  `json:"commentId"`
+			FirstName      string `json:"firstName"`
+			AppRating      int    `json:"appRating"`
+			CommentText    string `json:"commentText"`
+			CommentDate    string `json:"commentDate"`
+			AppVersionName string `json:"appVersionName"`
+		} `json:"content"`
+	}
+	url := fmt.Sprintf("%s/comment/comment?appId=%d&pageNumber=0&pageSize=%d", c.baseURL, info.AppID, c.limit)
+	if err := c.get(ctx, url, &page); err != nil {
+		return nil, err
+	}
+
+	reviews := make([]domain.StoreReview, 0, len(page.Content))
+	for _, cm := range page.Content {
+		created, _ := time.Parse(time.RFC3339, cm.CommentDate)
+		reviews = append(reviews, domain.StoreReview{
+			ID:        strconv.FormatInt(cm.CommentID, 10),
+			Author:    cm.FirstName,
+			Rating:    cm.AppRating,
+			Text:      cm.CommentText,
+			Version:   cm.AppVersionName,
+			CreatedAt: created,
+		})
+	}
+	return reviews, nil
+}
+
+func (c *RuStoreClient) appInfo(ctx context.Context, packageName string) (*ruStoreAppInfo, error) {
+	var info ruStoreAppInfo
+	url := fmt.Sprintf("%s/applicationData/overallInfo/%s", c.baseURL, packageName)
+	if err := c.get(ctx, url, &info); err != nil {
+		return nil, err
+	}
+	if info.AppID == 0 {
+		return nil, ErrStoreAppNotFound
+	}
+	return &info, nil
+}
+
+// get выполняет запрос и разбирает поле body из ответа {"code", "body"}.
+func (c *RuStoreClient) get(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrStoreAppNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("rustore %d: %s", resp.StatusCode, data)
+	}
+
+	var envelope struct {
+		Code string          `json:"code"`
+		Body json.RawMessage `json:"body"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if envelope.Code != "OK" {
+		return fmt.Errorf("rustore: %s", envelope.Code)
+	}
+	if err := json.Unmarshal(envelope.Body, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"victa/internal/domain"
+	"victa/internal/repository"
+)
+
+// StoreClient читает публичные данные приложения в одном магазине.
+type StoreClient interface {
+	// LatestVersion возвращает версию, опубликованную в магазине.
+	LatestVersion(ctx context.Context, storeAppID string) (string, error)
+	// Reviews возвращает последние отзывы, новые первыми.
+	Reviews(ctx context.Context, storeAppID string) ([]domain.StoreReview, error)
+}
+
+// StoreService хранит привязки приложений к магазинам и клиентов магазинов.
+type StoreService struct {
+	repo    repository.AppStoreStateRepository
+	clients map[string]StoreClient
+}
+
+// NewStoreService создаёт сервис с клиентами App Store и RuStore.
+// Play Store и AppGallery отдают данные только по ключам разработчика,
+// их клиенты подключаются через WithClient; до тех пор привязать
+// приложение к ним нельзя.
+func NewStoreService(repo repository.AppStoreStateRepository) *StoreService {
+	return &StoreService{
+		repo: repo,
+		clients: map[string]StoreClient{
+			domain.StoreSlugAppStore: NewAppStoreClient(),
+			domain.StoreSlugRuStore:  NewRuStoreClient(),
+		},
+	}
+}
+
+// WithClient подключает (или подменяет) клиента магазина slug.
+func (s *StoreService) WithClient(slug string, c StoreClient) *StoreService {
+	s.clients[slug] = c
+	return s
+}
+
+// Client возвращает клиента магазина; false — магазин не поддерживается.
+func (s *StoreService) Client(slug string) (StoreClient, bool) {
+	c, ok := s.clients[slug]
+	return c, ok
+}
+
+// SupportedSlugs возвращает магазины с подключённым клиентом в порядке вывода в боте.
+func (s *StoreService) SupportedSlugs() []string {
+	slugs := make([]string, 0, len(s.clients))
+	for _, slug := range domain.StoreSlugs {
+		if _, ok := s.clients[slug]; ok {
+			slugs = append(slugs, slug)
+		}
+	}
+	return slugs
+}
+
+// GetAll возвращает все приложения, привязанные к магазинам.
+func (s *StoreService) GetAll(ctx context.Context) ([]domain.AppStoreState, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// GetByAppID возвращает магазины приложения.
+func (s *StoreService) GetByAppID(ctx context.Context, appID int64) ([]domain.AppStoreState, error) {
+	return s.repo.GetByAppID(ctx, appID)
+}
+
+// SaveProgress запоминает увиденные версию и отзыв.
+func (s *StoreService) SaveProgress(ctx context.Context, st *domain.AppStoreState) error {
+	return s.repo.SaveProgress(ctx, st)
+}
+
+// UpdateAppStores принимает JSON {"<slug>": "<ID в магазине>"} и сохраняет
+// привязки приложения. Пустая строка отвязывает приложение от магазина;
+// привязать можно только к магазину, который монитор умеет опрашивать.
+func (s *StoreService) UpdateAppStores(ctx context.Context, appID int64, payload string) error {
+	var ids map[string]string
+	if err := json.Unmarshal([]byte(payload), &ids); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	for slug, id := range ids {
+		if !slices.Contains(domain.StoreSlugs, slug) {
+			return fmt.Errorf("unknown store %q", slug)
+		}
+		if _, ok := s.clients[slug]; !ok && strings.TrimSpace(id) != "" {
+			return fmt.Errorf("store %q is not supported yet, available: %s",
+				slug, strings.Join(s.SupportedSlugs(), ", "))
+		}
+	}
+
+	for slug, id := range ids {
+		id = strings.TrimSpace(id)
+		var err error
+		if id == "" {
+			err = s.repo.Delete(ctx, appID, slug)
+		} else {
+			err = s.repo.Upsert(ctx, appID, slug, id)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
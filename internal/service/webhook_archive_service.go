@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"victa/internal/domain"
+	"victa/internal/repository"
+)
+
+const (
+	// webhookArchiveTTL — сколько хранить сырые вебхуки.
+	webhookArchiveTTL = 14 * 24 * time.Hour
+	// webhookArchiveListLimit — сколько последних вебхуков показывать в боте.
+	webhookArchiveListLimit = 10
+)
+
+// WebhookArchiveService хранит входящие вебхуки для отладки и повтора.
+type WebhookArchiveService struct {
+	repo repository.WebhookDeliveryRepository
+}
+
+// NewWebhookArchiveService создаёт сервис архива вебхуков.
+func NewWebhookArchiveService(repo repository.WebhookDeliveryRepository) *WebhookArchiveService {
+	return &WebhookArchiveService{repo: repo}
+}
+
+// Record сохраняет вебхук вместе с итогом обработки.
+func (s *WebhookArchiveService) Record(ctx context.Context, d *domain.WebhookDelivery) error {
+	_, err := s.repo.Create(ctx, d)
+	return err
+}
+
+// GetByID возвращает вебхук (или ErrWebhookDeliveryNotFound из repo).
+func (s *WebhookArchiveService) GetByID(ctx context.Context, id int64) (*domain.WebhookDelivery, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// GetLastByCompanyID возвращает последние вебхуки компании.
+func (s *WebhookArchiveService) GetLastByCompanyID(ctx context.Context, companyID int64) ([]domain.WebhookDelivery, error) {
+	return s.repo.GetLastByCompanyID(ctx, companyID, webhookArchiveListLimit)
+}
+
+// PurgeExpired удаляет вебхуки старше срока хранения.
+func (s *WebhookArchiveService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteOlderThan(ctx, time.Now().Add(-webhookArchiveTTL))
+}
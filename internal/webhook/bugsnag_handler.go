@@ -1,7 +1,9 @@
 package webhook
 
 import (
+	"encoding/json"
 	"net/http"
+	"strings"
 	"victa/internal/domain"
 
 	"github.com/gin-gonic/gin"
@@ -15,31 +17,38 @@ import (
 type BugsnagWebhookHandler struct {
 	*webhook_common.BaseWebhook
 	companySvc *service.CompanyService
+	appSvc     *service.AppService
 }
 
 func NewBugsnagWebhookHandler(
 	factory *bot_common.BotFactory,
 	logger logger.Logger,
 	jwtSvc *service.JWTService,
+	outboxSvc *service.OutboxService,
+	deliverySvc *service.DeliveryService,
+	routingSvc *service.RoutingService,
+	identitySvc *service.IdentityService,
 	companySvc *service.CompanyService,
+	appSvc *service.AppService,
 ) *BugsnagWebhookHandler {
-	base := webhook_common.NewBaseWebhook(factory, logger, jwtSvc)
+	base := webhook_common.NewBaseWebhook(factory, logger, jwtSvc, outboxSvc, deliverySvc, routingSvc, identitySvc)
 	return &BugsnagWebhookHandler{
 		BaseWebhook: base,
 		companySvc:  companySvc,
+		appSvc:      appSvc,
 	}
 }
 func (h *BugsnagWebhookHandler) Handle(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	companyID, err := h.Authorize(c)
+	companyID, viaJWT, err := h.Identify(c)
 	if err != nil {
 		h.SendNewResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	var payload domain.BugsnagWebhook
-	if err := c.ShouldBindJSON(&payload); err != nil {
+	body, err := c.GetRawData()
+	if err != nil {
 		h.SendNewResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -54,22 +63,68 @@ func (h *BugsnagWebhookHandler) Handle(c *gin.Context) {
 		return
 	}
 
-	baseBot, err := h.BotFactory.GetBaseBot(
-		*integration.NotificationBotToken,
-		h.Logger,
-	)
+	err = h.Verify(viaJWT, integration.BugsnagWebhookSecret, func(secret string) error {
+		return webhook_common.VerifyHMACSHA256(body, secret, bugsnagSignature(c), "")
+	})
 	if err != nil {
 		h.SendNewResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	bot, err := notification_bot.NewBot(baseBot, *integration.ErrorsNotificationChatID)
+	var payload domain.BugsnagWebhook
+	if err := json.Unmarshal(body, &payload); err != nil {
+		h.SendNewResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if h.SkipDuplicate(c, companyID, "bugsnag", bugsnagDeliveryKey(payload)) {
+		return
+	}
+
+	app, err := h.appSvc.FindByBugsnagProjectID(ctx, companyID, payload.Project.ID)
 	if err != nil {
-		h.SendNewResponse(c, http.StatusUnauthorized, err.Error())
+		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	integration = integration.ForApp(app)
+
+	ev := domain.RoutingEvent{
+		Source:      domain.RoutingSourceBugsnag,
+		Status:      payload.Error.Status,
+		TriggerType: payload.Trigger.Type,
+		Severity:    payload.Error.Severity,
+	}
+	if app != nil {
+		ev.AppID = app.AppID
+	}
+
+	bots, err := h.NewNotificationBots(ctx, integration, ev, integration.ErrorsNotificationChatID)
+	if err != nil {
+		h.SendBotError(c, err)
 		return
 	}
 
-	bot.SendBugsnagNotification(payload)
+	err = h.Broadcast(ctx, bots, func(bot *notification_bot.Bot) error {
+		return bot.SendBugsnagNotification(ctx, payload, integration.BugsnagAPIToken != nil)
+	})
+	if err != nil {
+		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
 
 	h.SendNewResponse(c, http.StatusOK, "OK")
 }
+
+// bugsnagSignature достаёт hex‑подпись HMAC‑SHA256 тела запроса;
+// префикс «sha256=» необязателен.
+func bugsnagSignature(c *gin.Context) string {
+	return strings.TrimPrefix(c.GetHeader("X-Bugsnag-Signature"), "sha256=")
+}
+
+// bugsnagDeliveryKey — ошибка + событие; Bugsnag повторяет доставку с тем же телом.
+func bugsnagDeliveryKey(w domain.BugsnagWebhook) string {
+	if w.Error.ErrorID == "" || w.Error.ID == "" {
+		return ""
+	}
+	return w.Error.ErrorID + ":" + w.Error.ID
+}
@@ -2,56 +2,90 @@ package webhook
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"net/http"
-	"strings"
 	"time"
-	"victa/internal/bot/notification_bot"
+	"victa/internal/domain"
 	"victa/internal/logger"
 	"victa/internal/webhook/webhook_common"
 
 	"victa/internal/bot/bot_common"
 	"victa/internal/service"
+	"victa/internal/worker"
 )
 
 type CodemagicWebhookHandler struct {
 	*webhook_common.BaseWebhook
 	companySvc   *service.CompanyService
+	appSvc       *service.AppService
 	codemagicSvc *service.CodemagicService
+	progress     *worker.BuildProgress
 }
 
 func NewCodemagicWebhookHandler(
 	factory *bot_common.BotFactory,
 	logger logger.Logger,
 	jwtSvc *service.JWTService,
+	outboxSvc *service.OutboxService,
+	deliverySvc *service.DeliveryService,
+	routingSvc *service.RoutingService,
+	identitySvc *service.IdentityService,
 	companySvc *service.CompanyService,
+	appSvc *service.AppService,
 	codemagicSvc *service.CodemagicService,
+	progress *worker.BuildProgress,
 ) *CodemagicWebhookHandler {
-	base := webhook_common.NewBaseWebhook(factory, logger, jwtSvc)
+	base := webhook_common.NewBaseWebhook(factory, logger, jwtSvc, outboxSvc, deliverySvc, routingSvc, identitySvc)
 	return &CodemagicWebhookHandler{
 		BaseWebhook:  base,
 		codemagicSvc: codemagicSvc,
 		companySvc:   companySvc,
+		appSvc:       appSvc,
+		progress:     progress,
 	}
 }
 
 func (h *CodemagicWebhookHandler) Handle(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	companyID, err := h.Authorize(c)
+	// у Codemagic нет подписи запроса: без секрета Verify пропустит только JWT
+	companyID, viaJWT, err := h.Identify(c)
+	if err == nil {
+		err = h.Verify(viaJWT, nil, nil)
+	}
 	if err != nil {
 		h.SendNewResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	var payload struct {
-		BuildID string `json:"build_id"`
+	body, err := c.GetRawData()
+	if err != nil {
+		h.SendNewResponse(c, http.StatusBadRequest, err.Error())
+		return
 	}
-	if err := c.ShouldBindJSON(&payload); err != nil {
+
+	var payload domain.CodemagicWebhook
+	if err := json.Unmarshal(body, &payload); err != nil {
 		h.SendNewResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	buildID := payload.BuildID
+	if payload.Build != nil && payload.Build.ID != "" {
+		buildID = payload.Build.ID
+	}
+	if buildID == "" {
+		h.SendNewResponse(c, http.StatusBadRequest, "build id is missing")
+		return
+	}
+
+	// Codemagic не присылает ID доставки, поэтому повтор узнаём по телу:
+	// ключ по билду и статусу склеил бы разные события с одним статусом
+	if h.SkipDuplicate(c, companyID, "codemagic", webhook_common.BodyKey(body)) {
+		return
+	}
+
 	integration, err := h.companySvc.GetCompanyIntegrationByID(ctx, companyID) // +ctx
 	if err != nil {
 		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
@@ -65,36 +99,90 @@ func (h *CodemagicWebhookHandler) Handle(c *gin.Context) {
 	cmCtx, cancelCM := context.WithTimeout(ctx, 5*time.Second)
 	defer cancelCM()
 
-	build, err := h.codemagicSvc.GetBuildByID(cmCtx, payload.BuildID, *integration.CodemagicAPIKey) // +ctx
-	if err != nil {
-		h.SendNewResponse(c, http.StatusBadGateway, err.Error())
-		return
-	}
+	build := h.buildFromPayload(payload)
+	if !h.isComplete(build) {
+		if integration.CodemagicAPIKey == nil {
+			h.SendNewResponse(c, http.StatusBadRequest, "codemagic api key is not configured")
+			return
+		}
 
-	for i, art := range build.Build.Artefacts {
-		if strings.EqualFold(art.Type, "apk") {
-			url, err := h.codemagicSvc.GetArtifactPublicURL(cmCtx, art.Path, *integration.CodemagicAPIKey)
-			if err != nil {
-				h.Logger.Warn("codemagic public URL: %v", err)
-				break
-			}
-			build.Build.Artefacts[i].PublicURL = url
-			break
+		fetched, err := h.codemagicSvc.GetBuildByID(cmCtx, buildID, *integration.CodemagicAPIKey) // +ctx
+		if err != nil {
+			h.SendNewResponse(c, http.StatusBadGateway, err.Error())
+			return
 		}
+		build = fetched
 	}
 
-	baseBot, err := h.BotFactory.GetBaseBot(*integration.NotificationBotToken, h.Logger)
+	app, err := h.appSvc.FindByCodemagicAppID(ctx, companyID, build.Application.ID)
 	if err != nil {
-		h.SendNewResponse(c, http.StatusUnauthorized, err.Error())
+		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
-	bot, err := notification_bot.NewBot(baseBot, *integration.DeployNotificationChatID)
+	integration = integration.ForApp(app)
+
+	branch := build.Build.Commit.Branch
+	if branch == "" {
+		branch = build.Build.Branch
+	}
+	ev := domain.RoutingEvent{
+		Source: domain.RoutingSourceCodemagic,
+		Branch: branch,
+		Status: build.Build.Status,
+	}
+	if app != nil {
+		ev.AppID = app.AppID
+	}
+
+	bots, err := h.NewNotificationBots(ctx, integration, ev, integration.DeployNotificationChatID)
 	if err != nil {
-		h.SendNewResponse(c, http.StatusUnauthorized, err.Error())
+		h.SendBotError(c, err)
 		return
 	}
 
-	bot.SendDeployNotification(build.Application, build.Build)
+	if err := h.progress.Publish(ctx, integration, bots, build); err != nil {
+		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
 
 	h.SendNewResponse(c, http.StatusOK, "OK")
 }
+
+// buildFromPayload собирает ответ в формате API из нативного вебхука.
+// Для старого формата {"build_id"} возвращает nil.
+func (h *CodemagicWebhookHandler) buildFromPayload(payload domain.CodemagicWebhook) *domain.CodemagicBuildResponse {
+	if payload.Build == nil {
+		return nil
+	}
+
+	out := &domain.CodemagicBuildResponse{Build: *payload.Build}
+	switch {
+	case payload.App != nil:
+		out.Application = *payload.App
+	case payload.Application != nil:
+		out.Application = *payload.Application
+	}
+	if out.Application.ID == "" {
+		out.Application.ID = out.Build.AppID
+	}
+	return out
+}
+
+// isComplete проверяет, хватает ли данных вебхука для уведомления
+// без похода в API Codemagic.
+func (h *CodemagicWebhookHandler) isComplete(build *domain.CodemagicBuildResponse) bool {
+	if build == nil {
+		return false
+	}
+	if build.Build.Status == "" || build.Application.AppName == "" || build.Application.ID == "" {
+		return false
+	}
+	if build.Build.IsFinished() {
+		for _, art := range build.Build.Artefacts {
+			if art.ArtifactPath() == "" {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"victa/internal/bot/bot_common"
+	"victa/internal/domain"
+	"victa/internal/logger"
+	"victa/internal/service"
+	"victa/internal/webhook/webhook_common"
+)
+
+// GithubWebhookHandler принимает вебхуки GitHub и переводит их в модели
+// GitLab, чтобы уведомления в чатах выглядели одинаково.
+type GithubWebhookHandler struct {
+	*webhook_common.BaseWebhook
+	companySvc *service.CompanyService
+	appSvc     *service.AppService
+	gitlab     *GitlabIssueWebhookHandler
+}
+
+func NewGithubWebhookHandler(
+	factory *bot_common.BotFactory,
+	logger logger.Logger,
+	jwtSvc *service.JWTService,
+	outboxSvc *service.OutboxService,
+	deliverySvc *service.DeliveryService,
+	routingSvc *service.RoutingService,
+	identitySvc *service.IdentityService,
+	companySvc *service.CompanyService,
+	appSvc *service.AppService,
+) *GithubWebhookHandler {
+	base := webhook_common.NewBaseWebhook(factory, logger, jwtSvc, outboxSvc, deliverySvc, routingSvc, identitySvc)
+	return &GithubWebhookHandler{
+		BaseWebhook: base,
+		companySvc:  companySvc,
+		appSvc:      appSvc,
+		gitlab:      NewGitlabWebhookHandler(factory, logger, jwtSvc, outboxSvc, deliverySvc, routingSvc, identitySvc, companySvc, appSvc),
+	}
+}
+
+func (h *GithubWebhookHandler) Handle(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	companyID, viaJWT, err := h.Identify(c)
+	if err != nil {
+		h.SendNewResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		h.SendNewResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	integration, err := h.companySvc.GetCompanyIntegrationByID(ctx, companyID)
+	if err != nil {
+		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if integration == nil {
+		h.SendNewResponse(c, http.StatusBadRequest, "company not found")
+		return
+	}
+
+	err = h.Verify(viaJWT, integration.GithubWebhookSecret, func(secret string) error {
+		return webhook_common.VerifyHMACSHA256(body, secret, c.GetHeader("X-Hub-Signature-256"), "sha256=")
+	})
+	if err != nil {
+		h.SendNewResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	event := c.GetHeader("X-GitHub-Event")
+	switch event {
+	case "issues", "issue_comment", "pull_request", "workflow_run", "release":
+	case "ping":
+		h.SendNewResponse(c, http.StatusOK, "pong")
+		return
+	default:
+		h.SendNewResponse(c, http.StatusOK, "OK, but ignored")
+		return
+	}
+
+	if h.SkipDuplicate(c, companyID, "github", c.GetHeader("X-GitHub-Delivery")) {
+		return
+	}
+
+	var payload domain.GithubWebhook
+	if err := json.Unmarshal(body, &payload); err != nil {
+		h.SendNewResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var repoKey string
+	if payload.Repository.ID != 0 {
+		repoKey = strconv.FormatInt(payload.Repository.ID, 10)
+	}
+	app, err := h.appSvc.FindByGithubRepository(ctx, companyID, repoKey, payload.Repository.FullName)
+	if err != nil {
+		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	integration = integration.ForApp(app)
+
+	// GitHub приводится к моделям GitLab и маршрутизируется как gitlab
+	ev := domain.RoutingEvent{Source: domain.RoutingSourceGitlab}
+	if app != nil {
+		ev.AppID = app.AppID
+	}
+
+	switch event {
+	case "issues":
+		mapped, ok := h.mapIssue(payload)
+		if !ok {
+			h.SendNewResponse(c, http.StatusOK, "OK, but ignored")
+			return
+		}
+		h.gitlab.handleIssue(c, integration, ev, mapped)
+	case "issue_comment":
+		mapped, ok := h.mapIssueComment(payload)
+		if !ok {
+			h.SendNewResponse(c, http.StatusOK, "OK, but ignored")
+			return
+		}
+		h.gitlab.handleIssue(c, integration, ev, mapped)
+	case "pull_request":
+		mapped, ok := h.mapPullRequest(payload)
+		if !ok {
+			h.SendNewResponse(c, http.StatusOK, "OK, but ignored")
+			return
+		}
+		h.gitlab.handleIssue(c, integration, ev, mapped)
+	case "workflow_run":
+		mapped, ok := h.mapWorkflowRun(payload)
+		if !ok {
+			h.SendNewResponse(c, http.StatusOK, "OK, but ignored")
+			return
+		}
+		h.gitlab.handlePipeline(c, integration, ev, mapped)
+	case "release":
+		mapped, ok := h.mapRelease(payload)
+		if !ok {
+			h.SendNewResponse(c, http.StatusOK, "OK, but ignored")
+			return
+		}
+		h.gitlab.handleRelease(c, integration, ev, mapped)
+	}
+}
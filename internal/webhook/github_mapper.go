@@ -0,0 +1,224 @@
+package webhook
+
+import (
+	"strings"
+
+	"victa/internal/domain"
+)
+
+// githubIssueActions — действия issues/pull_request → action GitLab.
+var githubIssueActions = map[string]string{
+	"opened":           "open",
+	"reopened":         "reopen",
+	"closed":           "close",
+	"edited":           "update",
+	"assigned":         "update",
+	"ready_for_review": "update",
+	"review_requested": "update",
+}
+
+var githubCommentActions = map[string]string{
+	"created": "create",
+	"edited":  "update",
+}
+
+// githubConclusions — workflow_run.conclusion → статус пайплайна GitLab.
+var githubConclusions = map[string]string{
+	"success":   "success",
+	"failure":   "failed",
+	"timed_out": "failed",
+	"cancelled": "canceled",
+	"skipped":   "skipped",
+}
+
+var githubReleaseActions = map[string]string{
+	"published": "create",
+	"edited":    "update",
+	"deleted":   "delete",
+}
+
+// githubMergeableStates — pull_request.mergeable_state → merge_status GitLab.
+var githubMergeableStates = map[string]string{
+	"clean":    "can_be_merged",
+	"unstable": "can_be_merged",
+	"dirty":    "cannot_be_merged",
+	"blocked":  "not_approved",
+	"behind":   "need_rebase",
+	"draft":    "draft_status",
+}
+
+func (h *GithubWebhookHandler) mapIssue(w domain.GithubWebhook) (domain.GitlabWebhook, bool) {
+	action, ok := githubIssueActions[w.Action]
+	if !ok || w.Issue == nil {
+		return domain.GitlabWebhook{}, false
+	}
+
+	out := h.newGitlabWebhook(w, "issue")
+	out.ObjectAttributes = h.issueAttributes(*w.Issue)
+	out.ObjectAttributes.Action = action
+	out.Assignees = h.gitlabUsers(w.Issue.Assignees)
+	for _, label := range w.Issue.Labels {
+		out.Labels = append(out.Labels, domain.GitlabLabel{Title: label.Name})
+	}
+	return out, true
+}
+
+func (h *GithubWebhookHandler) mapIssueComment(w domain.GithubWebhook) (domain.GitlabWebhook, bool) {
+	action, ok := githubCommentActions[w.Action]
+	if !ok || w.Issue == nil || w.Comment == nil {
+		return domain.GitlabWebhook{}, false
+	}
+
+	out := h.newGitlabWebhook(w, "note")
+	out.ObjectAttributes = domain.Attributes{
+		Action:       action,
+		URL:          w.Comment.HTMLURL,
+		Note:         w.Comment.Body,
+		Description:  w.Comment.Body,
+		NoteableType: "Issue",
+	}
+
+	// комментарии к PR в GitHub приходят как issue_comment
+	if w.Issue.PullRequest != nil {
+		out.ObjectAttributes.NoteableType = "MergeRequest"
+		out.MergeRequest = h.issueAttributes(*w.Issue)
+	} else {
+		out.Issue = h.issueAttributes(*w.Issue)
+	}
+	return out, true
+}
+
+func (h *GithubWebhookHandler) mapPullRequest(w domain.GithubWebhook) (domain.GitlabWebhook, bool) {
+	action, ok := githubIssueActions[w.Action]
+	if !ok || w.PullRequest == nil {
+		return domain.GitlabWebhook{}, false
+	}
+
+	pr := w.PullRequest
+	if w.Action == "closed" && pr.Merged {
+		action = "merge"
+	}
+
+	state := "opened"
+	switch {
+	case pr.Merged:
+		state = "merged"
+	case pr.State == "closed":
+		state = "closed"
+	}
+
+	out := h.newGitlabWebhook(w, "merge_request")
+	out.ObjectAttributes = domain.Attributes{
+		IID:          pr.Number,
+		Title:        pr.Title,
+		Description:  pr.Body,
+		URL:          pr.HTMLURL,
+		Action:       action,
+		State:        state,
+		SourceBranch: pr.Head.Ref,
+		TargetBranch: pr.Base.Ref,
+		MergeStatus:  githubMergeableStates[pr.MergeableState],
+		Draft:        pr.Draft,
+	}
+	out.Assignees = h.gitlabUsers(pr.Assignees)
+	out.Reviewers = h.gitlabUsers(pr.RequestedReviewers)
+	return out, true
+}
+
+// mapWorkflowRun переводит только завершённые прогоны.
+func (h *GithubWebhookHandler) mapWorkflowRun(w domain.GithubWebhook) (domain.GitlabPipelineWebhook, bool) {
+	if w.Action != "completed" || w.WorkflowRun == nil {
+		return domain.GitlabPipelineWebhook{}, false
+	}
+	run := w.WorkflowRun
+
+	status, ok := githubConclusions[run.Conclusion]
+	if !ok {
+		status = run.Conclusion
+	}
+
+	var out domain.GitlabPipelineWebhook
+	out.ObjectKind = "pipeline"
+	out.ObjectAttributes.ID = run.RunNumber
+	out.ObjectAttributes.Ref = run.HeadBranch
+	out.ObjectAttributes.SHA = run.HeadSHA
+	out.ObjectAttributes.Source = run.Event
+	out.ObjectAttributes.Status = status
+	out.ObjectAttributes.URL = run.HTMLURL
+	if !run.RunStartedAt.IsZero() && run.UpdatedAt.After(run.RunStartedAt) {
+		d := run.UpdatedAt.Sub(run.RunStartedAt).Seconds()
+		out.ObjectAttributes.Duration = &d
+	}
+
+	out.Project.ID = w.Repository.ID
+	out.Project.Name = w.Repository.Name + " · " + run.Name
+	out.Project.WebURL = w.Repository.HTMLURL
+	out.Project.PathWithNamespace = w.Repository.FullName
+
+	out.Commit.ID = run.HeadSHA
+	out.Commit.Message = run.HeadCommit.Message
+	out.Commit.Title = strings.SplitN(run.HeadCommit.Message, "\n", 2)[0]
+	out.Commit.Author.Name = run.HeadCommit.Author.Name
+	out.Commit.Author.Email = run.HeadCommit.Author.Email
+
+	out.User = domain.GitlabUser{Name: run.Actor.Login, Username: run.Actor.Login}
+	return out, true
+}
+
+func (h *GithubWebhookHandler) mapRelease(w domain.GithubWebhook) (domain.GitlabReleaseWebhook, bool) {
+	action, ok := githubReleaseActions[w.Action]
+	if !ok || w.Release == nil {
+		return domain.GitlabReleaseWebhook{}, false
+	}
+	rel := w.Release
+
+	var out domain.GitlabReleaseWebhook
+	out.ObjectKind = "release"
+	out.Action = action
+	out.Name = rel.Name
+	out.Tag = rel.TagName
+	out.Description = rel.Body
+	out.URL = rel.HTMLURL
+	out.Project.ID = w.Repository.ID
+	out.Project.Name = w.Repository.Name
+	out.Project.WebURL = w.Repository.HTMLURL
+	for _, a := range rel.Assets {
+		out.Assets.Links = append(out.Assets.Links, domain.GitlabReleaseLink{
+			Name: a.Name,
+			URL:  a.BrowserDownloadURL,
+		})
+	}
+	return out, true
+}
+
+func (h *GithubWebhookHandler) newGitlabWebhook(w domain.GithubWebhook, kind string) domain.GitlabWebhook {
+	var out domain.GitlabWebhook
+	out.ObjectKind = kind
+	out.User = domain.GitlabUser{Name: w.Sender.Login, Username: w.Sender.Login}
+	out.Project.Name = w.Repository.Name
+	out.Project.Namespace = strings.TrimSuffix(w.Repository.FullName, "/"+w.Repository.Name)
+	out.Project.Homepage = w.Repository.HTMLURL
+	return out
+}
+
+func (h *GithubWebhookHandler) issueAttributes(issue domain.GithubIssue) domain.Attributes {
+	state := "opened"
+	if issue.State == "closed" {
+		state = "closed"
+	}
+	return domain.Attributes{
+		IID:         issue.Number,
+		Title:       issue.Title,
+		Description: issue.Body,
+		URL:         issue.HTMLURL,
+		State:       state,
+	}
+}
+
+func (h *GithubWebhookHandler) gitlabUsers(users []domain.GithubUser) []domain.GitlabUser {
+	out := make([]domain.GitlabUser, 0, len(users))
+	for _, u := range users {
+		out = append(out, domain.GitlabUser{Name: u.Login, Username: u.Login})
+	}
+	return out
+}
@@ -1,8 +1,10 @@
 package webhook
 
 import (
+	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"strconv"
 	"victa/internal/bot/bot_common"
 	"victa/internal/bot/notification_bot"
 	"victa/internal/domain"
@@ -14,36 +16,154 @@ import (
 type GitlabIssueWebhookHandler struct {
 	*webhook_common.BaseWebhook
 	companySvc *service.CompanyService
+	appSvc     *service.AppService
 }
 
 func NewGitlabWebhookHandler(
 	factory *bot_common.BotFactory,
 	logger logger.Logger,
 	jwtSvc *service.JWTService,
+	outboxSvc *service.OutboxService,
+	deliverySvc *service.DeliveryService,
+	routingSvc *service.RoutingService,
+	identitySvc *service.IdentityService,
 	companySvc *service.CompanyService,
+	appSvc *service.AppService,
 ) *GitlabIssueWebhookHandler {
-	base := webhook_common.NewBaseWebhook(factory, logger, jwtSvc)
+	base := webhook_common.NewBaseWebhook(factory, logger, jwtSvc, outboxSvc, deliverySvc, routingSvc, identitySvc)
 	return &GitlabIssueWebhookHandler{
 		BaseWebhook: base,
 		companySvc:  companySvc,
+		appSvc:      appSvc,
 	}
 }
 
 func (h *GitlabIssueWebhookHandler) Handle(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	companyID, err := h.Authorize(c)
+	companyID, viaJWT, err := h.Identify(c)
 	if err != nil {
 		h.SendNewResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	var payload domain.GitlabWebhook
-	if err := c.ShouldBindJSON(&payload); err != nil {
+	body, err := c.GetRawData()
+	if err != nil {
+		h.SendNewResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	integration, err := h.companySvc.GetCompanyIntegrationByID(ctx, companyID)
+	if err != nil {
+		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if integration == nil {
+		h.SendNewResponse(c, http.StatusBadRequest, "company not found")
+		return
+	}
+
+	err = h.Verify(viaJWT, integration.GitlabWebhookToken, func(secret string) error {
+		return webhook_common.VerifySecretToken(c.GetHeader("X-Gitlab-Token"), secret)
+	})
+	if err != nil {
+		h.SendNewResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var kind struct {
+		ObjectKind string `json:"object_kind"`
+		ProjectID  int64  `json:"project_id"`
+		Project    struct {
+			ID                int64  `json:"id"`
+			PathWithNamespace string `json:"path_with_namespace"`
+		} `json:"project"`
+	}
+	if err := json.Unmarshal(body, &kind); err != nil {
 		h.SendNewResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	switch kind.ObjectKind {
+	case "issue", "note", "merge_request", "pipeline", "build", "release":
+	default:
+		h.SendNewResponse(c, http.StatusOK, "OK, but ignored")
+		return
+	}
+
+	// GitLab повторяет доставку с тем же UUID, а иногда шлёт одно и то же
+	// обновление дважды под разными — второе ловим по содержимому.
+	if h.SkipDuplicate(c, companyID, "gitlab", c.GetHeader("X-Gitlab-Event-UUID"), webhook_common.BodyKey(body)) {
+		return
+	}
+
+	// job‑события несут ID проекта только на верхнем уровне
+	projectID := kind.Project.ID
+	if projectID == 0 {
+		projectID = kind.ProjectID
+	}
+	var projectKey string
+	if projectID != 0 {
+		projectKey = strconv.FormatInt(projectID, 10)
+	}
+	app, err := h.appSvc.FindByGitlabProject(ctx, companyID, projectKey, kind.Project.PathWithNamespace)
+	if err != nil {
+		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	integration = integration.ForApp(app)
+
+	ev := domain.RoutingEvent{Source: domain.RoutingSourceGitlab}
+	if app != nil {
+		ev.AppID = app.AppID
+	}
+
+	switch kind.ObjectKind {
+	case "pipeline":
+		var payload domain.GitlabPipelineWebhook
+		if err := json.Unmarshal(body, &payload); err != nil {
+			h.SendNewResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.handlePipeline(c, integration, ev, payload)
+	case "build":
+		var payload domain.GitlabJobWebhook
+		if err := json.Unmarshal(body, &payload); err != nil {
+			h.SendNewResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.handleJob(c, integration, ev, payload)
+	case "release":
+		var payload domain.GitlabReleaseWebhook
+		if err := json.Unmarshal(body, &payload); err != nil {
+			h.SendNewResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.handleRelease(c, integration, ev, payload)
+	default:
+		var payload domain.GitlabWebhook
+		if err := json.Unmarshal(body, &payload); err != nil {
+			h.SendNewResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.handleIssue(c, integration, ev, payload)
+	}
+}
+
+func (h *GitlabIssueWebhookHandler) handleIssue(
+	c *gin.Context,
+	integration *domain.CompanyIntegration,
+	ev domain.RoutingEvent,
+	payload domain.GitlabWebhook,
+) {
+	if payload.ObjectKind == "note" &&
+		payload.ObjectAttributes.NoteableType != "Issue" &&
+		payload.ObjectAttributes.NoteableType != "MergeRequest" {
+
+		h.SendNewResponse(c, http.StatusOK, "OK, but ignored")
+		return
+	}
+
 	if payload.ObjectKind == "issue" &&
 		payload.ObjectAttributes.Action == "update" &&
 		payload.Changes.ClosedAt != nil &&
@@ -54,28 +174,150 @@ func (h *GitlabIssueWebhookHandler) Handle(c *gin.Context) {
 		return
 	}
 
-	integration, err := h.companySvc.GetCompanyIntegrationByID(ctx, companyID)
+	ev.Branch = payload.ObjectAttributes.TargetBranch
+	ev.Status = payload.ObjectAttributes.State
+	for _, label := range payload.Labels {
+		ev.Labels = append(ev.Labels, label.Title)
+	}
+
+	bots, err := h.NewNotificationBots(c.Request.Context(), integration, ev, h.resolveChatID(payload, integration))
+	if err != nil {
+		h.SendBotError(c, err)
+		return
+	}
+
+	// кнопки работают через API GitLab: нужен токен и ID проекта,
+	// которого нет у задач, пришедших из GitHub
+	withActions := integration.GitlabAPIToken != nil && payload.Project.ID != 0
+
+	err = h.Broadcast(c.Request.Context(), bots, func(bot *notification_bot.Bot) error {
+		if h.isMergeRequestEvent(payload) {
+			return bot.SendMergeRequestNotification(c.Request.Context(), payload)
+		}
+		return bot.SendIssueNotification(c.Request.Context(), payload, withActions)
+	})
 	if err != nil {
 		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
-	if integration == nil {
-		h.SendNewResponse(c, http.StatusBadRequest, "company not found")
+
+	h.SendNewResponse(c, http.StatusOK, "OK")
+}
+
+// handlePipeline уведомляет только о завершённых пайплайнах:
+// промежуточные статусы (pending/running) приходят на каждый шаг.
+// Логи упавших задач уходят карточками задач (handleJob), а не в сводку,
+// чтобы одна ошибка CI не приходила в чат дважды.
+func (h *GitlabIssueWebhookHandler) handlePipeline(
+	c *gin.Context,
+	integration *domain.CompanyIntegration,
+	ev domain.RoutingEvent,
+	payload domain.GitlabPipelineWebhook,
+) {
+	switch payload.ObjectAttributes.Status {
+	case "success", "failed", "canceled":
+	default:
+		h.SendNewResponse(c, http.StatusOK, "OK, but ignored")
 		return
 	}
 
-	baseBot, err := h.BotFactory.GetBaseBot(*integration.NotificationBotToken, h.Logger)
+	ev.Branch = payload.ObjectAttributes.Ref
+	ev.Status = payload.ObjectAttributes.Status
+
+	bots, err := h.NewNotificationBots(c.Request.Context(), integration, ev, h.pipelinesChatID(integration))
 	if err != nil {
-		h.SendNewResponse(c, http.StatusUnauthorized, err.Error())
+		h.SendBotError(c, err)
 		return
 	}
-	bot, err := notification_bot.NewBot(baseBot, *integration.IssuesNotificationChatID)
+
+	err = h.Broadcast(c.Request.Context(), bots, func(bot *notification_bot.Bot) error {
+		return bot.SendPipelineNotification(c.Request.Context(), payload)
+	})
 	if err != nil {
-		h.SendNewResponse(c, http.StatusUnauthorized, err.Error())
+		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.SendNewResponse(c, http.StatusOK, "OK")
+}
+
+// handleJob сообщает об упавших задачах сразу, не дожидаясь конца пайплайна.
+func (h *GitlabIssueWebhookHandler) handleJob(
+	c *gin.Context,
+	integration *domain.CompanyIntegration,
+	ev domain.RoutingEvent,
+	payload domain.GitlabJobWebhook,
+) {
+	if payload.BuildStatus != "failed" || payload.BuildAllowFailure {
+		h.SendNewResponse(c, http.StatusOK, "OK, but ignored")
+		return
+	}
+
+	ev.Branch = payload.Ref
+	ev.Status = payload.BuildStatus
+
+	bots, err := h.NewNotificationBots(c.Request.Context(), integration, ev, h.pipelinesChatID(integration))
+	if err != nil {
+		h.SendBotError(c, err)
 		return
 	}
 
-	bot.SendIssueNotification(payload)
+	err = h.Broadcast(c.Request.Context(), bots, func(bot *notification_bot.Bot) error {
+		return bot.SendJobNotification(c.Request.Context(), payload)
+	})
+	if err != nil {
+		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
 
 	h.SendNewResponse(c, http.StatusOK, "OK")
 }
+
+func (h *GitlabIssueWebhookHandler) handleRelease(
+	c *gin.Context,
+	integration *domain.CompanyIntegration,
+	ev domain.RoutingEvent,
+	payload domain.GitlabReleaseWebhook,
+) {
+	bots, err := h.NewNotificationBots(c.Request.Context(), integration, ev, h.pipelinesChatID(integration))
+	if err != nil {
+		h.SendBotError(c, err)
+		return
+	}
+
+	err = h.Broadcast(c.Request.Context(), bots, func(bot *notification_bot.Bot) error {
+		return bot.SendReleaseNotification(c.Request.Context(), payload)
+	})
+	if err != nil {
+		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.SendNewResponse(c, http.StatusOK, "OK")
+}
+
+// isMergeRequestEvent — событие по MR: сам MR или комментарий к нему.
+func (h *GitlabIssueWebhookHandler) isMergeRequestEvent(payload domain.GitlabWebhook) bool {
+	return payload.ObjectKind == "merge_request" ||
+		(payload.ObjectKind == "note" && payload.ObjectAttributes.NoteableType == "MergeRequest")
+}
+
+// resolveChatID выбирает чат по типу события. Если отдельный чат для MR
+// не настроен, уведомления уходят в чат задач.
+func (h *GitlabIssueWebhookHandler) resolveChatID(
+	payload domain.GitlabWebhook,
+	integration *domain.CompanyIntegration,
+) *string {
+	if h.isMergeRequestEvent(payload) && integration.MergeRequestsNotificationChatID != nil {
+		return integration.MergeRequestsNotificationChatID
+	}
+	return integration.IssuesNotificationChatID
+}
+
+// pipelinesChatID — чат для CI и релизов, по умолчанию чат сборок.
+func (h *GitlabIssueWebhookHandler) pipelinesChatID(integration *domain.CompanyIntegration) *string {
+	if integration.PipelinesNotificationChatID != nil {
+		return integration.PipelinesNotificationChatID
+	}
+	return integration.DeployNotificationChatID
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+
+	"victa/internal/domain"
+	appErr "victa/internal/errors"
+	"victa/internal/service"
+	"victa/internal/webhook/webhook_common"
+)
+
+// Replayer прогоняет сохранённый вебхук через тот же обработчик,
+// что и при первой доставке: с исходными заголовками и телом,
+// но без проверки токена и дедупликации.
+type Replayer struct {
+	archiveSvc *service.WebhookArchiveService
+	handlers   map[string]gin.HandlerFunc
+}
+
+func NewReplayer(archiveSvc *service.WebhookArchiveService, handlers map[string]gin.HandlerFunc) *Replayer {
+	return &Replayer{archiveSvc: archiveSvc, handlers: handlers}
+}
+
+// Replay повторяет вебхук deliveryID компании companyID и возвращает
+// ответ обработчика.
+func (r *Replayer) Replay(ctx context.Context, companyID, deliveryID int64) (int, string, error) {
+	d, err := r.archiveSvc.GetByID(ctx, deliveryID)
+	if err != nil {
+		return 0, "", err
+	}
+	if d.CompanyID != companyID {
+		return 0, "", appErr.ErrWebhookDeliveryNotFound
+	}
+
+	handle, ok := r.handlers[d.Provider]
+	if !ok {
+		return 0, "", fmt.Errorf("no handler for provider %q", d.Provider)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/webhook/"+d.Provider, bytes.NewBufferString(d.Body))
+	if err != nil {
+		return 0, "", err
+	}
+	for k, v := range d.Headers {
+		req.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = req
+	c.Set(webhook_common.ReplayCompanyCtx, companyID)
+
+	handle(c)
+
+	var resp domain.ApiResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	return rec.Code, resp.Message, nil
+}
@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"victa/internal/bot/bot_common"
+	"victa/internal/bot/notification_bot"
+	"victa/internal/domain"
+	"victa/internal/logger"
+	"victa/internal/service"
+	"victa/internal/webhook/webhook_common"
+)
+
+type SentryWebhookHandler struct {
+	*webhook_common.BaseWebhook
+	companySvc *service.CompanyService
+	appSvc     *service.AppService
+}
+
+func NewSentryWebhookHandler(
+	factory *bot_common.BotFactory,
+	logger logger.Logger,
+	jwtSvc *service.JWTService,
+	outboxSvc *service.OutboxService,
+	deliverySvc *service.DeliveryService,
+	routingSvc *service.RoutingService,
+	identitySvc *service.IdentityService,
+	companySvc *service.CompanyService,
+	appSvc *service.AppService,
+) *SentryWebhookHandler {
+	base := webhook_common.NewBaseWebhook(factory, logger, jwtSvc, outboxSvc, deliverySvc, routingSvc, identitySvc)
+	return &SentryWebhookHandler{
+		BaseWebhook: base,
+		companySvc:  companySvc,
+		appSvc:      appSvc,
+	}
+}
+
+func (h *SentryWebhookHandler) Handle(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	companyID, viaJWT, err := h.Identify(c)
+	if err != nil {
+		h.SendNewResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		h.SendNewResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	integration, err := h.companySvc.GetCompanyIntegrationByID(ctx, companyID)
+	if err != nil {
+		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if integration == nil {
+		h.SendNewResponse(c, http.StatusBadRequest, "company not found")
+		return
+	}
+
+	err = h.Verify(viaJWT, integration.SentryClientSecret, func(secret string) error {
+		return webhook_common.VerifyHMACSHA256(body, secret, c.GetHeader("Sentry-Hook-Signature"), "")
+	})
+	if err != nil {
+		h.SendNewResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	resource := c.GetHeader("Sentry-Hook-Resource")
+	switch resource {
+	case "event_alert", "metric_alert":
+	default:
+		h.SendNewResponse(c, http.StatusOK, "OK, but ignored")
+		return
+	}
+
+	if h.SkipDuplicate(c, companyID, "sentry", c.GetHeader("Request-ID"), webhook_common.BodyKey(body)) {
+		return
+	}
+
+	var payload domain.SentryWebhook
+	if err := json.Unmarshal(body, &payload); err != nil {
+		h.SendNewResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if (resource == "event_alert" && payload.Data.Event == nil) ||
+		(resource == "metric_alert" && payload.Data.MetricAlert == nil) {
+		h.SendNewResponse(c, http.StatusBadRequest, "unexpected sentry payload")
+		return
+	}
+
+	// event_alert несёт ID проекта, metric_alert — slug'и проектов правила
+	var projectID, projectSlug string
+	if payload.Data.Event != nil && payload.Data.Event.Project != 0 {
+		projectID = strconv.FormatInt(payload.Data.Event.Project, 10)
+	}
+	if payload.Data.MetricAlert != nil && len(payload.Data.MetricAlert.AlertRule.Projects) > 0 {
+		projectSlug = payload.Data.MetricAlert.AlertRule.Projects[0]
+	}
+	app, err := h.appSvc.FindBySentryProject(ctx, companyID, projectID, projectSlug)
+	if err != nil {
+		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	integration = integration.ForApp(app)
+
+	ev := domain.RoutingEvent{
+		Source:      domain.RoutingSourceSentry,
+		TriggerType: resource,
+	}
+	if payload.Data.Event != nil {
+		ev.Severity = payload.Data.Event.Level
+	}
+	if app != nil {
+		ev.AppID = app.AppID
+	}
+
+	bots, err := h.NewNotificationBots(ctx, integration, ev, integration.ErrorsNotificationChatID)
+	if err != nil {
+		h.SendBotError(c, err)
+		return
+	}
+
+	err = h.Broadcast(ctx, bots, func(bot *notification_bot.Bot) error {
+		if resource == "metric_alert" {
+			return bot.SendSentryMetricAlertNotification(ctx, payload)
+		}
+		return bot.SendSentryEventNotification(ctx, payload)
+	})
+	if err != nil {
+		h.SendNewResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.SendNewResponse(c, http.StatusOK, "OK")
+}
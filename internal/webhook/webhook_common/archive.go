@@ -0,0 +1,101 @@
+package webhook_common
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"victa/internal/domain"
+	"victa/internal/logger"
+	"victa/internal/service"
+)
+
+// Ключи gin.Context, через которые обработчик сообщает архиву, чей это
+// вебхук и чем закончилась обработка.
+const (
+	companyIDCtx       = "webhook_company_id"
+	responseMessageCtx = "webhook_response_message"
+)
+
+// maxWebhookBody — предел тела вебхука. Самые крупные из настоящих —
+// пайплайны GitLab с сотнями задач — укладываются в него с запасом.
+const maxWebhookBody = 5 << 20
+
+// Archiver сохраняет каждый входящий вебхук в webhook_deliveries.
+type Archiver struct {
+	logger     logger.Logger
+	archiveSvc *service.WebhookArchiveService
+}
+
+func NewArchiver(logger logger.Logger, archiveSvc *service.WebhookArchiveService) *Archiver {
+	return &Archiver{logger: logger, archiveSvc: archiveSvc}
+}
+
+// Middleware оборачивает обработчик provider. Неаутентифицированные
+// запросы (401) и запросы без компании не сохраняются, тело больше
+// maxWebhookBody отклоняется с 413 до чтения целиком.
+func (a *Archiver) Middleware(provider string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBody))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			code := http.StatusRequestEntityTooLarge
+			c.AbortWithStatusJSON(code, domain.ApiResponse{Status: code, Message: err.Error()})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		companyID := c.GetInt64(companyIDCtx)
+		if companyID == 0 || status == http.StatusUnauthorized {
+			return
+		}
+
+		msg := c.GetString(responseMessageCtx)
+		delivery := &domain.WebhookDelivery{
+			CompanyID: companyID,
+			Provider:  provider,
+			Headers:   make(map[string]string, len(c.Request.Header)),
+			Body:      string(body),
+			Status:    status,
+			Outcome:   deliveryOutcome(status, msg),
+		}
+		for k, v := range c.Request.Header {
+			if !domain.IsSecretWebhookHeader(k) && len(v) > 0 {
+				delivery.Headers[k] = v[0]
+			}
+		}
+		if delivery.Outcome == domain.DeliveryOutcomeFailed {
+			delivery.Error = &msg
+		}
+
+		if err := a.archiveSvc.Record(c.Request.Context(), delivery); err != nil {
+			a.logger.Error("archive %s webhook: %v", provider, err)
+		}
+	}
+}
+
+// deliveryOutcome выводит итог из ответа обработчика: все они отвечают
+// через SendNewResponse, а пропуски помечают текстом «ignored»/«duplicate».
+func deliveryOutcome(status int, msg string) string {
+	switch {
+	case status >= http.StatusBadRequest:
+		return domain.DeliveryOutcomeFailed
+	case strings.Contains(msg, "duplicate"):
+		return domain.DeliveryOutcomeDuplicate
+	case strings.Contains(msg, "ignored"):
+		return domain.DeliveryOutcomeIgnored
+	default:
+		return domain.DeliveryOutcomeOK
+	}
+}
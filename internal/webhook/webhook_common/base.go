@@ -1,30 +1,49 @@
 package webhook_common
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
 	"strings"
 	"victa/internal/bot/bot_common"
+	"victa/internal/bot/notification_bot"
 	"victa/internal/domain"
 	"victa/internal/logger"
 	"victa/internal/service"
 )
 
 type BaseWebhook struct {
-	BotFactory *bot_common.BotFactory
-	Logger     logger.Logger
-	jwtSvc     *service.JWTService
+	BotFactory  *bot_common.BotFactory
+	Logger      logger.Logger
+	jwtSvc      *service.JWTService
+	outboxSvc   *service.OutboxService
+	deliverySvc *service.DeliveryService
+	routingSvc  *service.RoutingService
+	identitySvc *service.IdentityService
 }
 
 func NewBaseWebhook(
 	botFactory *bot_common.BotFactory,
 	logger logger.Logger,
 	jwtSvc *service.JWTService,
+	outboxSvc *service.OutboxService,
+	deliverySvc *service.DeliveryService,
+	routingSvc *service.RoutingService,
+	identitySvc *service.IdentityService,
 ) *BaseWebhook {
 	return &BaseWebhook{
-		BotFactory: botFactory,
-		Logger:     logger,
-		jwtSvc:     jwtSvc,
+		BotFactory:  botFactory,
+		Logger:      logger,
+		jwtSvc:      jwtSvc,
+		outboxSvc:   outboxSvc,
+		deliverySvc: deliverySvc,
+		routingSvc:  routingSvc,
+		identitySvc: identitySvc,
 	}
 }
 
@@ -45,10 +64,239 @@ func (wh *BaseWebhook) Authorize(c *gin.Context) (int64, error) {
 	return wh.jwtSvc.ParseToken(strings.TrimPrefix(auth, "Bearer "))
 }
 
+// ErrCompanyMismatch — company_id в пути не совпадает с компанией из токена.
+var ErrCompanyMismatch = errors.New("company id does not match access token")
+
+// ErrSecretNotConfigured — запрос без токена, а у компании не задан секрет провайдера.
+var ErrSecretNotConfigured = errors.New("webhook secret is not configured, use access token")
+
+// ReplayCompanyCtx — ключ gin.Context, которым Replayer помечает повтор
+// сохранённого вебхука. Значение — ID компании.
+const ReplayCompanyCtx = "webhook_replay_company_id"
+
+// Identify определяет компанию вебхука: по JWT (Authorization / ?access_token)
+// или по company_id из пути /webhook/<provider>/:company_id. viaJWT сообщает,
+// подтверждён ли запрос токеном; иначе его нужно проверить секретом через Verify.
+func (wh *BaseWebhook) Identify(c *gin.Context) (companyID int64, viaJWT bool, err error) {
+	defer func() {
+		if err == nil {
+			c.Set(companyIDCtx, companyID)
+		}
+	}()
+
+	// повтор из архива уже прошёл проверку при первой доставке
+	if id, ok := c.Get(ReplayCompanyCtx); ok {
+		return id.(int64), true, nil
+	}
+
+	pathID := c.Param("company_id")
+	if pathID == "" {
+		pathID = c.Query("company_id")
+	}
+
+	companyID, err = wh.Authorize(c)
+	if err == nil {
+		if pathID != "" && pathID != strconv.FormatInt(companyID, 10) {
+			return 0, false, ErrCompanyMismatch
+		}
+		return companyID, true, nil
+	}
+	if pathID == "" {
+		return 0, false, err
+	}
+
+	companyID, err = strconv.ParseInt(pathID, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid company id: %w", err)
+	}
+	return companyID, false, nil
+}
+
+// Verify проверяет подлинность запроса. Если у компании задан секрет
+// провайдера, check обязателен даже при валидном JWT; без секрета
+// запрос пропускается только с JWT.
+func (wh *BaseWebhook) Verify(viaJWT bool, secret *string, check func(secret string) error) error {
+	if secret != nil && *secret != "" {
+		return check(*secret)
+	}
+	if viaJWT {
+		return nil
+	}
+	return ErrSecretNotConfigured
+}
+
+// ErrChatNotConfigured — у компании не задан бот или чат для уведомления.
+var ErrChatNotConfigured = errors.New("notification chat is not configured")
+
+// NewNotificationBot создаёт бота уведомлений компании для заданного чата.
+// Уведомления бот не отправляет сам, а ставит в очередь outbox.
+func (wh *BaseWebhook) NewNotificationBot(
+	integration *domain.CompanyIntegration,
+	chatID *string,
+) (*notification_bot.Bot, error) {
+	if integration.NotificationBotToken == nil || chatID == nil {
+		return nil, ErrChatNotConfigured
+	}
+
+	baseBot, err := wh.BotFactory.GetBaseBot(*integration.NotificationBotToken, wh.Logger)
+	if err != nil {
+		return nil, err
+	}
+	bot, err := notification_bot.NewBot(baseBot, *chatID)
+	if err != nil {
+		return nil, err
+	}
+	return bot.WithOutbox(integration.CompanyID, wh.outboxSvc).WithIdentities(wh.identitySvc), nil
+}
+
+// ErrMuted — событие заглушено правилом маршрутизации.
+var ErrMuted = errors.New("muted by routing rule")
+
+// errRouting — правила маршрутизации не удалось прочитать.
+var errRouting = errors.New("routing rules")
+
+// NewNotificationBots применяет правила маршрутизации компании и создаёт
+// ботов для всех чатов назначения. fallback — чат категории из настроек
+// компании на случай, если ни одно правило не подошло.
+func (wh *BaseWebhook) NewNotificationBots(
+	ctx context.Context,
+	integration *domain.CompanyIntegration,
+	ev domain.RoutingEvent,
+	fallback *string,
+) ([]*notification_bot.Bot, error) {
+	chatIDs, err := wh.routingSvc.Resolve(ctx, integration.CompanyID, ev, fallback)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errRouting, err)
+	}
+	if chatIDs == nil {
+		return nil, ErrChatNotConfigured
+	}
+	if len(chatIDs) == 0 {
+		return nil, ErrMuted
+	}
+
+	bots := make([]*notification_bot.Bot, 0, len(chatIDs))
+	for _, chatID := range chatIDs {
+		bot, err := wh.NewNotificationBot(integration, &chatID)
+		if err != nil {
+			return nil, err
+		}
+		bots = append(bots, bot)
+	}
+	return bots, nil
+}
+
+// Broadcast вызывает send для каждого бота, а уведомления ставит в очередь
+// одной транзакцией: событие получат либо все чаты, либо ни один, и повтор
+// провайдера после ошибки не задвоит сообщение там, куда оно уже ушло.
+func (wh *BaseWebhook) Broadcast(
+	ctx context.Context,
+	bots []*notification_bot.Bot,
+	send func(bot *notification_bot.Bot) error,
+) error {
+	batch := wh.outboxSvc.NewBatch()
+	for _, bot := range bots {
+		if err := send(bot.WithOutbox(bot.CompanyID(), batch)); err != nil {
+			return err
+		}
+	}
+	return batch.Commit(ctx)
+}
+
+// SendBotError отвечает на ошибку создания бота уведомлений:
+// заглушенное событие — пропуск, ненастроенный чат — ошибка запроса,
+// остальное — невалидный токен бота.
+func (wh *BaseWebhook) SendBotError(c *gin.Context, err error) {
+	if errors.Is(err, ErrMuted) {
+		wh.SendNewResponse(c, http.StatusOK, "OK, but ignored: "+err.Error())
+		return
+	}
+	if errors.Is(err, ErrChatNotConfigured) {
+		wh.SendNewResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, errRouting) {
+		wh.SendNewResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	wh.SendNewResponse(c, http.StatusUnauthorized, err.Error())
+}
+
+// deliveryKeysCtx — ключ gin.Context со списком ключей текущей доставки.
+const deliveryKeysCtx = "webhook_delivery_keys"
+
+type deliveryKey struct {
+	companyID int64
+	provider  string
+	key       string
+}
+
+// SkipDuplicate записывает ключи доставки и, если хоть один уже встречался,
+// сам отвечает 200 и возвращает true. Пустые ключи пропускаются. Если
+// обработка потом завершится любым ответом кроме 2xx, ключи освобождаются
+// в SendNewResponse: уведомление не отправлено, и повтор провайдера или
+// исправленной настройки должен пройти.
+func (wh *BaseWebhook) SkipDuplicate(c *gin.Context, companyID int64, provider string, keys ...string) bool {
+	// повтор из архива запускается вручную и должен пройти заново
+	if _, ok := c.Get(ReplayCompanyCtx); ok {
+		return false
+	}
+
+	ctx := c.Request.Context()
+
+	var claimed []deliveryKey
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		fresh, err := wh.deliverySvc.Claim(ctx, companyID, provider, key)
+		if err != nil {
+			// лучше дубль в чате, чем потерянное уведомление
+			wh.Logger.Warn("claim delivery key %s/%s: %v", provider, key, err)
+			continue
+		}
+		if !fresh {
+			wh.releaseDeliveryKeys(c, claimed)
+			wh.SendNewResponse(c, http.StatusOK, "OK, duplicate delivery")
+			return true
+		}
+		claimed = append(claimed, deliveryKey{companyID: companyID, provider: provider, key: key})
+	}
+
+	c.Set(deliveryKeysCtx, claimed)
+	return false
+}
+
+func (wh *BaseWebhook) releaseDeliveryKeys(c *gin.Context, keys []deliveryKey) {
+	for _, k := range keys {
+		if err := wh.deliverySvc.Release(c.Request.Context(), k.companyID, k.provider, k.key); err != nil {
+			wh.Logger.Error("release delivery key %s/%s: %v", k.provider, k.key, err)
+		}
+	}
+}
+
+// BodyKey — ключ доставки по содержимому, когда провайдер не шлёт ID запроса
+// или присылает одно и то же событие под разными ID.
+func BodyKey(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
 func (wh *BaseWebhook) SendNewResponse(
 	c *gin.Context,
 	code int,
 	msg string,
 ) {
+	c.Set(responseMessageCtx, msg)
+
+	if code < http.StatusOK || code >= http.StatusMultipleChoices {
+		if keys, ok := c.Get(deliveryKeysCtx); ok {
+			c.Set(deliveryKeysCtx, nil)
+			if keys, ok := keys.([]deliveryKey); ok {
+				wh.releaseDeliveryKeys(c, keys)
+			}
+		}
+	}
 	c.AbortWithStatusJSON(code, domain.ApiResponse{Status: code, Message: msg})
 }
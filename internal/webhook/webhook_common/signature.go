@@ -0,0 +1,43 @@
+package webhook_common
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ErrBadSignature — подпись вебхука отсутствует или не совпала.
+var ErrBadSignature = errors.New("webhook signature mismatch")
+
+// VerifyHMACSHA256 сверяет hex‑подпись HMAC‑SHA256(body, secret).
+// prefix — необязательный префикс значения заголовка, например «sha256=».
+func VerifyHMACSHA256(body []byte, secret, signature, prefix string) error {
+	signature = strings.TrimSpace(signature)
+	if signature == "" || !strings.HasPrefix(signature, prefix) {
+		return ErrBadSignature
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, prefix))
+	if err != nil {
+		return ErrBadSignature
+	}
+
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write(body)
+	if !hmac.Equal(got, m.Sum(nil)) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// VerifySecretToken сверяет общий секрет из заголовка (например, X-Gitlab-Token)
+// за постоянное время.
+func VerifySecretToken(got, secret string) error {
+	if got == "" || subtle.ConstantTimeCompare([]byte(got), []byte(secret)) != 1 {
+		return ErrBadSignature
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"victa/internal/bot/bot_common"
+	"victa/internal/bot/notification_bot"
+	"victa/internal/domain"
+	appErr "victa/internal/errors"
+	"victa/internal/logger"
+	"victa/internal/service"
+)
+
+// BuildProgress ведёт одно сообщение на билд Codemagic: создаёт его при первом
+// событии и редактирует по мере выполнения шагов. Незавершённые билды
+// дополнительно опрашиваются через API, потому что Codemagic присылает
+// вебхук только на старте и в конце сборки. Само сообщение отправляет
+// Outbox по привязке build_messages.
+type BuildProgress struct {
+	factory      *bot_common.BotFactory
+	logger       logger.Logger
+	companySvc   *service.CompanyService
+	appSvc       *service.AppService
+	codemagicSvc *service.CodemagicService
+	buildMsgSvc  *service.BuildMessageService
+	outboxSvc    *service.OutboxService
+	identitySvc  *service.IdentityService
+	interval     time.Duration
+}
+
+func NewBuildProgress(
+	factory *bot_common.BotFactory,
+	logger logger.Logger,
+	companySvc *service.CompanyService,
+	appSvc *service.AppService,
+	codemagicSvc *service.CodemagicService,
+	buildMsgSvc *service.BuildMessageService,
+	outboxSvc *service.OutboxService,
+	identitySvc *service.IdentityService,
+) *BuildProgress {
+	return &BuildProgress{
+		factory:      factory,
+		logger:       logger,
+		companySvc:   companySvc,
+		appSvc:       appSvc,
+		codemagicSvc: codemagicSvc,
+		buildMsgSvc:  buildMsgSvc,
+		outboxSvc:    outboxSvc,
+		identitySvc:  identitySvc,
+		interval:     20 * time.Second,
+	}
+}
+
+// WithInterval меняет период опроса незавершённых билдов.
+func (p *BuildProgress) WithInterval(d time.Duration) *BuildProgress {
+	p.interval = d
+	return p
+}
+
+// Publish ставит в очередь сообщение о билде для каждого чата из bots.
+// В чат попадают только артефакты видов из artifact_types компании.
+// Каждый артефакт попадает в карточку публичной ссылкой; если компания
+// или приложение включили upload_artifacts, APK/AAB до 50 MB уйдут в чат
+// ещё и файлами (ссылка остаётся на случай, если загрузка не удастся),
+// а с artifact_qr_codes к ссылкам добавятся QR‑коды.
+func (p *BuildProgress) Publish(
+	ctx context.Context,
+	integration *domain.CompanyIntegration,
+	bots []*notification_bot.Bot,
+	resp *domain.CodemagicBuildResponse,
+) error {
+	build := resp.Build
+
+	var (
+		docs    []domain.NotificationDocument
+		qrCodes []domain.NotificationQRCode
+	)
+	if build.IsFinished() && integration.CodemagicAPIKey != nil {
+		if integration.UploadsArtifacts() {
+			docs = p.uploadableArtifacts(integration, build)
+		}
+		p.resolvePublicURLs(ctx, integration, &build)
+		if integration.SendsArtifactQRCodes() {
+			qrCodes = p.qrCodes(build)
+		}
+	}
+
+	// все чаты получают билд одной транзакцией, см. OutboxBatch
+	batch := p.outboxSvc.NewBatch()
+	for _, bot := range bots {
+		existing, err := p.buildMsgSvc.GetByBuildID(ctx, build.ID, bot.ChatID())
+		if err != nil && !errors.Is(err, appErr.ErrBuildMessageNotFound) {
+			p.logger.Warn("build message %s: %v", build.ID, err)
+		}
+
+		// запоздавший промежуточный статус не должен затирать итог
+		if existing != nil && existing.Finished && !build.IsFinished() {
+			continue
+		}
+
+		bot.WithOutbox(bot.CompanyID(), batch)
+		if err := bot.SendDeployNotification(ctx, resp.Application, build, docs, qrCodes); err != nil {
+			return err
+		}
+	}
+	return batch.Commit(ctx)
+}
+
+// Run опрашивает незавершённые билды, пока не отменят ctx.
+func (p *BuildProgress) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.poll(ctx)
+		}
+	}
+}
+
+func (p *BuildProgress) poll(ctx context.Context) {
+	active, err := p.buildMsgSvc.GetActive(ctx)
+	if err != nil {
+		p.logger.Error("active builds: %v", err)
+		return
+	}
+
+	for _, msg := range active {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := p.refresh(ctx, msg); err != nil {
+			p.logger.Warn("refresh build %s: %v", msg.BuildID, err)
+		}
+	}
+}
+
+func (p *BuildProgress) refresh(ctx context.Context, msg domain.BuildMessage) error {
+	integration, err := p.companySvc.GetCompanyIntegrationByID(ctx, msg.CompanyID)
+	if err != nil {
+		return err
+	}
+	if integration.CodemagicAPIKey == nil || integration.NotificationBotToken == nil {
+		return nil
+	}
+
+	cmCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := p.codemagicSvc.GetBuildByID(cmCtx, msg.BuildID, *integration.CodemagicAPIKey)
+	if err != nil {
+		return err
+	}
+
+	app, err := p.appSvc.FindByCodemagicAppID(ctx, msg.CompanyID, msg.AppID)
+	if err != nil {
+		return err
+	}
+	integration = integration.ForApp(app)
+
+	baseBot, err := p.factory.GetBaseBot(*integration.NotificationBotToken, p.logger)
+	if err != nil {
+		return err
+	}
+	// чат берём из привязки: у приложения он может отличаться от чата компании
+	bot, err := notification_bot.NewBot(baseBot, msg.ChatID)
+	if err != nil {
+		return err
+	}
+
+	bot = bot.WithOutbox(msg.CompanyID, p.outboxSvc).WithIdentities(p.identitySvc)
+	return p.Publish(ctx, integration, []*notification_bot.Bot{bot}, resp)
+}
+
+// uploadableArtifacts выбирает APK и AAB, которые бот может загрузить в чат.
+// Артефакты без размера или больше лимита остаются ссылками.
+func (p *BuildProgress) uploadableArtifacts(
+	integration *domain.CompanyIntegration,
+	build domain.CodemagicBuild,
+) []domain.NotificationDocument {
+	var docs []domain.NotificationDocument
+	for _, art := range build.Artefacts {
+		kind := art.Kind()
+		if kind != domain.ArtefactKindAPK && kind != domain.ArtefactKindAAB {
+			continue
+		}
+		if !integration.SharesArtifact(kind) {
+			continue
+		}
+		path := art.ArtifactPath()
+		if path == "" || art.Size <= 0 || art.Size > notification_bot.MaxDocumentSize {
+			continue
+		}
+		docs = append(docs, domain.NotificationDocument{
+			ArtifactPath: path,
+			Name:         art.Name,
+			Size:         art.Size,
+		})
+	}
+	return docs
+}
+
+// qrCodes собирает ссылки на артефакты для QR‑кодов.
+func (p *BuildProgress) qrCodes(build domain.CodemagicBuild) []domain.NotificationQRCode {
+	var out []domain.NotificationQRCode
+	for _, art := range build.Artefacts {
+		if art.PublicURL == "" {
+			continue
+		}
+		out = append(out, domain.NotificationQRCode{Name: art.Name, URL: art.PublicURL})
+	}
+	return out
+}
+
+// resolvePublicURLs подставляет публичные ссылки на все артефакты, которые
+// компания публикует в чатах, в том числе на уходящие файлом.
+func (p *BuildProgress) resolvePublicURLs(
+	ctx context.Context,
+	integration *domain.CompanyIntegration,
+	build *domain.CodemagicBuild,
+) {
+	cmCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	for i, art := range build.Artefacts {
+		path := art.ArtifactPath()
+		if path == "" || !integration.SharesArtifact(art.Kind()) {
+			continue
+		}
+		url, err := p.codemagicSvc.GetArtifactPublicURL(cmCtx, path, *integration.CodemagicAPIKey)
+		if err != nil {
+			p.logger.Warn("codemagic public URL %s: %v", art.Name, err)
+			continue
+		}
+		build.Artefacts[i].PublicURL = url
+	}
+}
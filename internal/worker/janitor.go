@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"victa/internal/logger"
+	"victa/internal/service"
+)
+
+// Janitor периодически чистит служебные таблицы с TTL.
+type Janitor struct {
+	logger      logger.Logger
+	deliverySvc *service.DeliveryService
+	archiveSvc  *service.WebhookArchiveService
+	replySvc    *service.PendingReplyService
+	interval    time.Duration
+}
+
+func NewJanitor(
+	logger logger.Logger,
+	deliverySvc *service.DeliveryService,
+	archiveSvc *service.WebhookArchiveService,
+	replySvc *service.PendingReplyService,
+) *Janitor {
+	return &Janitor{
+		logger:      logger,
+		deliverySvc: deliverySvc,
+		archiveSvc:  archiveSvc,
+		replySvc:    replySvc,
+		interval:    time.Hour,
+	}
+}
+
+// Run чистит таблицы раз в interval, пока не отменят ctx.
+func (j *Janitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			j.clean(ctx)
+		}
+	}
+}
+
+func (j *Janitor) clean(ctx context.Context) {
+	if n, err := j.deliverySvc.PurgeExpired(ctx); err != nil {
+		j.logger.Error("purge delivery keys: %v", err)
+	} else if n > 0 {
+		j.logger.Debug("purged %d delivery keys", n)
+	}
+
+	if n, err := j.archiveSvc.PurgeExpired(ctx); err != nil {
+		j.logger.Error("purge webhook deliveries: %v", err)
+	} else if n > 0 {
+		j.logger.Debug("purged %d webhook deliveries", n)
+	}
+
+	if n, err := j.replySvc.PurgeExpired(ctx); err != nil {
+		j.logger.Error("purge pending replies: %v", err)
+	} else if n > 0 {
+		j.logger.Debug("purged %d pending replies", n)
+	}
+}
@@ -0,0 +1,163 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"victa/internal/bot/bot_common"
+	"victa/internal/bot/notification_bot"
+	"victa/internal/logger"
+	"victa/internal/service"
+)
+
+const (
+	// updatesPollTimeout — long polling getUpdates; столько же ждём остановки.
+	updatesPollTimeout = 10
+	// updatesErrorPause — пауза после ошибки getUpdates (сеть, чужой webhook).
+	updatesErrorPause = time.Minute
+	updatesTimeout    = 10 * time.Second
+)
+
+// NotificationUpdates принимает нажатия кнопок под уведомлениями и ответы
+// на запросы комментариев. Для каждого бота уведомлений держит свой long
+// polling и передаёт обновления в notification_bot.Actions. Бот с токеном
+// skipToken пропускается: его обновления уже читает victa_bot. getUpdates
+// допускает одного читателя на бота, поэтому запускается через Singleton.
+type NotificationUpdates struct {
+	factory    *bot_common.BotFactory
+	logger     logger.Logger
+	companySvc *service.CompanyService
+	actions    *notification_bot.Actions
+	skipToken  string
+	interval   time.Duration // как часто перечитывать список ботов
+
+	wg      sync.WaitGroup
+	running map[string]context.CancelFunc
+}
+
+func NewNotificationUpdates(
+	factory *bot_common.BotFactory,
+	logger logger.Logger,
+	companySvc *service.CompanyService,
+	actions *notification_bot.Actions,
+	skipToken string,
+) *NotificationUpdates {
+	return &NotificationUpdates{
+		factory:    factory,
+		logger:     logger,
+		companySvc: companySvc,
+		actions:    actions,
+		skipToken:  skipToken,
+		interval:   time.Minute,
+		running:    make(map[string]context.CancelFunc),
+	}
+}
+
+// Run запускает и останавливает опрос ботов вслед за настройками компаний,
+// пока не отменят ctx.
+func (u *NotificationUpdates) Run(ctx context.Context) error {
+	ticker := time.NewTicker(u.interval)
+	defer ticker.Stop()
+
+	for {
+		u.sync(ctx)
+
+		select {
+		case <-ctx.Done():
+			for _, cancel := range u.running {
+				cancel()
+			}
+			u.wg.Wait()
+			// Run запускается заново, когда реплика снова станет лидером
+			clear(u.running)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync сверяет запущенные опросы со списком токенов из интеграций.
+func (u *NotificationUpdates) sync(ctx context.Context) {
+	tokens, err := u.companySvc.GetNotificationBotTokens(ctx)
+	if err != nil {
+		u.logger.Error("notification bot tokens: %v", err)
+		return
+	}
+
+	actual := make(map[string]struct{}, len(tokens))
+	for _, token := range tokens {
+		if token == u.skipToken {
+			continue
+		}
+		actual[token] = struct{}{}
+
+		if _, ok := u.running[token]; ok {
+			continue
+		}
+		pollCtx, cancel := context.WithCancel(ctx)
+		u.running[token] = cancel
+		u.wg.Add(1)
+		go func() {
+			defer u.wg.Done()
+			u.poll(pollCtx, token)
+		}()
+	}
+
+	for token, cancel := range u.running {
+		if _, ok := actual[token]; !ok {
+			cancel()
+			delete(u.running, token)
+		}
+	}
+}
+
+// poll читает callback_query и сообщения одного бота. Токен остаётся в
+// running, пока его не уберёт sync, поэтому ошибку создания бота (getMe,
+// сеть) не бросаем, а повторяем с паузой — иначе бот молчал бы до рестарта.
+func (u *NotificationUpdates) poll(ctx context.Context, token string) {
+	var base *bot_common.BaseBot
+	for {
+		var err error
+		if base, err = u.factory.GetBaseBot(token, u.logger); err == nil {
+			break
+		}
+		u.logger.Error("notification bot: %v", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(updatesErrorPause):
+		}
+	}
+
+	cfg := tgbotapi.NewUpdate(0)
+	cfg.Timeout = updatesPollTimeout
+	cfg.AllowedUpdates = []string{"callback_query", "message"}
+
+	for ctx.Err() == nil {
+		updates, err := base.BotAPI.GetUpdates(cfg)
+		if err != nil {
+			u.logger.Warn("notification bot @%s updates: %v", base.BotAPI.Self.UserName, err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(updatesErrorPause):
+			}
+			continue
+		}
+
+		for _, upd := range updates {
+			cfg.Offset = upd.UpdateID + 1
+
+			updCtx, cancel := context.WithTimeout(ctx, updatesTimeout)
+			switch {
+			case upd.CallbackQuery != nil && u.actions.Handles(upd.CallbackQuery.Data):
+				u.actions.HandleCallback(updCtx, base, upd.CallbackQuery)
+			case upd.Message != nil && u.actions.HandlesMessage(updCtx, upd.Message):
+				u.actions.HandleMessage(updCtx, base, upd.Message)
+			}
+			cancel()
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"victa/internal/bot/bot_common"
+	"victa/internal/bot/notification_bot"
+	"victa/internal/domain"
+	appErr "victa/internal/errors"
+	"victa/internal/logger"
+	"victa/internal/service"
+)
+
+const (
+	// outboxMaxAttempts — после стольких неудач уведомление уходит в dead‑letter.
+	outboxMaxAttempts = 12
+	outboxBaseBackoff = 5 * time.Second
+	outboxMaxBackoff  = time.Hour
+	outboxBatchSize   = 20
+	// outboxLease — на сколько строка остаётся за воркером после захвата.
+	// Пока пачка обрабатывается, захват продлевается каждые outboxHeartbeat,
+	// так что загрузка артефактов может идти дольше lease.
+	outboxLease     = 2 * time.Minute
+	outboxHeartbeat = outboxLease / 3
+	// traceTailLines — сколько последних строк лога упавшей задачи показывать.
+	traceTailLines = 25
+)
+
+// errPermanent помечает ошибки, которые повтор не исправит.
+var errPermanent = errors.New("permanent delivery error")
+
+// Outbox доставляет уведомления из notification_outbox в Telegram.
+// Ошибки сети и 5xx повторяются с экспоненциальной задержкой, на 429
+// ждём столько, сколько просит Telegram (retry_after). Неисправимые
+// ошибки (чат не найден, бот заблокирован, битый HTML) и исчерпанные
+// попытки переносят уведомление в notification_dead_letters.
+type Outbox struct {
+	factory      *bot_common.BotFactory
+	logger       logger.Logger
+	companySvc   *service.CompanyService
+	outboxSvc    *service.OutboxService
+	buildMsgSvc  *service.BuildMessageService
+	codemagicSvc *service.CodemagicService
+	artifactSvc  *service.ArtifactFileService
+	gitlabSvc    *service.GitlabService
+	interval     time.Duration
+}
+
+func NewOutbox(
+	factory *bot_common.BotFactory,
+	logger logger.Logger,
+	companySvc *service.CompanyService,
+	outboxSvc *service.OutboxService,
+	buildMsgSvc *service.BuildMessageService,
+	codemagicSvc *service.CodemagicService,
+	artifactSvc *service.ArtifactFileService,
+	gitlabSvc *service.GitlabService,
+) *Outbox {
+	return &Outbox{
+		factory:      factory,
+		logger:       logger,
+		companySvc:   companySvc,
+		outboxSvc:    outboxSvc,
+		buildMsgSvc:  buildMsgSvc,
+		codemagicSvc: codemagicSvc,
+		artifactSvc:  artifactSvc,
+		gitlabSvc:    gitlabSvc,
+		interval:     2 * time.Second,
+	}
+}
+
+// Run разбирает очередь, пока не отменят ctx.
+func (o *Outbox) Run(ctx context.Context) error {
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-o.outboxSvc.Wake():
+		}
+
+		for o.drain(ctx) {
+		}
+	}
+}
+
+// drain обрабатывает одну пачку; true — пачка была полной, стоит взять ещё.
+func (o *Outbox) drain(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	batch, err := o.outboxSvc.Claim(ctx, outboxBatchSize, outboxLease)
+	if err != nil {
+		o.logger.Error("outbox claim: %v", err)
+		return false
+	}
+
+	var (
+		mu      sync.Mutex
+		pending = make([]int64, 0, len(batch))
+	)
+	for _, n := range batch {
+		pending = append(pending, n.ID)
+	}
+	stop := o.heartbeat(ctx, func() []int64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(pending)
+	})
+	defer stop()
+
+	for _, n := range batch {
+		o.process(ctx, n)
+
+		mu.Lock()
+		pending = pending[1:]
+		mu.Unlock()
+	}
+	return len(batch) == outboxBatchSize
+}
+
+// heartbeat продлевает захват ещё не обработанных строк пачки, пока не
+// вызовут stop: иначе другой воркер заберёт строку, которая ждёт своей
+// очереди за загрузкой артефактов, и отправит её второй раз.
+func (o *Outbox) heartbeat(ctx context.Context, pending func() []int64) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(outboxHeartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ids := pending()
+				if len(ids) == 0 {
+					continue
+				}
+				if err := o.outboxSvc.Extend(ctx, ids, outboxLease); err != nil && ctx.Err() == nil {
+					o.logger.Warn("outbox extend lease: %v", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func (o *Outbox) process(ctx context.Context, n domain.Notification) {
+	err := o.deliver(ctx, n)
+	if err == nil {
+		if err := o.outboxSvc.Done(ctx, n.ID); err != nil {
+			o.logger.Error("outbox done %d: %v", n.ID, err)
+		}
+		return
+	}
+
+	retryAfter, permanent := o.classify(err)
+	if permanent || n.Attempts >= outboxMaxAttempts {
+		o.logger.Error("outbox %d: giving up after %d attempts: %v", n.ID, n.Attempts, err)
+		if err := o.outboxSvc.Fail(ctx, n.ID, err.Error()); err != nil {
+			o.logger.Error("outbox fail %d: %v", n.ID, err)
+		}
+		return
+	}
+
+	if retryAfter == 0 {
+		retryAfter = o.backoff(n.Attempts)
+	}
+	o.logger.Warn("outbox %d: attempt %d failed, retry in %s: %v", n.ID, n.Attempts, retryAfter, err)
+	if err := o.outboxSvc.Retry(ctx, n.ID, time.Now().Add(retryAfter), err.Error()); err != nil {
+		o.logger.Error("outbox retry %d: %v", n.ID, err)
+	}
+}
+
+func (o *Outbox) deliver(ctx context.Context, n domain.Notification) error {
+	integration, err := o.companySvc.GetCompanyIntegrationByID(ctx, n.CompanyID)
+	if errors.Is(err, appErr.ErrIntegrationNotFound) {
+		return fmt.Errorf("%w: %v", errPermanent, err)
+	}
+	if err != nil {
+		return err
+	}
+	if integration.NotificationBotToken == nil {
+		return fmt.Errorf("%w: notification bot token is not configured", errPermanent)
+	}
+
+	baseBot, err := o.factory.GetBaseBot(*integration.NotificationBotToken, o.logger)
+	if err != nil {
+		return err
+	}
+	bot, err := notification_bot.NewBot(baseBot, n.ChatID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPermanent, err)
+	}
+
+	if n.JobTrace != nil {
+		n.Text = bot.AppendJobTrace(n.Text, o.jobTrace(ctx, integration, *n.JobTrace))
+	}
+	if n.Build == nil {
+		_, err := bot.Deliver(n.Text, 0, n.Buttons)
+		return err
+	}
+	return o.deliverBuild(ctx, bot, integration, n)
+}
+
+// deliverBuild редактирует сообщение билда, если оно уже есть в этом чате.
+// Артефакты отправляются один раз — когда итог билда появляется впервые.
+// Claim не отдаёт параллельно две строки одного билда в одном чате, поэтому
+// чтение BuildMessage и его сохранение не гоняются между воркерами.
+func (o *Outbox) deliverBuild(
+	ctx context.Context,
+	bot *notification_bot.Bot,
+	integration *domain.CompanyIntegration,
+	n domain.Notification,
+) error {
+	existing, err := o.buildMsgSvc.GetByBuildID(ctx, n.Build.BuildID, n.ChatID)
+	if err != nil && !errors.Is(err, appErr.ErrBuildMessageNotFound) {
+		return err
+	}
+
+	messageID := 0
+	if existing != nil {
+		// итог уже показан — промежуточное состояние из очереди устарело
+		if existing.Finished && !n.Build.Finished {
+			return nil
+		}
+		messageID = existing.MessageID
+	}
+
+	messageID, err = bot.Deliver(n.Text, messageID, n.Buttons)
+	if err != nil {
+		return err
+	}
+
+	if n.Build.Finished && (existing == nil || !existing.Finished) {
+		o.deliverDocuments(ctx, bot, integration, n, messageID)
+		o.deliverQRCodes(bot, n, messageID)
+	}
+
+	_, err = o.buildMsgSvc.Save(ctx, &domain.BuildMessage{
+		BuildID:   n.Build.BuildID,
+		CompanyID: n.CompanyID,
+		AppID:     n.Build.AppID,
+		ChatID:    n.ChatID,
+		MessageID: messageID,
+		Status:    n.Build.Status,
+		Finished:  n.Build.Finished,
+	})
+	if err != nil {
+		// сообщение уже в чате — повтор создал бы дубль
+		o.logger.Error("save build message %s: %v", n.Build.BuildID, err)
+	}
+	return nil
+}
+
+// deliverDocuments отправляет артефакты ответом на сообщение билда.
+// Ошибки только логируются: повтор всего уведомления дублировал бы
+// уже отправленные файлы.
+func (o *Outbox) deliverDocuments(
+	ctx context.Context,
+	bot *notification_bot.Bot,
+	integration *domain.CompanyIntegration,
+	n domain.Notification,
+	replyTo int,
+) {
+	for _, doc := range n.Documents {
+		if err := o.deliverDocument(ctx, bot, integration, doc, replyTo); err != nil {
+			o.logger.Warn("outbox %d: artifact %s: %v", n.ID, doc.Name, err)
+		}
+	}
+}
+
+// deliverQRCodes отправляет QR‑коды ссылок на артефакты ответом на
+// сообщение билда. Ошибки, как и у файлов, только логируются.
+func (o *Outbox) deliverQRCodes(bot *notification_bot.Bot, n domain.Notification, replyTo int) {
+	for _, qr := range n.QRCodes {
+		if err := bot.DeliverQRCode(qr.URL, qr.Name, replyTo); err != nil {
+			o.logger.Warn("outbox %d: qr code %s: %v", n.ID, qr.Name, err)
+		}
+	}
+}
+
+// deliverDocument пересылает артефакт по сохранённому file_id, а если его
+// нет или Telegram его не принял — скачивает из Codemagic и загружает.
+func (o *Outbox) deliverDocument(
+	ctx context.Context,
+	bot *notification_bot.Bot,
+	integration *domain.CompanyIntegration,
+	doc domain.NotificationDocument,
+	replyTo int,
+) error {
+	cached, err := o.artifactSvc.GetByPath(ctx, integration.CompanyID, doc.ArtifactPath)
+	if err != nil && !errors.Is(err, appErr.ErrArtifactFileNotFound) {
+		return err
+	}
+	if cached != nil {
+		_, err := bot.DeliverDocument(tgbotapi.FileID(cached.FileID), replyTo)
+		if err == nil {
+			return nil
+		}
+		// file_id принадлежит боту: после смены токена он недействителен
+		o.logger.Warn("cached artifact %s: %v", doc.ArtifactPath, err)
+		if err := o.artifactSvc.Forget(ctx, integration.CompanyID, doc.ArtifactPath); err != nil {
+			return err
+		}
+	}
+
+	if integration.CodemagicAPIKey == nil {
+		return errors.New("codemagic api key is not configured")
+	}
+	body, err := o.codemagicSvc.DownloadArtifact(ctx, doc.ArtifactPath, *integration.CodemagicAPIKey, notification_bot.MaxDocumentSize)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	fileID, err := bot.DeliverDocument(tgbotapi.FileReader{Name: doc.Name, Reader: body}, replyTo)
+	if err != nil {
+		return err
+	}
+
+	_, err = o.artifactSvc.Save(ctx, &domain.ArtifactFile{
+		CompanyID:    integration.CompanyID,
+		ArtifactPath: doc.ArtifactPath,
+		FileID:       fileID,
+		FileName:     doc.Name,
+		Size:         doc.Size,
+	})
+	return err
+}
+
+// jobTrace скачивает хвост лога задачи GitLab CI. Без токена GitLab или
+// при ошибке API возвращает пустую строку — карточка уйдёт без лога.
+func (o *Outbox) jobTrace(
+	ctx context.Context,
+	integration *domain.CompanyIntegration,
+	job domain.NotificationJobTrace,
+) string {
+	if integration.GitlabAPIToken == nil || *integration.GitlabAPIToken == "" {
+		return ""
+	}
+
+	glCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	baseURL := o.gitlabSvc.ResolveBaseURL(integration.GitlabBaseURL)
+	trace, err := o.gitlabSvc.GetJobTraceTail(glCtx, baseURL, *integration.GitlabAPIToken, job.ProjectID, job.JobID, traceTailLines)
+	if err != nil {
+		o.logger.Warn("gitlab job trace %d/%d: %v", job.ProjectID, job.JobID, err)
+		return ""
+	}
+	return trace
+}
+
+// classify решает, что делать с ошибкой: ждать retry_after,
+// повторить с задержкой или сдаться.
+func (o *Outbox) classify(err error) (time.Duration, bool) {
+	if errors.Is(err, errPermanent) {
+		return 0, true
+	}
+
+	var tgErr *tgbotapi.Error
+	if !errors.As(err, &tgErr) {
+		return 0, false
+	}
+
+	switch {
+	case tgErr.Code == 429 || tgErr.RetryAfter > 0:
+		return time.Duration(tgErr.RetryAfter) * time.Second, false
+	case tgErr.Code == 400, tgErr.Code == 401, tgErr.Code == 403, tgErr.Code == 404:
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// backoff — 5s, 10s, 20s … но не больше часа.
+func (o *Outbox) backoff(attempts int) time.Duration {
+	d := outboxBaseBackoff
+	for i := 1; i < attempts && d < outboxMaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, outboxMaxBackoff)
+}
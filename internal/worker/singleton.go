@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"victa/internal/logger"
+	"victa/internal/repository"
+	"victa/internal/service"
+)
+
+const (
+	// singletonRetry — как часто реплика без блокировки пробует её взять.
+	singletonRetry = 30 * time.Second
+	// singletonPing — как часто лидер проверяет, что блокировка ещё его.
+	singletonPing = 15 * time.Second
+)
+
+// Runner — фоновая задача с циклом Run(ctx) до отмены ctx.
+type Runner interface {
+	Run(ctx context.Context) error
+}
+
+// Singleton запускает задачу только на той реплике, что держит её
+// advisory‑блокировку в Postgres. Остальные ждут и подхватывают задачу,
+// когда лидер остановится или потеряет соединение с БД.
+type Singleton struct {
+	logger    logger.Logger
+	leaderSvc *service.LeaderService
+	name      string
+	worker    Runner
+}
+
+func NewSingleton(
+	logger logger.Logger,
+	leaderSvc *service.LeaderService,
+	name string,
+	worker Runner,
+) *Singleton {
+	return &Singleton{
+		logger:    logger,
+		leaderSvc: leaderSvc,
+		name:      name,
+		worker:    worker,
+	}
+}
+
+// Run пытается стать лидером, пока не отменят ctx.
+func (s *Singleton) Run(ctx context.Context) error {
+	for {
+		lock, err := s.leaderSvc.TryAcquire(ctx, s.name)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("leader %s: %v", s.name, err)
+		}
+		if lock != nil {
+			s.logger.Info("лидер задачи %s — эта реплика", s.name)
+			s.lead(ctx, lock)
+			if err := lock.Release(); err != nil {
+				s.logger.Warn("leader %s: %v", s.name, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(singletonRetry):
+		}
+	}
+}
+
+// lead выполняет задачу, пока жива блокировка.
+func (s *Singleton) lead(ctx context.Context, lock repository.LeaderLock) {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- s.worker.Run(runCtx) }()
+
+	ticker := time.NewTicker(singletonPing)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				s.logger.Error("%s: %v", s.name, err)
+			}
+			return
+		case <-ticker.C:
+			if err := lock.Ping(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Warn("leader %s: блокировка потеряна: %v", s.name, err)
+				cancel()
+				<-done
+				return
+			}
+		}
+	}
+}
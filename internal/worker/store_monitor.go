@@ -0,0 +1,201 @@
+package worker
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"victa/internal/bot/bot_common"
+	"victa/internal/bot/notification_bot"
+	"victa/internal/domain"
+	"victa/internal/logger"
+	"victa/internal/service"
+)
+
+// storeReviewsPerPoll — больше отзывов за один опрос не пересылаем,
+// чтобы после долгого простоя не завалить чат.
+const storeReviewsPerPoll = 10
+
+// StoreMonitor опрашивает магазины приложений: сообщает о новой
+// опубликованной версии и пересылает новые отзывы в чат отзывов.
+// Что уже показано, хранится в app_store_states и сохраняется после
+// каждого поставленного в очередь уведомления. При первом опросе
+// приложения монитор только запоминает текущее состояние.
+type StoreMonitor struct {
+	factory     *bot_common.BotFactory
+	logger      logger.Logger
+	companySvc  *service.CompanyService
+	appSvc      *service.AppService
+	storeSvc    *service.StoreService
+	outboxSvc   *service.OutboxService
+	identitySvc *service.IdentityService
+	interval    time.Duration
+}
+
+func NewStoreMonitor(
+	factory *bot_common.BotFactory,
+	logger logger.Logger,
+	companySvc *service.CompanyService,
+	appSvc *service.AppService,
+	storeSvc *service.StoreService,
+	outboxSvc *service.OutboxService,
+	identitySvc *service.IdentityService,
+) *StoreMonitor {
+	return &StoreMonitor{
+		factory:     factory,
+		logger:      logger,
+		companySvc:  companySvc,
+		appSvc:      appSvc,
+		storeSvc:    storeSvc,
+		outboxSvc:   outboxSvc,
+		identitySvc: identitySvc,
+		interval:    30 * time.Minute,
+	}
+}
+
+// WithInterval меняет период опроса магазинов.
+func (m *StoreMonitor) WithInterval(d time.Duration) *StoreMonitor {
+	m.interval = d
+	return m
+}
+
+// Run опрашивает магазины сразу и затем раз в interval, пока не отменят ctx.
+func (m *StoreMonitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *StoreMonitor) poll(ctx context.Context) {
+	states, err := m.storeSvc.GetAll(ctx)
+	if err != nil {
+		m.logger.Error("app store states: %v", err)
+		return
+	}
+
+	for _, st := range states {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := m.check(ctx, st); err != nil {
+			m.logger.Warn("store %s app %d: %v", st.StoreSlug, st.AppID, err)
+		}
+	}
+}
+
+func (m *StoreMonitor) check(ctx context.Context, st domain.AppStoreState) error {
+	client, ok := m.storeSvc.Client(st.StoreSlug)
+	if !ok {
+		return nil
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	version, err := client.LatestVersion(reqCtx, st.StoreAppID)
+	if err != nil {
+		return err
+	}
+	reviews, err := client.Reviews(reqCtx, st.StoreAppID)
+	if err != nil {
+		return err
+	}
+
+	fresh := m.newReviews(st, reviews)
+	versionChanged := version != "" && st.CheckedAt != nil &&
+		(st.LastVersion == nil || *st.LastVersion != version)
+
+	var bot *notification_bot.Bot
+	if versionChanged || len(fresh) > 0 {
+		if bot, err = m.newBot(ctx, st); err != nil {
+			return err
+		}
+	}
+
+	// прогресс сохраняем после каждого уведомления: если следующее не
+	// встанет в очередь, повторный опрос не задвоит уже отправленные
+	if versionChanged && bot != nil {
+		if err := bot.SendStoreVersionNotification(ctx, st, version); err != nil {
+			return err
+		}
+		st.LastVersion = &version
+		if err := m.storeSvc.SaveProgress(ctx, &st); err != nil {
+			return err
+		}
+	}
+
+	if bot != nil {
+		// в чате отзывы идут от старых к новым
+		for _, r := range slices.Backward(fresh) {
+			if err := bot.SendStoreReviewNotification(ctx, st, r); err != nil {
+				return err
+			}
+			st.LastReviewID = &r.ID
+			if err := m.storeSvc.SaveProgress(ctx, &st); err != nil {
+				return err
+			}
+		}
+	}
+
+	if version != "" {
+		st.LastVersion = &version
+	}
+	if len(reviews) > 0 {
+		st.LastReviewID = &reviews[0].ID
+	}
+	return m.storeSvc.SaveProgress(ctx, &st)
+}
+
+// newReviews возвращает отзывы новее последнего показанного, новые первыми.
+// Первый опрос ничего не пересылает.
+func (m *StoreMonitor) newReviews(st domain.AppStoreState, reviews []domain.StoreReview) []domain.StoreReview {
+	if st.CheckedAt == nil {
+		return nil
+	}
+	i := len(reviews)
+	if st.LastReviewID != nil {
+		if j := slices.IndexFunc(reviews, func(r domain.StoreReview) bool {
+			return r.ID == *st.LastReviewID
+		}); j >= 0 {
+			i = j
+		}
+	}
+	return reviews[:min(i, storeReviewsPerPoll)]
+}
+
+// newBot создаёт бота для чата отзывов приложения; nil — чат не настроен.
+func (m *StoreMonitor) newBot(ctx context.Context, st domain.AppStoreState) (*notification_bot.Bot, error) {
+	integration, err := m.companySvc.GetCompanyIntegrationByID(ctx, st.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	app, err := m.appSvc.FindIntegration(ctx, st.AppID)
+	if err != nil {
+		return nil, err
+	}
+	integration = integration.ForApp(app)
+
+	chatID := integration.ReviewsNotificationChatID
+	if integration.NotificationBotToken == nil || chatID == nil || *chatID == "" {
+		return nil, nil
+	}
+
+	baseBot, err := m.factory.GetBaseBot(*integration.NotificationBotToken, m.logger)
+	if err != nil {
+		return nil, err
+	}
+	bot, err := notification_bot.NewBot(baseBot, *chatID)
+	if err != nil {
+		return nil, err
+	}
+	return bot.WithOutbox(st.CompanyID, m.outboxSvc).WithIdentities(m.identitySvc), nil
+}
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE company_integrations
+    ADD COLUMN merge_requests_notification_chat_id TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE company_integrations
+    DROP COLUMN IF EXISTS merge_requests_notification_chat_id;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE company_integrations
+    ADD COLUMN pipelines_notification_chat_id TEXT,
+    ADD COLUMN gitlab_api_token TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE company_integrations
+    DROP COLUMN IF EXISTS pipelines_notification_chat_id,
+    DROP COLUMN IF EXISTS gitlab_api_token;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE company_integrations
+    ADD COLUMN github_webhook_secret TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE company_integrations
+    DROP COLUMN IF EXISTS github_webhook_secret;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE company_integrations
+    ADD COLUMN sentry_client_secret TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE company_integrations
+    DROP COLUMN IF EXISTS sentry_client_secret;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE build_messages
+(
+    build_id    TEXT PRIMARY KEY,
+    company_id  BIGINT    NOT NULL REFERENCES companies (id) ON DELETE CASCADE,
+    app_id      TEXT      NOT NULL,
+    chat_id     TEXT      NOT NULL,
+    message_id  BIGINT    NOT NULL,
+    status      TEXT      NOT NULL,
+    finished    BOOLEAN   NOT NULL DEFAULT FALSE,
+    created_at  TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_build_messages_unfinished ON build_messages (updated_at) WHERE NOT finished;
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS build_messages;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE notification_outbox
+(
+    id              BIGSERIAL PRIMARY KEY,
+    company_id      BIGINT    NOT NULL REFERENCES companies (id) ON DELETE CASCADE,
+    chat_id         TEXT      NOT NULL,
+    text            TEXT      NOT NULL,
+    build           JSONB NULL,
+    attempts        INT       NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT now(),
+    locked_until    TIMESTAMP NULL,
+    last_error      TEXT NULL,
+    created_at      TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_notification_outbox_next_attempt_at ON notification_outbox (next_attempt_at);
+
+CREATE TABLE notification_dead_letters
+(
+    id         BIGINT PRIMARY KEY,
+    company_id BIGINT    NOT NULL REFERENCES companies (id) ON DELETE CASCADE,
+    chat_id    TEXT      NOT NULL,
+    text       TEXT      NOT NULL,
+    build      JSONB NULL,
+    attempts   INT       NOT NULL,
+    last_error TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    failed_at  TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_notification_dead_letters_company_id ON notification_dead_letters (company_id);
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification_dead_letters;
+DROP TABLE IF EXISTS notification_outbox;
+-- +goose StatementEnd
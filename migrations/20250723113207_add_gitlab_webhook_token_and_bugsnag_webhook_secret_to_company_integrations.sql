@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE company_integrations
+    ADD COLUMN gitlab_webhook_token TEXT,
+    ADD COLUMN bugsnag_webhook_secret TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE company_integrations
+    DROP COLUMN IF EXISTS bugsnag_webhook_secret,
+    DROP COLUMN IF EXISTS gitlab_webhook_token;
+-- +goose StatementEnd
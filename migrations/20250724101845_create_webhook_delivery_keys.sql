@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE webhook_delivery_keys
+(
+    company_id   BIGINT    NOT NULL REFERENCES companies (id) ON DELETE CASCADE,
+    provider     TEXT      NOT NULL,
+    delivery_key TEXT      NOT NULL,
+    created_at   TIMESTAMP NOT NULL DEFAULT now(),
+    expires_at   TIMESTAMP NOT NULL,
+    PRIMARY KEY (company_id, provider, delivery_key)
+);
+
+CREATE INDEX idx_webhook_delivery_keys_expires_at ON webhook_delivery_keys (expires_at);
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_delivery_keys;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE webhook_deliveries
+(
+    id         BIGSERIAL PRIMARY KEY,
+    company_id BIGINT    NOT NULL REFERENCES companies (id) ON DELETE CASCADE,
+    provider   TEXT      NOT NULL,
+    headers    JSONB     NOT NULL DEFAULT '{}'::jsonb,
+    body       TEXT      NOT NULL,
+    status     INT       NOT NULL,
+    outcome    TEXT      NOT NULL,
+    error      TEXT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhook_deliveries_company_id_id ON webhook_deliveries (company_id, id DESC);
+CREATE INDEX idx_webhook_deliveries_created_at ON webhook_deliveries (created_at);
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE app_integrations
+    ALTER COLUMN codemagic_app_id DROP NOT NULL,
+    ADD COLUMN gitlab_project_id TEXT,
+    ADD COLUMN bugsnag_project_id TEXT,
+    ADD COLUMN deploy_notification_chat_id TEXT,
+    ADD COLUMN issues_notification_chat_id TEXT,
+    ADD COLUMN errors_notification_chat_id TEXT,
+    ADD COLUMN merge_requests_notification_chat_id TEXT,
+    ADD COLUMN pipelines_notification_chat_id TEXT;
+
+CREATE INDEX idx_app_integrations_codemagic_app_id ON app_integrations (codemagic_app_id);
+CREATE INDEX idx_app_integrations_gitlab_project_id ON app_integrations (gitlab_project_id);
+CREATE INDEX idx_app_integrations_bugsnag_project_id ON app_integrations (bugsnag_project_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_app_integrations_bugsnag_project_id;
+DROP INDEX IF EXISTS idx_app_integrations_gitlab_project_id;
+DROP INDEX IF EXISTS idx_app_integrations_codemagic_app_id;
+
+DELETE FROM app_integrations WHERE codemagic_app_id IS NULL;
+
+ALTER TABLE app_integrations
+    DROP COLUMN IF EXISTS pipelines_notification_chat_id,
+    DROP COLUMN IF EXISTS merge_requests_notification_chat_id,
+    DROP COLUMN IF EXISTS errors_notification_chat_id,
+    DROP COLUMN IF EXISTS issues_notification_chat_id,
+    DROP COLUMN IF EXISTS deploy_notification_chat_id,
+    DROP COLUMN IF EXISTS bugsnag_project_id,
+    DROP COLUMN IF EXISTS gitlab_project_id,
+    ALTER COLUMN codemagic_app_id SET NOT NULL;
+-- +goose StatementEnd
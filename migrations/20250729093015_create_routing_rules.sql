@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE routing_rules
+(
+    id           BIGSERIAL PRIMARY KEY,
+    company_id   BIGINT    NOT NULL REFERENCES companies (id) ON DELETE CASCADE,
+    position     INT       NOT NULL,
+    source       TEXT,
+    app_id       BIGINT REFERENCES apps (id) ON DELETE CASCADE,
+    branch       TEXT,
+    status       TEXT,
+    trigger_type TEXT,
+    severity     TEXT,
+    label        TEXT,
+    action       TEXT      NOT NULL,
+    chat_ids     TEXT[]    NOT NULL DEFAULT '{}',
+    created_at   TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT routing_rules_action_check CHECK (action IN ('send', 'mute'))
+);
+
+CREATE INDEX idx_routing_rules_company_position ON routing_rules (company_id, position);
+
+-- одно сообщение о билде на каждый чат, куда его разослали правила
+ALTER TABLE build_messages DROP CONSTRAINT build_messages_pkey;
+ALTER TABLE build_messages ADD PRIMARY KEY (build_id, chat_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM build_messages bm
+ USING build_messages newer
+ WHERE bm.build_id = newer.build_id
+   AND bm.updated_at < newer.updated_at;
+ALTER TABLE build_messages DROP CONSTRAINT build_messages_pkey;
+ALTER TABLE build_messages ADD PRIMARY KEY (build_id);
+
+DROP TABLE IF EXISTS routing_rules;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_identities
+(
+    user_id     BIGINT    NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider    TEXT      NOT NULL,
+    external_id TEXT      NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, provider),
+    CONSTRAINT user_identities_provider_external_id_key UNIQUE (provider, external_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_identities;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE company_integrations
+    ADD COLUMN bugsnag_api_token TEXT;
+
+ALTER TABLE notification_outbox
+    ADD COLUMN buttons JSONB NULL;
+
+ALTER TABLE notification_dead_letters
+    ADD COLUMN buttons JSONB NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notification_dead_letters
+    DROP COLUMN IF EXISTS buttons;
+
+ALTER TABLE notification_outbox
+    DROP COLUMN IF EXISTS buttons;
+
+ALTER TABLE company_integrations
+    DROP COLUMN IF EXISTS bugsnag_api_token;
+-- +goose StatementEnd
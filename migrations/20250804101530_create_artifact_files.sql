@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE company_integrations
+    ADD COLUMN upload_artifacts BOOLEAN;
+
+ALTER TABLE app_integrations
+    ADD COLUMN upload_artifacts BOOLEAN;
+
+ALTER TABLE notification_outbox
+    ADD COLUMN documents JSONB NULL;
+
+ALTER TABLE notification_dead_letters
+    ADD COLUMN documents JSONB NULL;
+
+CREATE TABLE artifact_files
+(
+    company_id    BIGINT    NOT NULL REFERENCES companies (id) ON DELETE CASCADE,
+    artifact_path TEXT      NOT NULL,
+    file_id       TEXT      NOT NULL,
+    file_name     TEXT      NOT NULL,
+    size          BIGINT    NOT NULL DEFAULT 0,
+    created_at    TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (company_id, artifact_path)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS artifact_files;
+
+ALTER TABLE notification_dead_letters
+    DROP COLUMN IF EXISTS documents;
+
+ALTER TABLE notification_outbox
+    DROP COLUMN IF EXISTS documents;
+
+ALTER TABLE app_integrations
+    DROP COLUMN IF EXISTS upload_artifacts;
+
+ALTER TABLE company_integrations
+    DROP COLUMN IF EXISTS upload_artifacts;
+-- +goose StatementEnd
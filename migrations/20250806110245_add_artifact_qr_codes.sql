@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE company_integrations
+    ADD COLUMN artifact_qr_codes BOOLEAN;
+
+ALTER TABLE notification_outbox
+    ADD COLUMN qr_codes JSONB NULL;
+
+ALTER TABLE notification_dead_letters
+    ADD COLUMN qr_codes JSONB NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notification_dead_letters
+    DROP COLUMN IF EXISTS qr_codes;
+
+ALTER TABLE notification_outbox
+    DROP COLUMN IF EXISTS qr_codes;
+
+ALTER TABLE company_integrations
+    DROP COLUMN IF EXISTS artifact_qr_codes;
+-- +goose StatementEnd
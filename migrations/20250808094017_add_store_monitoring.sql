@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE app_store_states
+    ADD COLUMN store_app_id TEXT NOT NULL DEFAULT '',
+    ALTER COLUMN last_version TYPE TEXT USING last_version::text,
+    ALTER COLUMN last_review_id TYPE TEXT USING last_review_id::text;
+
+ALTER TABLE app_store_states
+    ALTER COLUMN store_app_id DROP DEFAULT;
+
+ALTER TABLE company_integrations
+    ADD COLUMN reviews_notification_chat_id TEXT;
+
+ALTER TABLE app_integrations
+    ADD COLUMN reviews_notification_chat_id TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE app_integrations
+    DROP COLUMN IF EXISTS reviews_notification_chat_id;
+
+ALTER TABLE company_integrations
+    DROP COLUMN IF EXISTS reviews_notification_chat_id;
+
+ALTER TABLE app_store_states
+    DROP COLUMN IF EXISTS store_app_id,
+    ALTER COLUMN last_version TYPE BIGINT USING NULL,
+    ALTER COLUMN last_review_id TYPE BIGINT USING NULL;
+-- +goose StatementEnd
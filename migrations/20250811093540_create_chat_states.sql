@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE chat_states
+(
+    chat_id     BIGINT PRIMARY KEY,
+    state       INT,
+    message_ids JSONB     NOT NULL DEFAULT '[]',
+    company_id  BIGINT    NOT NULL DEFAULT 0,
+    app_id      BIGINT    NOT NULL DEFAULT 0,
+    app_name    TEXT      NOT NULL DEFAULT '',
+    app_slug    TEXT      NOT NULL DEFAULT '',
+    provider    TEXT      NOT NULL DEFAULT '',
+    expires_at  TIMESTAMP NOT NULL,
+    updated_at  TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_chat_states_expires_at ON chat_states (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS chat_states;
+-- +goose StatementEnd
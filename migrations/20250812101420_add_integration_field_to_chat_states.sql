@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE chat_states
+    ADD COLUMN integration_field TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE chat_states
+    DROP COLUMN IF EXISTS integration_field;
+-- +goose StatementEnd
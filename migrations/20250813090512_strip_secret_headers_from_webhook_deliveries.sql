@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+UPDATE webhook_deliveries
+   SET headers = headers - 'X-Gitlab-Token'
+ WHERE headers ? 'X-Gitlab-Token';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 1;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE pending_replies
+(
+    chat_id    BIGINT    NOT NULL,
+    message_id INT       NOT NULL,
+    action     TEXT      NOT NULL,
+    base_url   TEXT      NOT NULL,
+    tg_user_id BIGINT    NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (chat_id, message_id)
+);
+
+CREATE INDEX idx_pending_replies_expires_at ON pending_replies (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pending_replies;
+-- +goose StatementEnd
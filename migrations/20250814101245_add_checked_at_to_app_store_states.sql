@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE app_store_states
+    ADD COLUMN checked_at TIMESTAMP;
+
+-- приложения, которые монитор уже опрашивал
+UPDATE app_store_states
+   SET checked_at = updated_at
+ WHERE last_version IS NOT NULL
+    OR last_review_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE app_store_states
+    DROP COLUMN IF EXISTS checked_at;
+-- +goose StatementEnd
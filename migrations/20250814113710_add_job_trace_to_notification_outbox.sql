@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE notification_outbox
+    ADD COLUMN job_trace JSONB NULL;
+
+ALTER TABLE notification_dead_letters
+    ADD COLUMN job_trace JSONB NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notification_dead_letters
+    DROP COLUMN IF EXISTS job_trace;
+
+ALTER TABLE notification_outbox
+    DROP COLUMN IF EXISTS job_trace;
+-- +goose StatementEnd
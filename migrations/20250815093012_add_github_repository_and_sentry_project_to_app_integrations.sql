@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE app_integrations
+    ADD COLUMN github_repository TEXT,
+    ADD COLUMN sentry_project TEXT;
+
+CREATE INDEX idx_app_integrations_github_repository ON app_integrations (github_repository);
+CREATE INDEX idx_app_integrations_sentry_project ON app_integrations (sentry_project);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_app_integrations_sentry_project;
+DROP INDEX IF EXISTS idx_app_integrations_github_repository;
+
+ALTER TABLE app_integrations
+    DROP COLUMN IF EXISTS sentry_project,
+    DROP COLUMN IF EXISTS github_repository;
+-- +goose StatementEnd